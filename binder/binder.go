@@ -11,6 +11,7 @@ import (
 	"math"
 	"net/http"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -59,6 +60,23 @@ type DefaultBinder struct {
 	BodyDecoder      BodyDecoder
 	ReadTimeout      time.Duration
 	StrictJSONBodies bool
+	Validator        Validator
+	// Converters parse bound string values for specific types, e.g. UUIDs
+	// that do not implement encoding.TextUnmarshaler.
+	Converters map[reflect.Type]Converter
+	// BodySchema, when set, validates JSON bodies before they are decoded.
+	BodySchema BodySchema
+	// Strict rejects query parameters and headers no field declares.
+	Strict StrictParams
+	// Modifiers adds or replaces `mod` tag modifiers, e.g. a Unicode
+	// normalization form backed by golang.org/x/text.
+	Modifiers map[string]Modifier
+}
+
+// BodySchema validates a raw request body, reporting violations as body
+// field errors. schema.Schema implements it.
+type BodySchema interface {
+	ValidateBody(data []byte) []FieldError
 }
 
 func NewDefaultBinder() *DefaultBinder {
@@ -82,6 +100,32 @@ func (b *DefaultBinder) SetStrictJSONBodies(strict bool) {
 	b.StrictJSONBodies = strict
 }
 
+// WithValidator returns a copy that validates bound structs with v.
+func (b *DefaultBinder) WithValidator(v Validator) *DefaultBinder {
+	copy := *b
+	copy.Validator = v
+	return &copy
+}
+
+// SetValidator sets the validator in place.
+func (b *DefaultBinder) SetValidator(v Validator) {
+	b.Validator = v
+}
+
+// WithMaxBodyBytes returns a copy that rejects bodies larger than n bytes.
+func (b *DefaultBinder) WithMaxBodyBytes(n int64) *DefaultBinder {
+	copy := *b
+	copy.MaxBodyBytes = n
+	return &copy
+}
+
+// WithBodySchema returns a copy that validates JSON bodies against s.
+func (b *DefaultBinder) WithBodySchema(s BodySchema) *DefaultBinder {
+	copy := *b
+	copy.BodySchema = s
+	return &copy
+}
+
 func (b *DefaultBinder) bodyDecoder() BodyDecoder {
 	if b.BodyDecoder == nil {
 		return JSONBodyDecoder{DisallowUnknown: b.StrictJSONBodies}
@@ -121,6 +165,9 @@ type FieldError struct {
 	Field   string      `json:"field"`
 	Source  FieldSource `json:"source"`
 	Message string      `json:"message"`
+	// Code names the failed validation rule, e.g. "required" or "uuid", so
+	// clients can translate the message.
+	Code string `json:"code,omitempty"`
 }
 
 // NewFieldError is a helper for constructing field-level errors.
@@ -234,7 +281,10 @@ func (e *BindError) WireData() any {
 // ErrBodyTooLarge is returned when the request body exceeds MaxBodyBytes.
 var ErrBodyTooLarge = errors.New("request body exceeds binder limit")
 
-var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
 
 // Bind populates dest based on struct tags.
 func (b *DefaultBinder) Bind(ctx context.Context, r *http.Request, dest any) error {
@@ -261,6 +311,7 @@ func (b *DefaultBinder) Bind(ctx context.Context, r *http.Request, dest any) err
 		pathParams: collectPathParams(r),
 		query:      collectQueryValues(r),
 		cookies:    collectCookies(r),
+		binding:    map[reflect.Type]bool{rv.Type(): true},
 	}
 
 	var bodyOnce sync.Once
@@ -273,16 +324,40 @@ func (b *DefaultBinder) Bind(ctx context.Context, r *http.Request, dest any) err
 		return bodyData, bodyErr
 	}
 
+	if unknown := b.Strict.unknownParams(rv.Type(), info); len(unknown) > 0 {
+		return &BindError{
+			message: "Unknown request parameters",
+			fields:  unknown,
+		}
+	}
+
 	var fieldErrors []FieldError
 	if err := b.bindStruct(ctx, rv, "", info, &fieldErrors, getBody); err != nil {
 		return err
 	}
+	if err := b.applyModifiers(rv); err != nil {
+		return err
+	}
 	if len(fieldErrors) > 0 {
+		// Report validation failures of the fields that did bind as well, so
+		// clients see every problem at once.
+		if b.Validator != nil {
+			if err := b.Validator.Validate(ctx, dest); err != nil {
+				var bindErr *BindError
+				if !errors.As(err, &bindErr) {
+					return err
+				}
+				fieldErrors = mergeFieldErrors(fieldErrors, bindErr.fields)
+			}
+		}
 		return &BindError{
 			message: "Request failed validation",
 			fields:  fieldErrors,
 		}
 	}
+	if b.Validator != nil {
+		return b.Validator.Validate(ctx, dest)
+	}
 	return nil
 }
 
@@ -291,6 +366,9 @@ type requestInfo struct {
 	pathParams map[string]string
 	query      map[string][]string
 	cookies    map[string]string
+	// binding holds the struct types being bound, so a type embedding a
+	// pointer to itself is not allocated without end.
+	binding map[reflect.Type]bool
 }
 
 type bodyLoader func() ([]byte, error)
@@ -315,54 +393,88 @@ func (b *DefaultBinder) bindStruct(
 			name = parent + "." + name
 		}
 
-		// Anonymous struct fields cascade.
+		// Anonymous struct fields cascade; embedded pointers are allocated so
+		// handlers can use promoted fields without nil checks.
 		if fieldType.Anonymous && field.Kind() == reflect.Struct && !hasBindingTag(fieldType) {
 			if err := b.bindStruct(ctx, field, parent, info, fieldErrors, getBody); err != nil {
 				return err
 			}
 			continue
 		}
+		if fieldType.Anonymous && isStructPointer(field.Type()) && !hasBindingTag(fieldType) {
+			elem := field.Type().Elem()
+			if info.binding[elem] {
+				continue
+			}
+			if field.IsNil() {
+				field.Set(reflect.New(elem))
+			}
+			info.binding[elem] = true
+			err := b.bindStruct(ctx, field.Elem(), parent, info, fieldErrors, getBody)
+			delete(info.binding, elem)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if sources := fallbackSources(fieldType); sources != nil {
+			b.bindFallback(field, fieldType, name, sources, info, fieldErrors)
+			continue
+		}
 
 		if source, ok := fieldType.Tag.Lookup("path"); ok {
 			key := firstNonEmpty(source, fieldType.Name)
 			val, found := info.pathParams[key]
 			if !found {
-				if required(fieldType) {
-					appendFieldError(fieldErrors, FieldError{Field: name, Source: SourcePath, Message: "missing required value"})
-				}
+				bindMissing(field, fieldType, name, SourcePath, b.conversion(fieldType), fieldErrors)
 				continue
 			}
-			if err := assignFromStrings(field, []string{val}); err != nil {
+			if err := assignFromStrings(field, []string{val}, b.conversion(fieldType)); err != nil {
 				appendFieldError(fieldErrors, FieldError{Field: name, Source: SourcePath, Message: err.Error()})
 			}
 			continue
 		}
 
 		if source, ok := fieldType.Tag.Lookup("query"); ok {
-			key := firstNonEmpty(source, fieldType.Name)
-			values := info.query[key]
-			if len(values) == 0 {
-				if required(fieldType) {
-					appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceQuery, Message: "missing required value"})
+			tag := parseQueryTag(source, fieldType)
+			conv := b.conversion(fieldType)
+			if tag.all {
+				bindAll(field, info.query, name, SourceQuery, conv, fieldErrors)
+				continue
+			}
+			if isDeepObject(fieldType.Type, conv) {
+				if node := parseDeepObject(info.query, tag.name); node != nil {
+					bindDeepObject(field, node, name, conv, fieldErrors)
+				} else {
+					bindMissing(field, fieldType, name, SourceQuery, conv, fieldErrors)
 				}
 				continue
 			}
-			if err := assignFromStrings(field, values); err != nil {
+			values := tag.values(info.query)
+			if len(values) == 0 {
+				bindMissing(field, fieldType, name, SourceQuery, conv, fieldErrors)
+				continue
+			}
+			if err := assignFromStrings(field, values, conv); err != nil {
 				appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceQuery, Message: err.Error()})
 			}
 			continue
 		}
 
 		if source, ok := fieldType.Tag.Lookup("header"); ok {
-			key := http.CanonicalHeaderKey(firstNonEmpty(source, fieldType.Name))
+			key, opts, _ := strings.Cut(source, ",")
+			if hasTagOption(opts, "all") {
+				bindAll(field, info.request.Header, name, SourceHeader, b.conversion(fieldType), fieldErrors)
+				continue
+			}
+			key = http.CanonicalHeaderKey(firstNonEmpty(key, fieldType.Name))
 			values := info.request.Header.Values(key)
 			if len(values) == 0 {
-				if required(fieldType) {
-					appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceHeader, Message: "missing required value"})
-				}
+				bindMissing(field, fieldType, name, SourceHeader, b.conversion(fieldType), fieldErrors)
 				continue
 			}
-			if err := assignFromStrings(field, values); err != nil {
+			if err := assignFromStrings(field, values, b.conversion(fieldType)); err != nil {
 				appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceHeader, Message: err.Error()})
 			}
 			continue
@@ -371,16 +483,22 @@ func (b *DefaultBinder) bindStruct(
 		if source, ok := fieldType.Tag.Lookup("cookie"); ok {
 			key := firstNonEmpty(source, fieldType.Name)
 			if val, ok := info.cookies[key]; ok {
-				if err := assignFromStrings(field, []string{val}); err != nil {
+				if err := assignFromStrings(field, []string{val}, b.conversion(fieldType)); err != nil {
 					appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceCookie, Message: err.Error()})
 				}
-			} else if required(fieldType) {
-				appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceCookie, Message: "missing required value"})
+			} else {
+				bindMissing(field, fieldType, name, SourceCookie, b.conversion(fieldType), fieldErrors)
 			}
 			continue
 		}
 
 		if _, ok := fieldType.Tag.Lookup("body"); ok {
+			if isNDJSONRequest(info.request) {
+				if err := b.bindNDJSON(info.request, field, name); err != nil {
+					return err
+				}
+				continue
+			}
 			data, err := getBody()
 			if err != nil {
 				if errors.Is(err, ErrBodyTooLarge) {
@@ -404,9 +522,37 @@ func (b *DefaultBinder) bindStruct(
 			if len(data) == 0 {
 				if required(fieldType) {
 					appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceBody, Message: "missing required value"})
+					continue
+				}
+				def, ok := fieldType.Tag.Lookup("default")
+				if !ok {
+					continue
+				}
+				// Body defaults are written in the body's own encoding.
+				data = []byte(def)
+			}
+			if isFormRequest(info.request) {
+				formErrors, err := decodeForm(data, field, name, b.conversion(fieldType))
+				if err != nil {
+					return &BindError{
+						message: "Failed to decode request body",
+						fields:  []FieldError{NewFieldError(name, SourceBody, err.Error())},
+						cause:   err,
+					}
+				}
+				for _, fe := range formErrors {
+					appendFieldError(fieldErrors, fe)
 				}
 				continue
 			}
+			if b.BodySchema != nil {
+				if violations := b.BodySchema.ValidateBody(data); len(violations) > 0 {
+					return &BindError{
+						message: "Request body does not match schema",
+						fields:  violations,
+					}
+				}
+			}
 			target := field
 			if target.Kind() != reflect.Pointer {
 				target = target.Addr()
@@ -497,6 +643,83 @@ func required(field reflect.StructField) bool {
 	return req == "true" || req == "1" || req == "yes"
 }
 
+// bindMissing handles a value absent from its source: required fields fail,
+// and fields with a `default` tag receive the default parsed like a request
+// value. Slice defaults are comma-separated.
+func bindMissing(field reflect.Value, fieldType reflect.StructField, name string, source FieldSource, conv conversion, fieldErrors *[]FieldError) {
+	if required(fieldType) {
+		appendFieldError(fieldErrors, FieldError{Field: name, Source: source, Message: "missing required value"})
+		return
+	}
+	def, ok := fieldType.Tag.Lookup("default")
+	if !ok {
+		return
+	}
+	if err := assignFromStrings(field, defaultValues(field.Type(), def, conv), conv); err != nil {
+		appendFieldError(fieldErrors, FieldError{Field: name, Source: source, Message: "invalid default: " + err.Error()})
+	}
+}
+
+func defaultValues(typ reflect.Type, def string, conv conversion) []string {
+	if _, ok := conv.converters[typ]; ok {
+		return []string{def}
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Slice && def != "" {
+		parts := strings.Split(def, ",")
+		for i, part := range parts {
+			parts[i] = strings.TrimSpace(part)
+		}
+		return parts
+	}
+	return []string{def}
+}
+
+// bindAll collects every value of a source into a map field keyed by
+// parameter or canonical header name. Fields bound individually still receive
+// their values; the map sees everything the client sent.
+func bindAll(field reflect.Value, values map[string][]string, name string, source FieldSource, conv conversion, fieldErrors *[]FieldError) {
+	mt := field.Type()
+	if mt.Kind() != reflect.Map || mt.Key().Kind() != reflect.String {
+		appendFieldError(fieldErrors, FieldError{Field: name, Source: source, Message: "all option requires a map with string keys"})
+		return
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	out := reflect.MakeMapWithSize(mt, len(keys))
+	for _, key := range keys {
+		if len(values[key]) == 0 {
+			continue
+		}
+		elem := reflect.New(mt.Elem()).Elem()
+		if err := assignFromStrings(elem, values[key], conv); err != nil {
+			appendFieldError(fieldErrors, FieldError{Field: name + "[" + key + "]", Source: source, Message: err.Error()})
+			continue
+		}
+		out.SetMapIndex(reflect.ValueOf(key).Convert(mt.Key()), elem)
+	}
+	field.Set(out)
+}
+
+// hasTagOption reports whether the comma-separated opts contain opt.
+func hasTagOption(opts, opt string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if strings.TrimSpace(o) == opt {
+			return true
+		}
+	}
+	return false
+}
+
+func isStructPointer(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Pointer && typ.Elem().Kind() == reflect.Struct
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, v := range values {
 		if v != "" {
@@ -516,19 +739,27 @@ func hasBindingTag(field reflect.StructField) bool {
 	return false
 }
 
-func assignFromStrings(field reflect.Value, values []string) error {
+func assignFromStrings(field reflect.Value, values []string, conv conversion) error {
+	if _, ok := conv.converters[field.Type()]; ok && len(values) > 0 {
+		val, err := convertString(values[0], field.Type(), conv)
+		if err != nil {
+			return err
+		}
+		field.Set(val)
+		return nil
+	}
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
 			field.Set(reflect.New(field.Type().Elem()))
 		}
-		return assignFromStrings(field.Elem(), values)
+		return assignFromStrings(field.Elem(), values, conv)
 	}
 
 	if field.Kind() == reflect.Slice {
 		elemType := field.Type().Elem()
 		slice := reflect.MakeSlice(field.Type(), 0, len(values))
 		for _, v := range values {
-			val, err := convertString(v, elemType)
+			val, err := convertString(v, elemType, conv)
 			if err != nil {
 				return err
 			}
@@ -542,7 +773,7 @@ func assignFromStrings(field reflect.Value, values []string) error {
 		return fmt.Errorf("no value provided")
 	}
 
-	val, err := convertString(values[0], field.Type())
+	val, err := convertString(values[0], field.Type(), conv)
 	if err != nil {
 		return err
 	}
@@ -550,7 +781,25 @@ func assignFromStrings(field reflect.Value, values []string) error {
 	return nil
 }
 
-func convertString(input string, typ reflect.Type) (reflect.Value, error) {
+func convertString(input string, typ reflect.Type, conv conversion) (reflect.Value, error) {
+	if fn, ok := conv.converters[typ]; ok {
+		out, err := fn(input)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		val := reflect.ValueOf(out)
+		if !val.IsValid() || !val.Type().AssignableTo(typ) {
+			return reflect.Value{}, fmt.Errorf("converter for %s returned %T", typ, out)
+		}
+		return val, nil
+	}
+	if typ == durationType {
+		d, err := time.ParseDuration(input)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("expected duration such as 30s or 1h30m")
+		}
+		return reflect.ValueOf(d), nil
+	}
 	switch typ.Kind() {
 	case reflect.String:
 		return reflect.ValueOf(input).Convert(typ), nil
@@ -588,10 +837,10 @@ func convertString(input string, typ reflect.Type) (reflect.Value, error) {
 		v.SetFloat(f)
 		return v, nil
 	case reflect.Struct:
-		if typ == reflect.TypeOf(time.Time{}) {
-			t, err := time.Parse(time.RFC3339, input)
+		if typ == timeType {
+			t, err := parseTime(input, conv.format)
 			if err != nil {
-				return reflect.Value{}, fmt.Errorf("expected RFC3339 timestamp")
+				return reflect.Value{}, err
 			}
 			return reflect.ValueOf(t), nil
 		}
@@ -629,6 +878,11 @@ func (b *DefaultBinder) readBody(ctx context.Context, r *http.Request) ([]byte,
 	reader := io.LimitReader(r.Body, limit+1)
 	data, err := readAllWithContext(ctx, reader, b.ReadTimeout)
 	if err != nil {
+		// An http.MaxBytesReader installed before binding may trip first.
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, ErrBodyTooLarge
+		}
 		return nil, err
 	}
 	if int64(len(data)) > limit {
@@ -3,12 +3,15 @@ package binder_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	framework "github.com/aatuh/pureapi-framework"
+	"github.com/aatuh/pureapi-framework/binder"
 )
 
 type binderTestBody struct {
@@ -126,3 +129,58 @@ func TestDefaultBinder_RequiredFieldMissing(t *testing.T) {
 		t.Fatalf("expected field errors in response")
 	}
 }
+
+type defaultsInput struct {
+	Limit  int           `query:"limit" default:"20"`
+	Sort   string        `query:"sort" default:"-created_at"`
+	Tags   []string      `query:"tag" default:"new, sale"`
+	Region string        `header:"X-Region" default:"eu"`
+	Wait   time.Duration `cookie:"wait" default:"5s"`
+	Filter struct {
+		Status string `json:"status"`
+	} `body:"" default:"{\"status\":\"active\"}"`
+}
+
+func TestDefaultBinder_AppliesDefaults(t *testing.T) {
+	b := binder.NewDefaultBinder()
+
+	var in defaultsInput
+	req := httptest.NewRequest(http.MethodGet, "/?sort=name", nil)
+	if err := b.Bind(context.Background(), req, &in); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	if in.Limit != 20 || in.Sort != "name" || in.Region != "eu" || in.Wait != 5*time.Second {
+		t.Fatalf("unexpected defaults: %+v", in)
+	}
+	if len(in.Tags) != 2 || in.Tags[0] != "new" || in.Tags[1] != "sale" {
+		t.Fatalf("unexpected slice default: %v", in.Tags)
+	}
+	if in.Filter.Status != "active" {
+		t.Fatalf("expected body default, got %+v", in.Filter)
+	}
+
+	var bad struct {
+		Limit int `query:"limit" default:"many"`
+	}
+	err := b.Bind(context.Background(), httptest.NewRequest(http.MethodGet, "/", nil), &bad)
+	var bindErr *binder.BindError
+	if !errors.As(err, &bindErr) || len(bindErr.Fields()) != 1 || bindErr.Fields()[0].Field != "Limit" {
+		t.Fatalf("expected field error for invalid default, got %v", err)
+	}
+}
+
+type SelfEmbeddingInput struct {
+	*SelfEmbeddingInput
+	Name string `query:"name" validate:"required" mod:"trim"`
+}
+
+func TestDefaultBinder_SelfEmbeddingPointer(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?name=+x+", nil)
+	var dst SelfEmbeddingInput
+	if err := binder.NewDefaultBinder().Bind(context.Background(), req, &dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "x" || dst.SelfEmbeddingInput != nil {
+		t.Fatalf("unexpected binding: %+v", dst)
+	}
+}
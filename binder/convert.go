@@ -0,0 +1,127 @@
+package binder
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Converter parses a bound path, query, header, cookie, or form value into a
+// value of the type it is registered for.
+type Converter func(input string) (any, error)
+
+// ConverterFor adapts a typed parse function into a Converter keyed by T.
+func ConverterFor[T any](fn func(string) (T, error)) (reflect.Type, Converter) {
+	return reflect.TypeFor[T](), func(input string) (any, error) {
+		return fn(input)
+	}
+}
+
+// WithConverter returns a copy that converts values of typ with fn.
+func (b *DefaultBinder) WithConverter(typ reflect.Type, fn Converter) *DefaultBinder {
+	copy := *b
+	copy.Converters = make(map[reflect.Type]Converter, len(b.Converters)+1)
+	for k, v := range b.Converters {
+		copy.Converters[k] = v
+	}
+	copy.Converters[typ] = fn
+	return &copy
+}
+
+// SetConverter registers fn for typ in place.
+func (b *DefaultBinder) SetConverter(typ reflect.Type, fn Converter) {
+	if b.Converters == nil {
+		b.Converters = make(map[reflect.Type]Converter)
+	}
+	b.Converters[typ] = fn
+}
+
+// conversion carries the binder converters and the field's `format` tag.
+type conversion struct {
+	converters map[reflect.Type]Converter
+	format     string
+}
+
+func (b *DefaultBinder) conversion(field reflect.StructField) conversion {
+	return conversion{converters: b.Converters, format: field.Tag.Get("format")}
+}
+
+func (c conversion) withField(field reflect.StructField) conversion {
+	c.format = field.Tag.Get("format")
+	return c
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Date is a calendar date bound and encoded as 2006-01-02.
+type Date struct {
+	time.Time
+}
+
+// DateLayout is the wire layout of Date.
+const DateLayout = "2006-01-02"
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.Format(DateLayout)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := time.Parse(DateLayout, string(text))
+	if err != nil {
+		return fmt.Errorf("expected date in %s format", DateLayout)
+	}
+	d.Time = t
+	return nil
+}
+
+// MarshalJSON encodes the date as a JSON string.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.Format(DateLayout))), nil
+}
+
+// UnmarshalJSON decodes a JSON date string.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("expected date string")
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// parseTime applies a `format` tag: unix, unixmilli, unixmicro, unixnano,
+// date, rfc3339 (the default), or any time layout.
+func parseTime(input, format string) (time.Time, error) {
+	unix := func(scale func(int64) time.Time) (time.Time, error) {
+		n, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("expected %s timestamp", format)
+		}
+		return scale(n).UTC(), nil
+	}
+	switch format {
+	case "", "rfc3339":
+		t, err := time.Parse(time.RFC3339, input)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("expected RFC3339 timestamp")
+		}
+		return t, nil
+	case "unix":
+		return unix(func(n int64) time.Time { return time.Unix(n, 0) })
+	case "unixmilli":
+		return unix(time.UnixMilli)
+	case "unixmicro":
+		return unix(time.UnixMicro)
+	case "unixnano":
+		return unix(func(n int64) time.Time { return time.Unix(0, n) })
+	case "date":
+		format = DateLayout
+	}
+	t, err := time.Parse(format, input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected time in %s format", format)
+	}
+	return t, nil
+}
@@ -0,0 +1,64 @@
+package binder_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+type userID [2]string
+
+func parseUserID(s string) (userID, error) {
+	prefix, rest, ok := strings.Cut(s, "_")
+	if !ok {
+		return userID{}, errors.New("expected prefix_id")
+	}
+	return userID{prefix, rest}, nil
+}
+
+type conversionInput struct {
+	Since   time.Time     `query:"since" format:"unix"`
+	Until   time.Time     `query:"until" format:"unixmilli"`
+	Day     time.Time     `query:"day" format:"date"`
+	Stamp   time.Time     `query:"stamp" format:"02/01/2006"`
+	Default time.Time     `query:"default"`
+	Timeout time.Duration `query:"timeout"`
+	Date    binder.Date   `query:"date"`
+	User    userID        `header:"X-User"`
+}
+
+func TestDefaultBinder_ConvertsFormatsDurationsAndCustomTypes(t *testing.T) {
+	b := binder.NewDefaultBinder().WithConverter(binder.ConverterFor(parseUserID))
+	req := httptest.NewRequest("GET", "/?since=1700000000&until=1700000000123&day=2024-02-29&stamp=31/12/2023&default=2024-01-01T10:00:00Z&timeout=1m30s&date=2024-03-01", nil)
+	req.Header.Set("X-User", "usr_42")
+
+	var in conversionInput
+	if err := b.Bind(context.Background(), req, &in); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	if !in.Since.Equal(time.Unix(1700000000, 0)) || !in.Until.Equal(time.UnixMilli(1700000000123)) {
+		t.Fatalf("unexpected unix times %v %v", in.Since, in.Until)
+	}
+	if in.Day.Format("2006-01-02") != "2024-02-29" || in.Stamp.Format("2006-01-02") != "2023-12-31" {
+		t.Fatalf("unexpected layouts %v %v", in.Day, in.Stamp)
+	}
+	if in.Default.Hour() != 10 || in.Timeout != 90*time.Second || in.Date.Format(binder.DateLayout) != "2024-03-01" {
+		t.Fatalf("unexpected values %+v", in)
+	}
+	if in.User != (userID{"usr", "42"}) {
+		t.Fatalf("unexpected converted user %v", in.User)
+	}
+
+	req = httptest.NewRequest("GET", "/?timeout=soon", nil)
+	req.Header.Set("X-User", "bad")
+	err := b.Bind(context.Background(), req, &conversionInput{})
+	var bindErr *binder.BindError
+	if !errors.As(err, &bindErr) || len(bindErr.Fields()) != 2 {
+		t.Fatalf("expected two field errors, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package binder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var dbFieldsCache sync.Map // reflect.Type -> dbFieldsResult
+
+type dbFieldsResult struct {
+	fields map[string]string
+	err    error
+}
+
+// DeriveAPIToDBFields maps the API names of Entity's fields to their database
+// columns, for use with MergePatch.Updates and the CRUD setup configs. The
+// column comes from the `db` tag and the API name from the `json` tag, or an
+// `api` tag overriding it. Fields without a db tag, or tagged "-", are left
+// out; embedded structs without a json name are flattened. Two fields sharing
+// an API name or a column are an error. Results are cached per type.
+func DeriveAPIToDBFields[Entity any]() (map[string]string, error) {
+	t := reflect.TypeFor[Entity]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("derive api to db fields: %s is not a struct", t)
+	}
+	cached, ok := dbFieldsCache.Load(t)
+	if !ok {
+		fields := map[string]string{}
+		err := collectDBFields(t, fields, map[string]string{}, map[reflect.Type]bool{})
+		if err != nil {
+			err = fmt.Errorf("derive api to db fields for %s: %w", t, err)
+		}
+		cached, _ = dbFieldsCache.LoadOrStore(t, dbFieldsResult{fields: fields, err: err})
+	}
+	result := cached.(dbFieldsResult)
+	if result.err != nil {
+		return nil, result.err
+	}
+	out := make(map[string]string, len(result.fields))
+	for api, column := range result.fields {
+		out[api] = column
+	}
+	return out, nil
+}
+
+// MustDeriveAPIToDBFields is like DeriveAPIToDBFields but panics on error.
+func MustDeriveAPIToDBFields[Entity any]() map[string]string {
+	fields, err := DeriveAPIToDBFields[Entity]()
+	if err != nil {
+		panic(err)
+	}
+	return fields
+}
+
+// collectDBFields adds the mapped fields of t to fields; columns tracks the
+// API name already using each column.
+func collectDBFields(t reflect.Type, fields, columns map[string]string, seen map[reflect.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && jsonName == "" && field.Tag.Get("db") == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := collectDBFields(embedded, fields, columns, seen); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		column, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+		if column == "" || column == "-" {
+			continue
+		}
+		api, _, _ := strings.Cut(field.Tag.Get("api"), ",")
+		if api == "" {
+			api = jsonName
+		}
+		if api == "-" {
+			continue
+		}
+		api = firstNonEmpty(api, field.Name)
+		if existing, ok := fields[api]; ok {
+			return fmt.Errorf("api field %q maps to both %q and %q", api, existing, column)
+		}
+		if existing, ok := columns[column]; ok {
+			return fmt.Errorf("column %q is mapped by both %q and %q", column, existing, api)
+		}
+		fields[api] = column
+		columns[column] = api
+	}
+	return nil
+}
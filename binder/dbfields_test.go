@@ -0,0 +1,58 @@
+package binder
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type dbAudit struct {
+	CreatedAt string `json:"created_at" db:"created_at"`
+}
+
+type dbUser struct {
+	dbAudit
+	ID       int    `json:"id" db:"id"`
+	Name     string `json:"name" db:"full_name"`
+	Email    string `json:"email" api:"contact" db:"email"`
+	Password string `json:"-" db:"password_hash"`
+	Secret   string `json:"secret" api:"-" db:"secret"`
+	Computed string `json:"computed"`
+	Plain    int    `db:"plain"`
+}
+
+type dbCollision struct {
+	Name  string `json:"name" db:"name"`
+	Alias string `json:"alias" db:"name"`
+}
+
+func TestDeriveAPIToDBFields(t *testing.T) {
+	fields, err := DeriveAPIToDBFields[dbUser]()
+	if err != nil {
+		t.Fatalf("derive: %v", err)
+	}
+	want := map[string]string{
+		"created_at": "created_at",
+		"id":         "id",
+		"name":       "full_name",
+		"contact":    "email",
+		"Plain":      "plain",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("unexpected fields:\n got %v\nwant %v", fields, want)
+	}
+
+	fields["id"] = "changed"
+	if again := MustDeriveAPIToDBFields[*dbUser](); again["id"] != "id" {
+		t.Fatalf("expected cached result to be copied, got %v", again)
+	}
+}
+
+func TestDeriveAPIToDBFields_RejectsCollisions(t *testing.T) {
+	if _, err := DeriveAPIToDBFields[dbCollision](); err == nil || !strings.Contains(err.Error(), `column "name"`) {
+		t.Fatalf("expected column collision, got %v", err)
+	}
+	if _, err := DeriveAPIToDBFields[string](); err == nil {
+		t.Fatalf("expected error for non-struct type")
+	}
+}
@@ -0,0 +1,198 @@
+package binder
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewRequest builds the HTTP request that binds to in: the inverse of
+// DefaultBinder.Bind. Path tags fill {name} placeholders in pattern, query,
+// header, and cookie tags set non-zero values, and the body field is JSON
+// encoded. The returned request targets the relative path.
+func NewRequest(ctx context.Context, method, pattern string, in any) (*http.Request, error) {
+	enc := &requestEncoder{path: pattern, query: url.Values{}, header: http.Header{}}
+	if err := enc.encode(reflect.ValueOf(in)); err != nil {
+		return nil, err
+	}
+	target := enc.path
+	if len(enc.query) > 0 {
+		target += "?" + enc.query.Encode()
+	}
+	var body io.Reader
+	if enc.body != nil {
+		body = bytes.NewReader(enc.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, values := range enc.header {
+		req.Header[k] = values
+	}
+	for _, cookie := range enc.cookies {
+		req.AddCookie(cookie)
+	}
+	if enc.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+type requestEncoder struct {
+	path    string
+	query   url.Values
+	header  http.Header
+	cookies []*http.Cookie
+	body    []byte
+}
+
+func (e *requestEncoder) encode(v reflect.Value) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fieldType, field := t.Field(i), v.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+		if fieldType.Anonymous && (field.Kind() == reflect.Struct || isStructPointer(field.Type())) && !hasBindingTag(fieldType) {
+			if err := e.encode(field); err != nil {
+				return err
+			}
+			continue
+		}
+		if key, ok := fieldType.Tag.Lookup("path"); ok {
+			placeholder := "{" + firstNonEmpty(key, fieldType.Name) + "}"
+			e.path = strings.ReplaceAll(e.path, placeholder, url.PathEscape(formatValue(field, fieldType.Tag.Get("format"))))
+			continue
+		}
+		if tag, ok := fieldType.Tag.Lookup("query"); ok {
+			encodeQuery(e.query, parseQueryTag(tag, fieldType), field, fieldType.Tag.Get("format"))
+			continue
+		}
+		if key, ok := fieldType.Tag.Lookup("header"); ok {
+			key, opts, _ := strings.Cut(key, ",")
+			if hasTagOption(opts, "all") {
+				encodeAll(field, fieldType.Tag.Get("format"), e.header.Add)
+				continue
+			}
+			for _, s := range formatValues(field, fieldType.Tag.Get("format")) {
+				e.header.Add(http.CanonicalHeaderKey(firstNonEmpty(key, fieldType.Name)), s)
+			}
+			continue
+		}
+		if key, ok := fieldType.Tag.Lookup("cookie"); ok {
+			if !field.IsZero() {
+				e.cookies = append(e.cookies, &http.Cookie{Name: firstNonEmpty(key, fieldType.Name), Value: formatValue(field, fieldType.Tag.Get("format"))})
+			}
+			continue
+		}
+		if _, ok := fieldType.Tag.Lookup("body"); ok {
+			if field.Kind() == reflect.Pointer && field.IsNil() {
+				continue
+			}
+			data, err := json.Marshal(field.Interface())
+			if err != nil {
+				return fmt.Errorf("encode %s body: %w", fieldType.Name, err)
+			}
+			e.body = data
+			continue
+		}
+		if field.Kind() == reflect.Struct && fieldType.Tag == "" {
+			if err := e.encode(field); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// encodeAll writes each entry of a map bound with the `all` tag option.
+func encodeAll(v reflect.Value, format string, add func(key, value string)) {
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return
+	}
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	for _, key := range keys {
+		for _, s := range formatValues(v.MapIndex(key), format) {
+			add(key.String(), s)
+		}
+	}
+}
+
+func formatValues(v reflect.Value, format string) []string {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		out := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out = append(out, formatValue(v.Index(i), format))
+		}
+		return out
+	}
+	if v.IsZero() {
+		return nil
+	}
+	return []string{formatValue(v, format)}
+}
+
+func formatValue(v reflect.Value, format string) string {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return formatTime(t, format)
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// formatTime is the inverse of parseTime.
+func formatTime(t time.Time, format string) string {
+	switch format {
+	case "", "rfc3339":
+		return t.Format(time.RFC3339)
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "unixmilli":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case "unixmicro":
+		return strconv.FormatInt(t.UnixMicro(), 10)
+	case "unixnano":
+		return strconv.FormatInt(t.UnixNano(), 10)
+	case "date":
+		return t.Format(DateLayout)
+	default:
+		return t.Format(format)
+	}
+}
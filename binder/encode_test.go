@@ -0,0 +1,38 @@
+package binder_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+func TestNewRequestEncodesBindingTags(t *testing.T) {
+	in := binderTestInput{
+		ID:      "a/b",
+		Cursor:  5,
+		Sort:    []string{"name", "-id"},
+		Client:  "cli",
+		Session: "s1",
+		Body:    binderTestBody{Name: "gear"},
+	}
+	req, err := binder.NewRequest(context.Background(), http.MethodPost, "/widgets/{id}", in)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if got := req.URL.String(); got != "/widgets/a%2Fb?cursor=5&sort=name&sort=-id" {
+		t.Fatalf("unexpected url %s", got)
+	}
+	if req.Header.Get("X-Client") != "cli" || req.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected headers %v", req.Header)
+	}
+	if cookie, err := req.Cookie("session_id"); err != nil || cookie.Value != "s1" {
+		t.Fatalf("expected session cookie, got %v", err)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != `{"name":"gear"}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+}
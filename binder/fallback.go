@@ -0,0 +1,75 @@
+package binder
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// fallbackOrder is the order sources are tried when a field declares
+// several of them without a `sources` tag.
+var fallbackOrder = []FieldSource{SourcePath, SourceQuery, SourceHeader, SourceCookie}
+
+// fallbackSources returns the sources a field declares in the order they are
+// tried, or nil when it declares at most one. A `sources:"header,query"` tag
+// overrides the default path, query, header, cookie order.
+func fallbackSources(field reflect.StructField) []FieldSource {
+	var sources []FieldSource
+	if list, ok := field.Tag.Lookup("sources"); ok {
+		for _, raw := range strings.Split(list, ",") {
+			source := FieldSource(strings.TrimSpace(raw))
+			if _, declared := field.Tag.Lookup(string(source)); declared && source != SourceBody {
+				sources = append(sources, source)
+			}
+		}
+	} else {
+		for _, source := range fallbackOrder {
+			if _, ok := field.Tag.Lookup(string(source)); ok {
+				sources = append(sources, source)
+			}
+		}
+	}
+	if len(sources) < 2 {
+		return nil
+	}
+	return sources
+}
+
+// bindFallback binds the first source in sources that carries a value.
+// Fallback chains read plain values; `all` and deep-object query forms need
+// a single source.
+func (b *DefaultBinder) bindFallback(field reflect.Value, fieldType reflect.StructField, name string, sources []FieldSource, info requestInfo, fieldErrors *[]FieldError) {
+	conv := b.conversion(fieldType)
+	for _, source := range sources {
+		values := sourceValues(source, fieldType, info)
+		if len(values) == 0 {
+			continue
+		}
+		if err := assignFromStrings(field, values, conv); err != nil {
+			appendFieldError(fieldErrors, FieldError{Field: name, Source: source, Message: err.Error()})
+		}
+		return
+	}
+	bindMissing(field, fieldType, name, sources[0], conv, fieldErrors)
+}
+
+// sourceValues returns the raw values of one source for a field.
+func sourceValues(source FieldSource, fieldType reflect.StructField, info requestInfo) []string {
+	tag := fieldType.Tag.Get(string(source))
+	switch source {
+	case SourcePath:
+		if val, ok := info.pathParams[firstNonEmpty(tag, fieldType.Name)]; ok {
+			return []string{val}
+		}
+	case SourceQuery:
+		return parseQueryTag(tag, fieldType).values(info.query)
+	case SourceHeader:
+		key, _, _ := strings.Cut(tag, ",")
+		return info.request.Header.Values(http.CanonicalHeaderKey(firstNonEmpty(key, fieldType.Name)))
+	case SourceCookie:
+		if val, ok := info.cookies[firstNonEmpty(tag, fieldType.Name)]; ok {
+			return []string{val}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package binder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fallbackInput struct {
+	Tenant string `header:"X-Tenant" query:"tenant" sources:"header,query"`
+	Token  string `query:"token" cookie:"token"`
+	Page   int    `query:"page" header:"X-Page" default:"1"`
+	Region string `header:"X-Region" query:"region" required:"true"`
+}
+
+func TestBind_FallsBackAcrossSources(t *testing.T) {
+	b := NewDefaultBinder().WithStrictParams(StrictParams{Query: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/?tenant=from-query&region=eu", nil)
+	req.AddCookie(&http.Cookie{Name: "token", Value: "from-cookie"})
+	var dst fallbackInput
+	if err := b.Bind(context.Background(), req, &dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Tenant != "from-query" || dst.Token != "from-cookie" || dst.Page != 1 || dst.Region != "eu" {
+		t.Fatalf("unexpected fallback binding: %+v", dst)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?tenant=from-query&token=from-query", nil)
+	req.Header.Set("X-Tenant", "from-header")
+	req.Header.Set("X-Page", "3")
+	req.Header.Set("X-Region", "us")
+	req.AddCookie(&http.Cookie{Name: "token", Value: "from-cookie"})
+	dst = fallbackInput{}
+	if err := b.Bind(context.Background(), req, &dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Tenant != "from-header" || dst.Token != "from-query" || dst.Page != 3 || dst.Region != "us" {
+		t.Fatalf("expected first source with a value to win, got %+v", dst)
+	}
+}
+
+func TestBind_FallbackReportsFirstSourceWhenMissing(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?page=x", nil)
+	err := NewDefaultBinder().Bind(context.Background(), req, &fallbackInput{})
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("expected BindError, got %v", err)
+	}
+	sources := map[string]FieldSource{}
+	for _, fe := range bindErr.Fields() {
+		sources[fe.Field] = fe.Source
+	}
+	if sources["Region"] != SourceQuery || sources["Page"] != SourceQuery || len(sources) != 2 {
+		t.Fatalf("unexpected field errors: %+v", bindErr.Fields())
+	}
+}
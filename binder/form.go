@@ -0,0 +1,153 @@
+package binder
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const formContentType = "application/x-www-form-urlencoded"
+
+// isFormRequest reports whether the request carries a URL-encoded form body.
+func isFormRequest(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == formContentType
+}
+
+// decodeForm binds URL-encoded form data into target. Struct fields are keyed
+// by their `form` tag, falling back to the `json` tag and then the field name,
+// so the same structs serve JSON and form bodies.
+func decodeForm(data []byte, target reflect.Value, name string, conv conversion) ([]FieldError, error) {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("form decode: %w", err)
+	}
+	var fieldErrors []FieldError
+	assignForm(values, target, "", name, conv, &fieldErrors)
+	return fieldErrors, nil
+}
+
+func assignForm(values url.Values, target reflect.Value, prefix, name string, conv conversion, fieldErrors *[]FieldError) {
+	for target.Kind() == reflect.Pointer {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+	switch target.Kind() {
+	case reflect.Map:
+		assignFormMap(values, target, prefix)
+	case reflect.Struct:
+		rt := target.Type()
+		for i := 0; i < target.NumField(); i++ {
+			field := target.Field(i)
+			fieldType := rt.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			if fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct {
+				assignForm(values, field, prefix, name, conv, fieldErrors)
+				continue
+			}
+			key := formKey(fieldType)
+			if key == "-" {
+				continue
+			}
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			fieldName := name + "." + key
+			if isNestedFormStruct(fieldType.Type) {
+				if hasFormPrefix(values, key) {
+					assignForm(values, field, key, name, conv, fieldErrors)
+				}
+				continue
+			}
+			vals, ok := values[key]
+			if !ok || len(vals) == 0 {
+				if def, ok := fieldType.Tag.Lookup("default"); ok {
+					fieldConv := conv.withField(fieldType)
+					if err := assignFromStrings(field, defaultValues(field.Type(), def, fieldConv), fieldConv); err != nil {
+						appendFieldError(fieldErrors, FieldError{Field: fieldName, Source: SourceBody, Message: "invalid default: " + err.Error()})
+					}
+				}
+				continue
+			}
+			if err := assignFromStrings(field, vals, conv.withField(fieldType)); err != nil {
+				appendFieldError(fieldErrors, FieldError{Field: fieldName, Source: SourceBody, Message: err.Error()})
+			}
+		}
+	}
+}
+
+func assignFormMap(values url.Values, target reflect.Value, prefix string) {
+	mt := target.Type()
+	if mt.Key().Kind() != reflect.String {
+		return
+	}
+	if target.IsNil() {
+		target.Set(reflect.MakeMap(mt))
+	}
+	for key, vals := range values {
+		if prefix != "" {
+			rest, ok := strings.CutPrefix(key, prefix+".")
+			if !ok {
+				continue
+			}
+			key = rest
+		}
+		var elem reflect.Value
+		switch {
+		case mt.Elem().Kind() == reflect.String && len(vals) > 0:
+			elem = reflect.ValueOf(vals[0]).Convert(mt.Elem())
+		case mt.Elem() == reflect.TypeOf([]string(nil)):
+			elem = reflect.ValueOf(append([]string{}, vals...))
+		case mt.Elem().Kind() == reflect.Interface && len(vals) == 1:
+			elem = reflect.ValueOf(vals[0])
+		case mt.Elem().Kind() == reflect.Interface:
+			elem = reflect.ValueOf(append([]string{}, vals...))
+		default:
+			continue
+		}
+		target.SetMapIndex(reflect.ValueOf(key).Convert(mt.Key()), elem)
+	}
+}
+
+func formKey(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("form"); ok {
+		if key := strings.Split(tag, ",")[0]; key != "" {
+			return key
+		}
+	}
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		if key := strings.Split(tag, ",")[0]; key != "" {
+			return key
+		}
+	}
+	return field.Name
+}
+
+func isNestedFormStruct(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Map {
+		return true
+	}
+	return typ.Kind() == reflect.Struct && !isLeafStruct(typ) && typ != timeType
+}
+
+func hasFormPrefix(values url.Values, prefix string) bool {
+	for key := range values {
+		if strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package binder
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type formAddress struct {
+	City string `json:"city"`
+}
+
+type formBody struct {
+	Name    string       `json:"name"`
+	Age     *int         `json:"age"`
+	Tags    []string     `form:"tag"`
+	Active  bool         `json:"active"`
+	Note    *string      `json:"note"`
+	Address *formAddress `json:"address"`
+}
+
+func TestDefaultBinder_BindsURLEncodedForm(t *testing.T) {
+	type input struct {
+		Body formBody `body:"" required:"true"`
+	}
+
+	form := "name=Jane&age=42&tag=a&tag=b&active=true&address.city=Oslo"
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	var dst input
+	if err := NewDefaultBinder().Bind(req.Context(), req, &dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := dst.Body
+	if got.Name != "Jane" || got.Age == nil || *got.Age != 42 || !got.Active {
+		t.Fatalf("unexpected scalar binding: %+v", got)
+	}
+	if len(got.Tags) != 2 || got.Tags[0] != "a" || got.Tags[1] != "b" {
+		t.Fatalf("expected repeated fields as slice, got %#v", got.Tags)
+	}
+	if got.Note != nil {
+		t.Fatalf("expected absent optional field to stay nil")
+	}
+	if got.Address == nil || got.Address.City != "Oslo" {
+		t.Fatalf("expected nested binding, got %+v", got.Address)
+	}
+}
+
+func TestDefaultBinder_FormConversionErrors(t *testing.T) {
+	type input struct {
+		Body formBody `body:""`
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("age=old"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var dst input
+	err := NewDefaultBinder().Bind(req.Context(), req, &dst)
+	bindErr, ok := err.(*BindError)
+	if !ok {
+		t.Fatalf("expected BindError, got %v", err)
+	}
+	fields := bindErr.Fields()
+	if len(fields) != 1 || fields[0].Field != "Body.age" || fields[0].Source != SourceBody {
+		t.Fatalf("unexpected field errors: %+v", fields)
+	}
+}
@@ -0,0 +1,238 @@
+package binder
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Modifier rewrites a bound string value for a `mod` tag entry; param is the
+// text after '=' or "".
+type Modifier func(value, param string) string
+
+// WithModifier returns a copy with the named modifier registered for `mod`
+// tags, replacing a built-in of the same name.
+func (b *DefaultBinder) WithModifier(name string, fn Modifier) *DefaultBinder {
+	copy := *b
+	copy.Modifiers = make(map[string]Modifier, len(b.Modifiers)+1)
+	for k, v := range b.Modifiers {
+		copy.Modifiers[k] = v
+	}
+	copy.Modifiers[name] = fn
+	return &copy
+}
+
+// SetModifier registers the named modifier in place.
+func (b *DefaultBinder) SetModifier(name string, fn Modifier) {
+	if b.Modifiers == nil {
+		b.Modifiers = make(map[string]Modifier)
+	}
+	b.Modifiers[name] = fn
+}
+
+func (b *DefaultBinder) modifier(name string) (Modifier, bool) {
+	if fn, ok := b.Modifiers[name]; ok {
+		return fn, true
+	}
+	fn, ok := builtinModifiers[name]
+	return fn, ok
+}
+
+var builtinModifiers = map[string]Modifier{
+	"trim":        func(v, _ string) string { return strings.TrimSpace(v) },
+	"ltrim":       func(v, _ string) string { return strings.TrimLeftFunc(v, unicode.IsSpace) },
+	"rtrim":       func(v, _ string) string { return strings.TrimRightFunc(v, unicode.IsSpace) },
+	"lowercase":   func(v, _ string) string { return strings.ToLower(v) },
+	"uppercase":   func(v, _ string) string { return strings.ToUpper(v) },
+	"casefold":    modCaseFold,
+	"collapse":    func(v, _ string) string { return strings.Join(strings.Fields(v), " ") },
+	"truncate":    modTruncate,
+	"escape_html": func(v, _ string) string { return html.EscapeString(v) },
+	"strip_html":  modStripHTML,
+}
+
+// modCaseFold maps each rune to its simple case-folded form for
+// case-insensitive comparisons.
+func modCaseFold(v, _ string) string {
+	return strings.Map(func(r rune) rune {
+		folded := unicode.SimpleFold(r)
+		for folded > r {
+			folded = unicode.SimpleFold(folded)
+		}
+		if folded < r {
+			return folded
+		}
+		return r
+	}, v)
+}
+
+// modTruncate keeps at most param runes.
+func modTruncate(v, param string) string {
+	n, err := strconv.Atoi(param)
+	if err != nil || n < 0 || utf8.RuneCountInString(v) <= n {
+		return v
+	}
+	i := 0
+	for pos := range v {
+		if i == n {
+			return v[:pos]
+		}
+		i++
+	}
+	return v
+}
+
+// modStripHTML removes tags, comments, and the content of script and style
+// elements, leaving entities escaped.
+func modStripHTML(v, _ string) string {
+	var out strings.Builder
+	for len(v) > 0 {
+		start := strings.IndexByte(v, '<')
+		if start < 0 {
+			out.WriteString(v)
+			break
+		}
+		out.WriteString(v[:start])
+		v = v[start:]
+		if strings.HasPrefix(v, "<!--") {
+			end := strings.Index(v, "-->")
+			if end < 0 {
+				break
+			}
+			v = v[end+3:]
+			continue
+		}
+		end := strings.IndexByte(v, '>')
+		if end < 0 {
+			break
+		}
+		tag := strings.ToLower(strings.TrimSpace(v[1:end]))
+		v = v[end+1:]
+		for _, raw := range []string{"script", "style"} {
+			if tag == raw || strings.HasPrefix(tag, raw+" ") {
+				if close := strings.Index(strings.ToLower(v), "</"+raw); close >= 0 {
+					v = v[close:]
+				} else {
+					v = ""
+				}
+			}
+		}
+	}
+	return out.String()
+}
+
+// modTagged caches whether a struct type has `mod` tags at any depth.
+var modTagged sync.Map
+
+// applyModifiers rewrites the string fields of rv tagged with `mod`, e.g.
+// `mod:"trim,lowercase,truncate=255"`, in tag order.
+func (b *DefaultBinder) applyModifiers(rv reflect.Value) error {
+	if !hasModTags(rv.Type()) {
+		return nil
+	}
+	return b.modifyStruct(rv)
+}
+
+func (b *DefaultBinder) modifyStruct(rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Field(i)
+		fieldType := rt.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		if tag, ok := fieldType.Tag.Lookup("mod"); ok && tag != "-" {
+			if err := b.modifyValue(field, tag, fieldType.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		nested := field
+		for nested.Kind() == reflect.Pointer && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && hasModTags(nested.Type()) {
+			if err := b.modifyStruct(nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// modifyValue applies tag to a string, *string, or string slice field.
+func (b *DefaultBinder) modifyValue(field reflect.Value, tag, name string) error {
+	switch {
+	case field.Kind() == reflect.Pointer:
+		if field.IsNil() {
+			return nil
+		}
+		return b.modifyValue(field.Elem(), tag, name)
+	case field.Kind() == reflect.String:
+		out, err := b.modify(field.String(), tag, name)
+		if err != nil {
+			return err
+		}
+		field.SetString(out)
+	case (field.Kind() == reflect.Slice || field.Kind() == reflect.Array) && field.Type().Elem().Kind() == reflect.String:
+		for i := 0; i < field.Len(); i++ {
+			if err := b.modifyValue(field.Index(i), tag, name); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("mod tag on field %s requires a string type, got %s", name, field.Type())
+	}
+	return nil
+}
+
+func (b *DefaultBinder) modify(value, tag, name string) (string, error) {
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		modName, param, _ := strings.Cut(part, "=")
+		fn, ok := b.modifier(strings.TrimSpace(modName))
+		if !ok || fn == nil {
+			return "", fmt.Errorf("unknown modifier %q on field %s", modName, name)
+		}
+		value = fn(value, strings.TrimSpace(param))
+	}
+	return value, nil
+}
+
+// hasModTags reports whether rt has `mod` tags at any depth.
+func hasModTags(rt reflect.Type) bool {
+	if cached, ok := modTagged.Load(rt); ok {
+		return cached.(bool)
+	}
+	found := scanModTags(rt, map[reflect.Type]bool{})
+	modTagged.Store(rt, found)
+	return found
+}
+
+func scanModTags(rt reflect.Type, visiting map[reflect.Type]bool) bool {
+	for rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct || visiting[rt] {
+		return false
+	}
+	visiting[rt] = true
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if _, ok := field.Tag.Lookup("mod"); ok {
+			return true
+		}
+		if (field.IsExported() || field.Anonymous) && scanModTags(field.Type, visiting) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package binder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type modifiedBody struct {
+	Email string   `json:"email" mod:"trim,lowercase" validate:"required"`
+	Bio   string   `json:"bio" mod:"strip_html,collapse,truncate=12"`
+	Tags  []string `json:"tags" mod:"trim,uppercase"`
+}
+
+type modifiedInput struct {
+	Q    *string      `query:"q" mod:"trim,escape_html"`
+	Name string       `query:"name" mod:"trim" validate:"min=3"`
+	Body modifiedBody `body:""`
+}
+
+func TestBind_AppliesModifiersBeforeValidation(t *testing.T) {
+	b := NewDefaultBinder().WithValidator(NewTagValidator())
+	body := `{"email":"  Alice@Example.COM ","bio":"<p>Hello   <b>big</b></p><script>x()</script> world!","tags":[" a ","b "]}`
+	req := httptest.NewRequest(http.MethodPost, "/?q=+%3Cb%3E+&name=+bob+", strings.NewReader(body))
+	var dst modifiedInput
+	if err := b.Bind(req.Context(), req, &dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Body.Email != "alice@example.com" || dst.Name != "bob" || *dst.Q != "&lt;b&gt;" {
+		t.Fatalf("unexpected modified values: %+v %q", dst, *dst.Q)
+	}
+	if dst.Body.Bio != "Hello big wo" {
+		t.Fatalf("unexpected bio %q", dst.Body.Bio)
+	}
+	if strings.Join(dst.Body.Tags, ",") != "A,B" {
+		t.Fatalf("unexpected tags %v", dst.Body.Tags)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/?name=+ab+", strings.NewReader(`{"email":"  "}`))
+	err := b.Bind(req.Context(), req, &modifiedInput{})
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) || len(bindErr.Fields()) != 2 {
+		t.Fatalf("expected trimmed values to fail validation, got %v", err)
+	}
+}
+
+func TestBind_CustomAndUnknownModifiers(t *testing.T) {
+	type input struct {
+		Phone string `query:"phone" mod:"digits"`
+		Other string `query:"other" mod:"bogus"`
+	}
+	digits := func(v, _ string) string {
+		return strings.Map(func(r rune) rune {
+			if r < '0' || r > '9' {
+				return -1
+			}
+			return r
+		}, v)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?phone=040-123+45", nil)
+	err := NewDefaultBinder().WithModifier("digits", digits).Bind(context.Background(), req, &input{})
+	if err == nil || !strings.Contains(err.Error(), `unknown modifier "bogus"`) {
+		t.Fatalf("expected unknown modifier error, got %v", err)
+	}
+
+	type phoneOnly struct {
+		Phone string `query:"phone" mod:"digits,truncate=5"`
+	}
+	var dst phoneOnly
+	if err := NewDefaultBinder().WithModifier("digits", digits).Bind(context.Background(), req, &dst); err != nil || dst.Phone != "04012" {
+		t.Fatalf("expected custom modifier applied, got %q %v", dst.Phone, err)
+	}
+}
+
+func TestModCaseFold(t *testing.T) {
+	if modCaseFold("ÀBC", "") != modCaseFold("àbc", "") {
+		t.Fatalf("expected case-insensitive equality")
+	}
+}
@@ -0,0 +1,157 @@
+package binder
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"math"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// NDJSONContentType is the media type of newline-delimited JSON bodies.
+const NDJSONContentType = "application/x-ndjson"
+
+func isNDJSONRequest(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == NDJSONContentType
+}
+
+// NDJSON is a body field that decodes an application/x-ndjson request body
+// lazily, one object per line, so bulk-ingest handlers never hold the whole
+// payload in memory. Bind it with a `body` tag.
+type NDJSON[T any] struct {
+	dec   *json.Decoder
+	field string
+	line  int
+}
+
+// bindStream implements ndjsonStream.
+func (s *NDJSON[T]) bindStream(dec *json.Decoder, field string) {
+	s.dec, s.field, s.line = dec, field, 0
+}
+
+// All yields the decoded items in order. It stops at the first malformed
+// line with a *BindError, or with ErrBodyTooLarge once the body exceeds the
+// binder's MaxBodyBytes. The body can be iterated once.
+func (s *NDJSON[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if s == nil || s.dec == nil {
+			return
+		}
+		for {
+			var item T
+			err := s.dec.Decode(&item)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			s.line++
+			if err != nil {
+				yield(item, ndjsonError(s.field, s.line, err))
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ndjsonStream is implemented by *NDJSON[T].
+type ndjsonStream interface {
+	bindStream(dec *json.Decoder, field string)
+}
+
+func ndjsonError(field string, line int, err error) error {
+	if errors.Is(err, ErrBodyTooLarge) {
+		return err
+	}
+	return &BindError{
+		message: "Failed to decode request body",
+		fields:  []FieldError{NewFieldError(field, SourceBody, "line "+strconv.Itoa(line)+": "+err.Error())},
+		cause:   err,
+	}
+}
+
+// bindNDJSON binds an application/x-ndjson body into an NDJSON field, or
+// decodes it line by line into a slice field.
+func (b *DefaultBinder) bindNDJSON(r *http.Request, field reflect.Value, name string) error {
+	if r.Body == nil {
+		return nil
+	}
+	limit := b.MaxBodyBytes
+	if limit <= 0 {
+		limit = defaultMaxBodyBytes
+	}
+	dec := json.NewDecoder(&limitedBody{r: r.Body, remaining: limit})
+	if b.strictBodies() {
+		dec.DisallowUnknownFields()
+	}
+	if stream, ok := field.Addr().Interface().(ndjsonStream); ok {
+		stream.bindStream(dec, name)
+		return nil
+	}
+	if field.Kind() != reflect.Slice {
+		return &BindError{
+			message: "Unsupported request body",
+			fields:  []FieldError{NewFieldError(name, SourceBody, "NDJSON bodies bind to slices or binder.NDJSON fields")},
+		}
+	}
+	items := reflect.MakeSlice(field.Type(), 0, 0)
+	for line := 1; ; line++ {
+		item := reflect.New(field.Type().Elem())
+		err := dec.Decode(item.Interface())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ndjsonError(name, line, err)
+		}
+		items = reflect.Append(items, item.Elem())
+	}
+	field.Set(items)
+	return nil
+}
+
+// strictBodies reports whether unknown JSON body fields are rejected.
+func (b *DefaultBinder) strictBodies() bool {
+	switch dec := b.bodyDecoder().(type) {
+	case JSONBodyDecoder:
+		return dec.DisallowUnknown
+	case *JSONBodyDecoder:
+		return dec.DisallowUnknown
+	}
+	return false
+}
+
+// limitedBody fails with ErrBodyTooLarge, rather than a silent EOF, once
+// more than remaining bytes are read.
+type limitedBody struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 && l.remaining < math.MaxInt64 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, ErrBodyTooLarge
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return n, ErrBodyTooLarge
+	}
+	return n, err
+}
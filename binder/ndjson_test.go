@@ -0,0 +1,66 @@
+package binder_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+type ndjsonItem struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+func ndjsonRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	req.Header.Set("Content-Type", binder.NDJSONContentType)
+	return req
+}
+
+func TestDefaultBinder_DecodesNDJSONIntoSlices(t *testing.T) {
+	var in struct {
+		Items []ndjsonItem `body:""`
+	}
+	err := binder.NewDefaultBinder().Bind(context.Background(), ndjsonRequest("{\"sku\":\"a\",\"qty\":1}\n{\"sku\":\"b\",\"qty\":2}\n"), &in)
+	if err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	if len(in.Items) != 2 || in.Items[1].SKU != "b" || in.Items[1].Qty != 2 {
+		t.Fatalf("unexpected items: %+v", in.Items)
+	}
+
+	err = binder.NewDefaultBinder().Bind(context.Background(), ndjsonRequest("{\"sku\":\"a\"}\n{\"qty\":\"x\"}\n"), &in)
+	bindErr, ok := err.(*binder.BindError)
+	if !ok || !strings.HasPrefix(bindErr.Fields()[0].Message, "line 2:") {
+		t.Fatalf("expected line 2 error, got %v", err)
+	}
+}
+
+func TestNDJSON_StreamsLazilyWithinLimit(t *testing.T) {
+	var in struct {
+		Items binder.NDJSON[ndjsonItem] `body:""`
+	}
+	b := binder.NewDefaultBinder()
+	b.MaxBodyBytes = 40
+	body := strings.Repeat("{\"sku\":\"a\",\"qty\":1}\n", 3)
+	if err := b.Bind(context.Background(), ndjsonRequest(body), &in); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	var got int
+	var streamErr error
+	for item, err := range in.Items.All() {
+		if err != nil {
+			streamErr = err
+			break
+		}
+		got += item.Qty
+	}
+	if got != 2 || !errors.Is(streamErr, binder.ErrBodyTooLarge) {
+		t.Fatalf("expected two items then ErrBodyTooLarge, got %d and %v", got, streamErr)
+	}
+}
@@ -0,0 +1,141 @@
+package binder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// MergePatchContentType is the media type of RFC 7386 JSON Merge Patch bodies.
+const MergePatchContentType = "application/merge-patch+json"
+
+// MergePatch is an RFC 7386 JSON Merge Patch document. Bind it through a
+// `body` tag; members keep their raw JSON so explicit nulls stay distinct
+// from absent members.
+type MergePatch map[string]json.RawMessage
+
+// Has reports whether the patch supplies field, including explicit nulls.
+func (p MergePatch) Has(field string) bool {
+	_, ok := p[field]
+	return ok
+}
+
+// IsNull reports whether the patch explicitly clears field.
+func (p MergePatch) IsNull(field string) bool {
+	raw, ok := p[field]
+	return ok && bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// Fields returns the supplied top-level members in sorted order.
+func (p MergePatch) Fields() []string {
+	fields := make([]string, 0, len(p))
+	for field := range p {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// CheckImmutable rejects patches that touch any of the immutable fields.
+func (p MergePatch) CheckImmutable(immutable ...string) error {
+	var fieldErrors []FieldError
+	for _, field := range immutable {
+		if p.Has(field) {
+			fieldErrors = append(fieldErrors, NewFieldError(field, SourceBody, "field is immutable"))
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return NewBindError("Patch modifies immutable fields", fieldErrors)
+	}
+	return nil
+}
+
+// Updates translates the supplied members into column updates using
+// apiToDBFields. Unknown members are reported as field errors; explicit nulls
+// map to nil values.
+func (p MergePatch) Updates(apiToDBFields map[string]string) (map[string]any, error) {
+	updates := make(map[string]any, len(p))
+	var fieldErrors []FieldError
+	for _, field := range p.Fields() {
+		column, ok := apiToDBFields[field]
+		if !ok {
+			fieldErrors = append(fieldErrors, NewFieldError(field, SourceBody, "unknown field"))
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(p[field], &value); err != nil {
+			fieldErrors = append(fieldErrors, NewFieldError(field, SourceBody, err.Error()))
+			continue
+		}
+		updates[column] = value
+	}
+	if len(fieldErrors) > 0 {
+		return nil, NewBindError("Invalid patch", fieldErrors)
+	}
+	return updates, nil
+}
+
+// Apply merges the patch into target, which must be a non-nil pointer, per
+// RFC 7386: nulls remove members, objects merge recursively, and other values
+// replace. Values that do not fit target's type are reported as field errors.
+func (p MergePatch) Apply(target any, immutable ...string) error {
+	if err := p.CheckImmutable(immutable...); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("merge patch target must be a non-nil pointer")
+	}
+	original, err := json.Marshal(target)
+	if err != nil {
+		return fmt.Errorf("merge patch: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(original, &doc); err != nil {
+		return fmt.Errorf("merge patch: %w", err)
+	}
+	patch := make(map[string]any, len(p))
+	for field, raw := range p {
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return NewBindError("Invalid patch", []FieldError{NewFieldError(field, SourceBody, err.Error())})
+		}
+		patch[field] = value
+	}
+	merged, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		return fmt.Errorf("merge patch: %w", err)
+	}
+	result := reflect.New(rv.Elem().Type())
+	if err := json.Unmarshal(merged, result.Interface()); err != nil {
+		field := ""
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+			field = typeErr.Field
+		}
+		return NewBindError("Invalid patch", []FieldError{NewFieldError(field, SourceBody, err.Error())})
+	}
+	rv.Elem().Set(result.Elem())
+	return nil
+}
+
+// mergePatch implements the RFC 7386 MergePatch algorithm.
+func mergePatch(target any, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = map[string]any{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
@@ -0,0 +1,87 @@
+package binder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type patchAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type patchUser struct {
+	ID       int           `json:"id"`
+	Name     string        `json:"name"`
+	Nickname *string       `json:"nickname"`
+	Address  patchAddress  `json:"address"`
+	Tags     []string      `json:"tags"`
+	Manager  *patchAddress `json:"manager,omitempty"`
+}
+
+func bindPatch(t *testing.T, body string) MergePatch {
+	t.Helper()
+	var input struct {
+		Patch MergePatch `body:"true"`
+	}
+	req := httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", MergePatchContentType)
+	if err := NewDefaultBinder().Bind(context.Background(), req, &input); err != nil {
+		t.Fatalf("bind patch: %v", err)
+	}
+	return input.Patch
+}
+
+func TestMergePatchApply(t *testing.T) {
+	nick := "al"
+	user := patchUser{ID: 1, Name: "Alice", Nickname: &nick, Address: patchAddress{City: "Oslo", Zip: "0150"}, Tags: []string{"a"}}
+
+	patch := bindPatch(t, `{"name":"Alicia","nickname":null,"address":{"city":"Bergen"},"tags":["b","c"]}`)
+	if !patch.Has("nickname") || !patch.IsNull("nickname") || patch.Has("id") {
+		t.Fatalf("unexpected presence tracking: %v", patch.Fields())
+	}
+	if err := patch.Apply(&user, "id"); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if user.Name != "Alicia" || user.Nickname != nil || user.Address.City != "Bergen" || user.Address.Zip != "0150" {
+		t.Fatalf("unexpected patched user: %+v", user)
+	}
+	if len(user.Tags) != 2 || user.Tags[0] != "b" {
+		t.Fatalf("expected arrays to be replaced, got %v", user.Tags)
+	}
+}
+
+func TestMergePatchRejectsImmutableAndMistypedFields(t *testing.T) {
+	user := patchUser{ID: 1}
+	var bindErr *BindError
+
+	err := bindPatch(t, `{"id":2}`).Apply(&user, "id")
+	if !errors.As(err, &bindErr) || bindErr.Fields()[0].Field != "id" {
+		t.Fatalf("expected immutable field error, got %v", err)
+	}
+	err = bindPatch(t, `{"name":5}`).Apply(&user)
+	if !errors.As(err, &bindErr) || bindErr.Fields()[0].Field != "name" {
+		t.Fatalf("expected type error on name, got %v", err)
+	}
+	if user.ID != 1 {
+		t.Fatalf("expected target untouched on failure")
+	}
+}
+
+func TestMergePatchUpdates(t *testing.T) {
+	patch := bindPatch(t, `{"name":"Bob","nickname":null}`)
+	updates, err := patch.Updates(map[string]string{"name": "full_name", "nickname": "nick"})
+	if err != nil {
+		t.Fatalf("updates: %v", err)
+	}
+	if updates["full_name"] != "Bob" || updates["nick"] != nil || len(updates) != 2 {
+		t.Fatalf("unexpected updates: %v", updates)
+	}
+	if _, err := patch.Updates(map[string]string{"name": "full_name"}); err == nil {
+		t.Fatalf("expected unknown field error")
+	}
+}
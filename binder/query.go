@@ -0,0 +1,288 @@
+package binder
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// queryTag is a parsed `query` struct tag: the parameter name followed by
+// comma-separated options. `explode=false` reads and writes lists as one
+// comma-separated value instead of repeated parameters, and `all` collects
+// every parameter into a map field.
+type queryTag struct {
+	name    string
+	explode bool
+	all     bool
+}
+
+func parseQueryTag(tag string, field reflect.StructField) queryTag {
+	name, opts, _ := strings.Cut(tag, ",")
+	qt := queryTag{name: firstNonEmpty(strings.TrimSpace(name), field.Name), explode: true}
+	for _, opt := range strings.Split(opts, ",") {
+		switch strings.TrimSpace(opt) {
+		case "explode=false":
+			qt.explode = false
+		case "explode", "explode=true":
+			qt.explode = true
+		case "all":
+			qt.all = true
+		}
+	}
+	return qt
+}
+
+// values returns the values bound to a scalar or list query field,
+// accepting both name and name[] keys.
+func (qt queryTag) values(query map[string][]string) []string {
+	values := append(append([]string{}, query[qt.name]...), query[qt.name+"[]"]...)
+	if qt.explode {
+		return values
+	}
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// isDeepObject reports whether a query field binds from bracket syntax
+// (filter[name]=x&filter[age][gte]=3) rather than a single parameter.
+func isDeepObject(typ reflect.Type, conv conversion) bool {
+	if _, ok := conv.converters[typ]; ok {
+		return false
+	}
+	return isNestedFormStruct(typ)
+}
+
+// queryNode is one level of a bracket-syntax query parameter.
+type queryNode struct {
+	values   []string
+	children map[string]*queryNode
+}
+
+// parseDeepObject collects the parameters nested under name. It returns nil
+// when the request carries none.
+func parseDeepObject(query map[string][]string, name string) *queryNode {
+	var root *queryNode
+	for key, values := range query {
+		rest, ok := strings.CutPrefix(key, name+"[")
+		if !ok {
+			continue
+		}
+		segments, ok := bracketSegments("[" + rest)
+		if !ok {
+			continue
+		}
+		if root == nil {
+			root = &queryNode{}
+		}
+		node := root
+		for _, segment := range segments {
+			if node.children == nil {
+				node.children = make(map[string]*queryNode)
+			}
+			child, ok := node.children[segment]
+			if !ok {
+				child = &queryNode{}
+				node.children[segment] = child
+			}
+			node = child
+		}
+		node.values = append(node.values, values...)
+	}
+	return root
+}
+
+// bracketSegments splits "[a][b]" into a and b.
+func bracketSegments(s string) ([]string, bool) {
+	var segments []string
+	for s != "" {
+		if s[0] != '[' {
+			return nil, false
+		}
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return nil, false
+		}
+		segments = append(segments, s[1:end])
+		s = s[end+1:]
+	}
+	return segments, len(segments) > 0
+}
+
+func bindDeepObject(target reflect.Value, node *queryNode, name string, conv conversion, fieldErrors *[]FieldError) {
+	for target.Kind() == reflect.Pointer {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+	if !isDeepObject(target.Type(), conv) {
+		if len(node.values) == 0 {
+			appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceQuery, Message: "expected a value, not nested parameters"})
+			return
+		}
+		if err := assignFromStrings(target, node.values, conv); err != nil {
+			appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceQuery, Message: err.Error()})
+		}
+		return
+	}
+	switch target.Kind() {
+	case reflect.Struct:
+		rt := target.Type()
+		for i := 0; i < target.NumField(); i++ {
+			field := target.Field(i)
+			fieldType := rt.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			if fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct {
+				bindDeepObject(field, node, name, conv, fieldErrors)
+				continue
+			}
+			key := deepObjectKey(fieldType)
+			if key == "-" {
+				continue
+			}
+			fieldName := name + "." + fieldType.Name
+			fieldConv := conv.withField(fieldType)
+			child, ok := node.children[key]
+			if !ok {
+				bindMissing(field, fieldType, fieldName, SourceQuery, fieldConv, fieldErrors)
+				continue
+			}
+			bindDeepObject(field, child, fieldName, fieldConv, fieldErrors)
+		}
+	case reflect.Map:
+		mt := target.Type()
+		if mt.Key().Kind() != reflect.String {
+			appendFieldError(fieldErrors, FieldError{Field: name, Source: SourceQuery, Message: fmt.Sprintf("unsupported map key type %s", mt.Key())})
+			return
+		}
+		if target.IsNil() {
+			target.Set(reflect.MakeMap(mt))
+		}
+		keys := make([]string, 0, len(node.children))
+		for key := range node.children {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := node.children[key]
+			elem := reflect.New(mt.Elem()).Elem()
+			if mt.Elem().Kind() == reflect.Interface {
+				elem.Set(reflect.ValueOf(child.interfaceValue()))
+			} else {
+				bindDeepObject(elem, child, name+"["+key+"]", conv, fieldErrors)
+			}
+			target.SetMapIndex(reflect.ValueOf(key).Convert(mt.Key()), elem)
+		}
+	}
+}
+
+// interfaceValue renders node for map[string]any targets: nested nodes become
+// maps, single values strings, and repeated values string slices.
+func (n *queryNode) interfaceValue() any {
+	if len(n.children) > 0 {
+		out := make(map[string]any, len(n.children))
+		for key, child := range n.children {
+			out[key] = child.interfaceValue()
+		}
+		return out
+	}
+	if len(n.values) == 1 {
+		return n.values[0]
+	}
+	return append([]string{}, n.values...)
+}
+
+// deepObjectKey names a nested field by its `query` tag, then its `json` tag,
+// then the field name.
+func deepObjectKey(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("query"); ok {
+		if key, _, _ := strings.Cut(tag, ","); key != "" {
+			return key
+		}
+	}
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		if key, _, _ := strings.Cut(tag, ","); key != "" {
+			return key
+		}
+	}
+	return field.Name
+}
+
+// encodeQuery is the inverse of the query binding used by NewRequest.
+func encodeQuery(query url.Values, tag queryTag, v reflect.Value, format string) {
+	if tag.all {
+		encodeAll(v, format, query.Add)
+		return
+	}
+	if isNestedFormStruct(v.Type()) {
+		encodeDeepObject(query, tag.name, v)
+		return
+	}
+	values := formatValues(v, format)
+	if !tag.explode && len(values) > 0 {
+		values = []string{strings.Join(values, ",")}
+	}
+	for _, s := range values {
+		query.Add(tag.name, s)
+	}
+}
+
+func encodeDeepObject(query url.Values, prefix string, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if !isNestedFormStruct(v.Type()) {
+		for _, s := range formatValues(v, "") {
+			query.Add(prefix, s)
+		}
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			fieldType, field := t.Field(i), v.Field(i)
+			if !fieldType.IsExported() {
+				continue
+			}
+			if fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct {
+				encodeDeepObject(query, prefix, field)
+				continue
+			}
+			key := deepObjectKey(fieldType)
+			if key == "-" {
+				continue
+			}
+			if isNestedFormStruct(fieldType.Type) {
+				encodeDeepObject(query, prefix+"["+key+"]", field)
+				continue
+			}
+			for _, s := range formatValues(field, fieldType.Tag.Get("format")) {
+				query.Add(prefix+"["+key+"]", s)
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			encodeDeepObject(query, prefix+"["+key.String()+"]", v.MapIndex(key))
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package binder_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+type rangeFilter struct {
+	Gte *int `json:"gte"`
+	Lte *int `json:"lte"`
+}
+
+type listFilter struct {
+	Name   string                 `json:"name"`
+	Age    rangeFilter            `json:"age"`
+	Status []string               `json:"status"`
+	Limit  int                    `json:"limit" default:"10"`
+	Extra  map[string]string      `json:"extra"`
+	Raw    map[string]any         `json:"raw"`
+	Scores map[string]rangeFilter `json:"scores"`
+}
+
+type listInput struct {
+	Filter listFilter `query:"filter"`
+	IDs    []int      `query:"ids,explode=false"`
+	Tags   []string   `query:"tag"`
+}
+
+func TestDefaultBinder_BindsDeepObjectQueries(t *testing.T) {
+	target := "/items?filter[name]=bob&filter[age][gte]=3&filter[status]=a&filter[status]=b" +
+		"&filter[extra][region]=eu&filter[raw][x][y]=1&filter[scores][math][lte]=9" +
+		"&ids=1,2,3&tag[]=x&tag[]=y"
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+
+	var in listInput
+	if err := binder.NewDefaultBinder().Bind(context.Background(), req, &in); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	f := in.Filter
+	if f.Name != "bob" || f.Age.Gte == nil || *f.Age.Gte != 3 || f.Age.Lte != nil || f.Limit != 10 {
+		t.Fatalf("unexpected filter: %+v", f)
+	}
+	if !reflect.DeepEqual(f.Status, []string{"a", "b"}) || f.Extra["region"] != "eu" {
+		t.Fatalf("unexpected filter lists: %+v", f)
+	}
+	if raw, ok := f.Raw["x"].(map[string]any); !ok || raw["y"] != "1" {
+		t.Fatalf("unexpected raw map: %#v", f.Raw)
+	}
+	if s := f.Scores["math"]; s.Lte == nil || *s.Lte != 9 {
+		t.Fatalf("unexpected scores: %+v", f.Scores)
+	}
+	if !reflect.DeepEqual(in.IDs, []int{1, 2, 3}) || !reflect.DeepEqual(in.Tags, []string{"x", "y"}) {
+		t.Fatalf("unexpected lists: %v %v", in.IDs, in.Tags)
+	}
+
+	bad := httptest.NewRequest(http.MethodGet, "/items?filter[age][gte]=old", nil)
+	err := binder.NewDefaultBinder().Bind(context.Background(), bad, &listInput{})
+	bindErr, ok := err.(*binder.BindError)
+	if !ok || len(bindErr.Fields()) != 1 || bindErr.Fields()[0].Field != "Filter.Age.Gte" {
+		t.Fatalf("expected field error for Filter.Age.Gte, got %v", err)
+	}
+}
+
+func TestNewRequest_EncodesDeepObjectQueries(t *testing.T) {
+	gte := 3
+	in := listInput{
+		Filter: listFilter{Name: "bob", Age: rangeFilter{Gte: &gte}, Extra: map[string]string{"region": "eu"}},
+		IDs:    []int{1, 2},
+	}
+	req, err := binder.NewRequest(context.Background(), http.MethodGet, "/items", in)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	q := req.URL.Query()
+	if q.Get("filter[name]") != "bob" || q.Get("filter[age][gte]") != "3" || q.Get("filter[extra][region]") != "eu" || q.Get("ids") != "1,2" {
+		t.Fatalf("unexpected query: %s", req.URL.RawQuery)
+	}
+
+	var out listInput
+	if err := binder.NewDefaultBinder().Bind(context.Background(), req, &out); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	if out.Filter.Name != "bob" || *out.Filter.Age.Gte != 3 || !reflect.DeepEqual(out.IDs, []int{1, 2}) {
+		t.Fatalf("round trip mismatch: %+v", out)
+	}
+}
+
+type Paging struct {
+	Limit  int `query:"limit" default:"25"`
+	Offset int `query:"offset"`
+}
+
+type collectInput struct {
+	*Paging
+	Limit   int               `query:"limit"`
+	Tenant  string            `header:"X-Tenant"`
+	Query   map[string]string `query:",all"`
+	Multi   map[string][]int  `query:",all"`
+	Headers map[string]string `header:",all"`
+}
+
+func TestDefaultBinder_CollectsAllAndBindsEmbeddedPointers(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items?limit=5&offset=10&offset=20", nil)
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("x-trace", "t1")
+
+	var in collectInput
+	err := binder.NewDefaultBinder().Bind(context.Background(), req, &in)
+	if err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	// Individually bound fields and the collecting maps both see every value;
+	// the outer Limit does not shadow the embedded one during binding.
+	if in.Paging == nil || in.Paging.Limit != 5 || in.Offset != 10 || in.Limit != 5 {
+		t.Fatalf("unexpected paging: %+v %+v", in.Paging, in)
+	}
+	if in.Query["limit"] != "5" || in.Query["offset"] != "10" {
+		t.Fatalf("unexpected query map: %v", in.Query)
+	}
+	if !reflect.DeepEqual(in.Multi["offset"], []int{10, 20}) {
+		t.Fatalf("unexpected multi map: %v", in.Multi)
+	}
+	if in.Tenant != "acme" || in.Headers["X-Tenant"] != "acme" || in.Headers["X-Trace"] != "t1" {
+		t.Fatalf("unexpected headers: %q %v", in.Tenant, in.Headers)
+	}
+
+	// Embedded pointers are allocated even without values, so defaults apply.
+	var empty collectInput
+	if err := binder.NewDefaultBinder().Bind(context.Background(), httptest.NewRequest(http.MethodGet, "/items", nil), &empty); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	if empty.Paging == nil || empty.Paging.Limit != 25 || len(empty.Query) != 0 {
+		t.Fatalf("unexpected empty bind: %+v", empty)
+	}
+
+	bad := httptest.NewRequest(http.MethodGet, "/items?offset=x", nil)
+	err = binder.NewDefaultBinder().Bind(context.Background(), bad, &collectInput{})
+	bindErr, ok := err.(*binder.BindError)
+	if !ok || len(bindErr.Fields()) != 2 || bindErr.Fields()[1].Field != "Multi[offset]" {
+		t.Fatalf("expected errors for Offset and Multi[offset], got %v", err)
+	}
+}
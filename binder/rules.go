@@ -0,0 +1,157 @@
+package binder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*\.?$`)
+	// regexCache holds patterns compiled for the regex rule.
+	regexCache sync.Map
+)
+
+// stringRule applies check to string values and reports other kinds as
+// unsupported.
+func stringRule(name string, check func(value, param string) string) ValidationRule {
+	return func(value reflect.Value, param string) string {
+		if value.Kind() != reflect.String {
+			return fmt.Sprintf("%s is not supported for %s", name, value.Kind())
+		}
+		return check(value.String(), param)
+	}
+}
+
+func ruleUUID(value, _ string) string {
+	if !uuidPattern.MatchString(value) {
+		return "must be a valid UUID"
+	}
+	return ""
+}
+
+// ruleURL accepts absolute URLs. The parameter optionally lists the allowed
+// schemes separated by |, e.g. url=https.
+func ruleURL(value, param string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+		return "must be a valid absolute URL"
+	}
+	if param == "" {
+		return ""
+	}
+	for _, scheme := range strings.Split(param, "|") {
+		if strings.EqualFold(u.Scheme, strings.TrimSpace(scheme)) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("must be a URL with scheme %s", strings.Join(strings.Split(param, "|"), ", "))
+}
+
+// ruleIP accepts IPv4 and IPv6 addresses when version is 0.
+func ruleIP(version int) func(value, param string) string {
+	return func(value, _ string) string {
+		addr, err := netip.ParseAddr(value)
+		switch {
+		case err != nil || addr.Zone() != "":
+		case version == 4 && !addr.Is4():
+		case version == 6 && (!addr.Is6() || addr.Is4In6()):
+		default:
+			return ""
+		}
+		if version == 0 {
+			return "must be a valid IP address"
+		}
+		return fmt.Sprintf("must be a valid IPv%d address", version)
+	}
+}
+
+func ruleHostname(value, _ string) string {
+	if len(value) > 253 || !hostnamePattern.MatchString(value) || net.ParseIP(value) != nil {
+		return "must be a valid hostname"
+	}
+	return ""
+}
+
+// ruleRegex matches the whole value against the parameter. Tag parameters
+// cannot contain ';', which separates rules.
+func ruleRegex(value, param string) string {
+	re, err := compileRuleRegex(param)
+	if err != nil {
+		return fmt.Sprintf("invalid regex parameter %q", param)
+	}
+	if !re.MatchString(value) {
+		return fmt.Sprintf("must match pattern %s", param)
+	}
+	return ""
+}
+
+func compileRuleRegex(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+// ruleDatetime parses the value with the Go layout given as parameter,
+// defaulting to RFC 3339.
+func ruleDatetime(value, param string) string {
+	layout := param
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	if _, err := time.Parse(layout, value); err != nil {
+		return fmt.Sprintf("must be a date and time in the format %s", layout)
+	}
+	return ""
+}
+
+func ruleAlpha(value, _ string) string {
+	for _, r := range value {
+		if !unicode.IsLetter(r) {
+			return "must contain only letters"
+		}
+	}
+	return ""
+}
+
+func ruleAlphanumeric(value, _ string) string {
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return "must contain only letters and digits"
+		}
+	}
+	return ""
+}
+
+// ruleJSON accepts strings, byte slices, and json.RawMessage holding valid
+// JSON.
+func ruleJSON(value reflect.Value, _ string) string {
+	var data []byte
+	switch {
+	case value.Kind() == reflect.String:
+		data = []byte(value.String())
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		data = value.Bytes()
+	default:
+		return fmt.Sprintf("json is not supported for %s", value.Kind())
+	}
+	if !json.Valid(data) {
+		return "must be valid JSON"
+	}
+	return ""
+}
@@ -0,0 +1,2 @@
+// Package schema compiles, generates, and validates JSON Schemas for request bodies.
+package schema
@@ -0,0 +1,252 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	dateType            = reflect.TypeFor[binder.Date]()
+	rawMessageType      = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Generate derives a schema from a Go type using its JSON field names.
+// Fields tagged `required:"true"` are required, pointer fields also accept
+// null, and recursive types are described through $defs.
+func Generate(t reflect.Type) (*Schema, error) {
+	g := &generator{defs: map[string]*Schema{}, names: map[reflect.Type]string{}, active: map[reflect.Type]bool{}}
+	s, err := g.schema(t)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+	if err := s.prepare(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ForBody generates the schema of the `body` field of the input type T.
+func ForBody[T any]() (*Schema, error) {
+	t := reflect.TypeFor[T]()
+	field, ok := bodyField(t, map[reflect.Type]bool{})
+	if !ok {
+		return nil, fmt.Errorf("schema: %s has no body field", t)
+	}
+	return Generate(field.Type)
+}
+
+// MustForBody is like ForBody but panics on error.
+func MustForBody[T any]() *Schema {
+	s, err := ForBody[T]()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// bodyField finds the body field of t, skipping types already searched so
+// self-embedding types terminate.
+func bodyField(t reflect.Type, seen map[reflect.Type]bool) (reflect.StructField, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return reflect.StructField{}, false
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup("body"); ok {
+			return field, true
+		}
+		if field.Anonymous || field.Type.Kind() == reflect.Struct && field.Tag == "" {
+			if nested, ok := bodyField(field.Type, seen); ok {
+				return nested, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+type generator struct {
+	defs   map[string]*Schema
+	names  map[reflect.Type]string
+	active map[reflect.Type]bool
+}
+
+func (g *generator) schema(t reflect.Type) (*Schema, error) {
+	if t.Kind() == reflect.Pointer {
+		s, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case s.Ref != "":
+			return &Schema{AnyOf: []*Schema{s, {Type: Types{"null"}}}}, nil
+		case len(s.Type) > 0 && !typeAllowed(s.Type, "null"):
+			s.Type = append(append(Types{}, s.Type...), "null")
+		}
+		return s, nil
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}, nil
+	case t == dateType:
+		return &Schema{Type: Types{"string"}, Format: "date"}, nil
+	case t == rawMessageType:
+		return &Schema{}, nil
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return &Schema{Type: Types{"string"}}, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}, nil
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: Types{"integer"}}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		zero := 0.0
+		return &Schema{Type: Types{"integer"}, Minimum: &zero}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{"string"}}, nil
+		}
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s := &Schema{Type: Types{"array"}, Items: items}
+		if t.Kind() == reflect.Slice {
+			s.Type = Types{"array", "null"}
+		} else {
+			n := t.Len()
+			s.MinItems, s.MaxItems = &n, &n
+		}
+		return s, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String && !t.Key().Implements(textMarshalerType) {
+			return nil, fmt.Errorf("schema: unsupported map key type %s", t.Key())
+		}
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: Types{"object", "null"}, AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.object(t)
+	default:
+		return nil, fmt.Errorf("schema: unsupported type %s", t)
+	}
+}
+
+// object describes a struct. A struct reached again while it is being
+// described becomes a $ref to its $defs entry.
+func (g *generator) object(t reflect.Type) (*Schema, error) {
+	if g.active[t] {
+		return &Schema{Ref: "#/$defs/" + g.defName(t)}, nil
+	}
+	g.active[t] = true
+	defer delete(g.active, t)
+
+	s := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+	if err := g.fields(s, t); err != nil {
+		return nil, err
+	}
+	if name, ok := g.names[t]; ok {
+		g.defs[name] = s
+		return &Schema{Ref: "#/$defs/" + name}, nil
+	}
+	return s, nil
+}
+
+func (g *generator) fields(s *Schema, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				// Fields of a type already being described are in place, so
+				// a type embedding itself is inlined once.
+				if g.active[embedded] {
+					continue
+				}
+				g.active[embedded] = true
+				err := g.fields(s, embedded)
+				delete(g.active, embedded)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		prop, err := g.schema(field.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		name = firstNonEmpty(name, field.Name)
+		s.Properties[name] = prop
+		if req := strings.ToLower(strings.TrimSpace(field.Tag.Get("required"))); req == "true" || req == "1" || req == "yes" {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return nil
+}
+
+func (g *generator) defName(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := firstNonEmpty(t.Name(), "Object")
+	base := name
+	for n := 2; g.taken(name); n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	g.names[t] = name
+	return name
+}
+
+func (g *generator) taken(name string) bool {
+	for _, n := range g.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
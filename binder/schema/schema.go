@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Schema is a JSON Schema document. It covers the keywords needed to describe
+// request bodies: types, object properties, arrays, enums, numeric and length
+// bounds, patterns, formats, anyOf, and local $ref pointers into $defs.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+
+	// boolean is set for the literal true and false schemas.
+	boolean *bool
+	pattern *regexp.Regexp
+}
+
+// Types lists the JSON types a value may have. It encodes as a string when it
+// holds a single type.
+type Types []string
+
+// UnmarshalJSON accepts a single type name or a list.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	*t = many
+	return nil
+}
+
+// MarshalJSON writes a single type as a string.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// schemaFields avoids recursing into Schema's own (un)marshalers.
+type schemaFields Schema
+
+// UnmarshalJSON accepts schema objects and the boolean schemas true and false.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "true", "false":
+		b := string(bytes.TrimSpace(data)) == "true"
+		*s = Schema{boolean: &b}
+		return nil
+	}
+	var fields schemaFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*s = Schema(fields)
+	return nil
+}
+
+// MarshalJSON writes boolean schemas as true or false.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.boolean != nil {
+		return json.Marshal(*s.boolean)
+	}
+	return json.Marshal((*schemaFields)(s))
+}
+
+// Bool returns the schema that accepts everything (true) or nothing (false).
+func Bool(accept bool) *Schema {
+	return &Schema{boolean: &accept}
+}
+
+// Compile parses a JSON Schema document and prepares it for validation.
+func Compile(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	if err := s.prepare(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// MustCompile is like Compile but panics on error.
+func MustCompile(data []byte) *Schema {
+	s, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// prepare compiles patterns and checks $ref targets throughout the document.
+func (s *Schema) prepare() error {
+	return s.walk(s, "#")
+}
+
+func (s *Schema) walk(root *Schema, at string) error {
+	if s == nil {
+		return nil
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("schema %s: invalid pattern: %w", at, err)
+		}
+		s.pattern = re
+	}
+	if s.Ref != "" {
+		if _, err := root.resolve(s.Ref); err != nil {
+			return fmt.Errorf("schema %s: %w", at, err)
+		}
+	}
+	for name, def := range s.Defs {
+		if err := def.walk(root, at+"/$defs/"+name); err != nil {
+			return err
+		}
+	}
+	for name, prop := range s.Properties {
+		if err := prop.walk(root, at+"/properties/"+name); err != nil {
+			return err
+		}
+	}
+	for i, alt := range s.AnyOf {
+		if err := alt.walk(root, fmt.Sprintf("%s/anyOf/%d", at, i)); err != nil {
+			return err
+		}
+	}
+	if err := s.AdditionalProperties.walk(root, at+"/additionalProperties"); err != nil {
+		return err
+	}
+	return s.Items.walk(root, at+"/items")
+}
+
+func (s *Schema) resolve(ref string) (*Schema, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q: only #/$defs/<name> is supported", ref)
+	}
+	def, ok := s.Defs[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %q", ref)
+	}
+	return def, nil
+}
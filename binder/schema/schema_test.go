@@ -0,0 +1,128 @@
+package schema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/binder/schema"
+)
+
+func TestCompile_ValidatesDocuments(t *testing.T) {
+	s, err := schema.Compile([]byte(`{
+		"type": "object",
+		"required": ["name", "tags"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+			"role": {"enum": ["admin", "user"]},
+			"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+			"a/b": {"$ref": "#/$defs/flag"}
+		},
+		"$defs": {"flag": {"type": "boolean"}}
+	}`))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	violations, err := s.Validate([]byte(`{"name":"Bo","age":1.5,"role":"root","tags":["a",1,"c"],"a/b":"yes","extra":1}`))
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	got := map[string]string{}
+	for _, v := range violations {
+		got[v.Pointer] = v.Message
+	}
+	want := map[string]string{
+		"/name":   "must match pattern ^[a-z]+$",
+		"/age":    "expected integer, got number",
+		"/role":   `must be one of ["admin","user"]`,
+		"/tags":   "must contain at most 2 items",
+		"/tags/1": "expected string, got integer",
+		"/a~1b":   "expected boolean, got string",
+		"/extra":  "unknown property",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected violations:\n got %v\nwant %v", got, want)
+	}
+
+	violations, _ = s.Validate([]byte(`{"tags":[]}`))
+	if len(violations) != 1 || violations[0].Pointer != "/name" || violations[0].Message != "missing required property" {
+		t.Fatalf("expected missing name, got %v", violations)
+	}
+	if violations, _ := s.Validate([]byte(`{"name":"bob","tags":["x"],"age":30}`)); len(violations) != 0 {
+		t.Fatalf("expected valid document, got %v", violations)
+	}
+
+	if _, err := schema.Compile([]byte(`{"$ref": "#/$defs/missing"}`)); err == nil || !strings.Contains(err.Error(), "unresolved") {
+		t.Fatalf("expected unresolved ref error, got %v", err)
+	}
+}
+
+type address struct {
+	City string `json:"city" required:"true"`
+}
+
+type node struct {
+	Name     string    `json:"name" required:"true"`
+	Count    uint      `json:"count,omitempty"`
+	When     time.Time `json:"when"`
+	Address  *address  `json:"address"`
+	Next     *node     `json:"next"`
+	Children []node    `json:"children"`
+	Labels   map[string]int
+	internal string
+}
+
+func TestGenerate_DescribesGoTypes(t *testing.T) {
+	s, err := schema.Generate(reflect.TypeFor[node]())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	valid := `{"name":"root","count":1,"when":"2024-01-01T00:00:00Z","address":{"city":"x"},
+		"next":{"name":"n","next":null},"children":[{"name":"c"}],"Labels":{"a":1}}`
+	if violations, err := s.Validate([]byte(valid)); err != nil || len(violations) != 0 {
+		t.Fatalf("expected valid document, got %v %v", violations, err)
+	}
+
+	invalid := `{"count":-1,"when":"yesterday","address":{},"next":{"name":3},"children":[{}],"Labels":{"a":"b"}}`
+	violations, err := s.Validate([]byte(invalid))
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	got := map[string]bool{}
+	for _, v := range violations {
+		got[v.Pointer] = true
+	}
+	for _, ptr := range []string{"/name", "/count", "/when", "/address/city", "/next/name", "/children/0/name", "/Labels/a"} {
+		if !got[ptr] {
+			t.Fatalf("expected violation at %s, got %v", ptr, violations)
+		}
+	}
+}
+
+type SelfEmbedding struct {
+	*SelfEmbedding
+	Name string `json:"name" required:"true"`
+}
+
+type SelfEmbeddingInput struct {
+	*SelfEmbeddingInput
+	Body SelfEmbedding `body:"json"`
+}
+
+func TestForBody_SelfEmbeddingTypes(t *testing.T) {
+	s, err := schema.ForBody[SelfEmbeddingInput]()
+	if err != nil {
+		t.Fatalf("for body: %v", err)
+	}
+	if violations, err := s.Validate([]byte(`{"name":"x"}`)); err != nil || len(violations) != 0 {
+		t.Fatalf("expected valid document, got %v %v", violations, err)
+	}
+	violations, err := s.Validate([]byte(`{}`))
+	if err != nil || len(violations) != 1 || violations[0].Pointer != "/name" {
+		t.Fatalf("expected one violation at /name, got %v %v", violations, err)
+	}
+}
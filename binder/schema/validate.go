@@ -0,0 +1,299 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+// Violation is a single schema failure located by a JSON Pointer.
+type Violation struct {
+	Pointer string
+	Message string
+}
+
+// Validate checks the JSON document data against s. It returns an error only
+// when data is not valid JSON.
+func (s *Schema) Validate(data []byte) ([]Violation, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	var out []Violation
+	s.check(doc, "", s, &out)
+	return out, nil
+}
+
+// ValidateBody implements binder.BodySchema. Violations become body field
+// errors keyed by JSON Pointer; malformed JSON is left to the body decoder.
+func (s *Schema) ValidateBody(data []byte) []binder.FieldError {
+	violations, err := s.Validate(data)
+	if err != nil {
+		return nil
+	}
+	fields := make([]binder.FieldError, 0, len(violations))
+	for _, v := range violations {
+		fields = append(fields, binder.NewFieldError(v.Pointer, binder.SourceBody, v.Message))
+	}
+	return fields
+}
+
+func (s *Schema) check(v any, ptr string, root *Schema, out *[]Violation) {
+	if s == nil {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*out = append(*out, Violation{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.boolean != nil {
+		if !*s.boolean {
+			fail("value is not allowed")
+		}
+		return
+	}
+	if s.Ref != "" {
+		target, err := root.resolve(s.Ref)
+		if err != nil {
+			fail("%v", err)
+			return
+		}
+		target.check(v, ptr, root, out)
+		return
+	}
+
+	if len(s.AnyOf) > 0 && !s.matchesAny(v, ptr, root, out) {
+		return
+	}
+
+	actual := jsonType(v)
+	if len(s.Type) > 0 && !typeAllowed(s.Type, actual) {
+		fail("expected %s, got %s", strings.Join(s.Type, " or "), actual)
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		fail("must be one of %s", enumList(s.Enum))
+	}
+
+	switch val := v.(type) {
+	case string:
+		s.checkString(val, fail)
+	case json.Number:
+		s.checkNumber(val, fail)
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			fail("must contain at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			fail("must contain at most %d items", *s.MaxItems)
+		}
+		for i, item := range val {
+			s.Items.check(item, ptr+"/"+strconv.Itoa(i), root, out)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				*out = append(*out, Violation{Pointer: ptr + "/" + escapePointer(name), Message: "missing required property"})
+			}
+		}
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := ptr + "/" + escapePointer(name)
+			if prop, ok := s.Properties[name]; ok {
+				prop.check(val[name], child, root, out)
+				continue
+			}
+			if s.AdditionalProperties != nil {
+				if b := s.AdditionalProperties.boolean; b != nil && !*b {
+					*out = append(*out, Violation{Pointer: child, Message: "unknown property"})
+					continue
+				}
+				s.AdditionalProperties.check(val[name], child, root, out)
+			}
+		}
+	}
+}
+
+// matchesAny reports whether v satisfies one of the anyOf schemas; otherwise
+// it reports the violations of the closest alternative.
+func (s *Schema) matchesAny(v any, ptr string, root *Schema, out *[]Violation) bool {
+	var best []Violation
+	for i, alt := range s.AnyOf {
+		var violations []Violation
+		alt.check(v, ptr, root, &violations)
+		if len(violations) == 0 {
+			return true
+		}
+		if i == 0 || len(violations) < len(best) {
+			best = violations
+		}
+	}
+	*out = append(*out, best...)
+	return false
+}
+
+func (s *Schema) checkString(val string, fail func(string, ...any)) {
+	length := utf8.RuneCountInString(val)
+	if s.MinLength != nil && length < *s.MinLength {
+		fail("must be at least %d characters", *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		fail("must be at most %d characters", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		re := s.pattern
+		if re == nil {
+			var err error
+			if re, err = regexp.Compile(s.Pattern); err != nil {
+				fail("invalid pattern: %v", err)
+				return
+			}
+		}
+		if !re.MatchString(val) {
+			fail("must match pattern %s", s.Pattern)
+		}
+	}
+	if msg := checkFormat(s.Format, val); msg != "" {
+		fail("%s", msg)
+	}
+}
+
+func (s *Schema) checkNumber(val json.Number, fail func(string, ...any)) {
+	f, err := val.Float64()
+	if err != nil {
+		fail("invalid number")
+		return
+	}
+	if s.Minimum != nil && f < *s.Minimum {
+		fail("must be >= %v", *s.Minimum)
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		fail("must be <= %v", *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && f <= *s.ExclusiveMinimum {
+		fail("must be > %v", *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && f >= *s.ExclusiveMaximum {
+		fail("must be < %v", *s.ExclusiveMaximum)
+	}
+}
+
+var (
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// checkFormat validates the formats generated for Go types plus email and
+// uuid; unknown formats are annotations only.
+func checkFormat(format, val string) string {
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, val); err != nil {
+			return "must be an RFC 3339 date-time"
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, val); err != nil {
+			return "must be a date in YYYY-MM-DD format"
+		}
+	case "email":
+		if !emailPattern.MatchString(val) {
+			return "must be an email address"
+		}
+	case "uuid":
+		if !uuidPattern.MatchString(val) {
+			return "must be a UUID"
+		}
+	}
+	return ""
+}
+
+func jsonType(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if f, err := val.Float64(); err == nil && f == math.Trunc(f) && !strings.ContainsAny(val.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func typeAllowed(types Types, actual string) bool {
+	for _, t := range types {
+		if t == actual || t == "number" && actual == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, candidate := range enum {
+		if equalJSON(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalJSON compares decoded values, treating numbers by value.
+func equalJSON(a, b any) bool {
+	an, aNum := toFloat(a)
+	bn, bNum := toFloat(b)
+	if aNum || bNum {
+		return aNum && bNum && an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func enumList(enum []any) string {
+	data, err := json.Marshal(enum)
+	if err != nil {
+		return fmt.Sprint(enum)
+	}
+	return string(data)
+}
+
+// escapePointer escapes a property name as a JSON Pointer reference token.
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,175 @@
+package binder
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StrictParams rejects request parameters that no bound field declares,
+// mirroring StrictJSONBodies for the rest of the request.
+type StrictParams struct {
+	// Query rejects undeclared query parameters other than AllowedQuery.
+	Query bool
+	// AllowedQuery lists further query parameters accepted in strict mode,
+	// e.g. parameters read by middleware.
+	AllowedQuery []string
+	// Headers rejects undeclared headers. Standard HTTP, CORS, tracing, and
+	// proxy headers are always accepted, as are AllowedHeaders.
+	Headers bool
+	// AllowedHeaders lists further headers accepted in strict header mode.
+	AllowedHeaders []string
+}
+
+// WithStrictParams returns a copy that rejects undeclared parameters.
+func (b *DefaultBinder) WithStrictParams(strict StrictParams) *DefaultBinder {
+	copy := *b
+	copy.Strict = strict
+	return &copy
+}
+
+// SetStrictParams configures strict parameter checks in place.
+func (b *DefaultBinder) SetStrictParams(strict StrictParams) {
+	b.Strict = strict
+}
+
+// standardHeaders are accepted in strict header mode regardless of bindings.
+var standardHeaders = map[string]bool{
+	"Accept": true, "Accept-Charset": true, "Accept-Encoding": true, "Accept-Language": true,
+	"Authorization": true, "Cache-Control": true, "Connection": true, "Content-Encoding": true,
+	"Content-Language": true, "Content-Length": true, "Content-Type": true, "Cookie": true,
+	"Date": true, "Dnt": true, "Expect": true, "Forwarded": true, "From": true, "Host": true,
+	"If-Match": true, "If-Modified-Since": true, "If-None-Match": true, "If-Range": true,
+	"If-Unmodified-Since": true, "Keep-Alive": true, "Origin": true, "Pragma": true,
+	"Priority": true, "Proxy-Authorization": true, "Range": true, "Referer": true, "Te": true,
+	"Trailer": true, "Transfer-Encoding": true, "Upgrade": true, "Upgrade-Insecure-Requests": true,
+	"User-Agent": true, "Via": true, "Traceparent": true, "Tracestate": true, "Baggage": true,
+	"X-Request-Id": true, "X-Correlation-Id": true, "X-Forwarded-For": true,
+	"X-Forwarded-Host": true, "X-Forwarded-Proto": true, "X-Forwarded-Port": true,
+	"X-Real-Ip": true, "X-Requested-With": true,
+}
+
+var standardHeaderPrefixes = []string{"Sec-", "Access-Control-Request-"}
+
+// declaredParams lists the query parameters and headers an input type binds.
+type declaredParams struct {
+	query        map[string]bool
+	queryObjects []string
+	allQuery     bool
+	headers      map[string]bool
+	allHeaders   bool
+}
+
+var declaredCache sync.Map // reflect.Type -> *declaredParams
+
+func declaredFor(t reflect.Type) *declaredParams {
+	if cached, ok := declaredCache.Load(t); ok {
+		return cached.(*declaredParams)
+	}
+	d := &declaredParams{query: map[string]bool{}, headers: map[string]bool{}}
+	d.collect(t, map[reflect.Type]bool{})
+	declaredCache.Store(t, d)
+	return d
+}
+
+func (d *declaredParams) collect(t reflect.Type, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag, ok := field.Tag.Lookup("query"); ok {
+			qt := parseQueryTag(tag, field)
+			switch {
+			case qt.all:
+				d.allQuery = true
+			case isNestedFormStruct(field.Type):
+				d.queryObjects = append(d.queryObjects, qt.name+"[")
+			default:
+				d.query[qt.name] = true
+				d.query[qt.name+"[]"] = true
+			}
+		}
+		// A field may fall back from one source to another, so both the
+		// query and header tags are recorded.
+		if tag, ok := field.Tag.Lookup("header"); ok {
+			key, opts, _ := strings.Cut(tag, ",")
+			if hasTagOption(opts, "all") {
+				d.allHeaders = true
+			} else {
+				d.headers[http.CanonicalHeaderKey(firstNonEmpty(key, field.Name))] = true
+			}
+			continue
+		}
+		if hasBindingTag(field) {
+			continue
+		}
+		if field.Anonymous || field.Type.Kind() == reflect.Struct && field.Tag == "" {
+			d.collect(field.Type, seen)
+		}
+	}
+}
+
+// unknownParams reports the query parameters and headers of r that no field
+// of t declares.
+func (s StrictParams) unknownParams(t reflect.Type, info requestInfo) []FieldError {
+	if !s.Query && !s.Headers {
+		return nil
+	}
+	d := declaredFor(t)
+	var fields []FieldError
+	if s.Query && !d.allQuery {
+		for _, key := range sortedKeys(info.query) {
+			if !d.query[key] && !hasAnyPrefix(key, d.queryObjects) && !contains(s.AllowedQuery, key) {
+				fields = append(fields, FieldError{Field: key, Source: SourceQuery, Message: "unknown query parameter"})
+			}
+		}
+	}
+	if s.Headers && !d.allHeaders {
+		allowed := make(map[string]bool, len(s.AllowedHeaders))
+		for _, h := range s.AllowedHeaders {
+			allowed[http.CanonicalHeaderKey(h)] = true
+		}
+		for _, key := range sortedKeys(info.request.Header) {
+			key = http.CanonicalHeaderKey(key)
+			if d.headers[key] || allowed[key] || standardHeaders[key] || hasAnyPrefix(key, standardHeaderPrefixes) {
+				continue
+			}
+			fields = append(fields, FieldError{Field: key, Source: SourceHeader, Message: "unknown header"})
+		}
+	}
+	return fields
+}
+
+func sortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package binder_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+type strictInput struct {
+	Paging
+	Filter struct {
+		Name string `json:"name"`
+	} `query:"filter"`
+	Sort   string `query:"sort"`
+	Tenant string `header:"X-Tenant"`
+}
+
+func TestDefaultBinder_StrictParamsRejectUnknownKeys(t *testing.T) {
+	b := binder.NewDefaultBinder().WithStrictParams(binder.StrictParams{
+		Query:          true,
+		Headers:        true,
+		AllowedHeaders: []string{"X-Debug"},
+	})
+
+	ok := httptest.NewRequest(http.MethodGet, "/?limit=1&offset=2&sort=name&filter[name]=x", nil)
+	ok.Header.Set("X-Tenant", "acme")
+	ok.Header.Set("X-Debug", "1")
+	ok.Header.Set("User-Agent", "test")
+	ok.Header.Set("X-Request-ID", "abc")
+	if err := b.Bind(context.Background(), ok, &strictInput{}); err != nil {
+		t.Fatalf("expected declared params to bind, got %v", err)
+	}
+
+	bad := httptest.NewRequest(http.MethodGet, "/?sort=name&page=2&debug=1", nil)
+	bad.Header.Set("X-Unknown", "1")
+	err := b.Bind(context.Background(), bad, &strictInput{})
+	bindErr, isBindErr := err.(*binder.BindError)
+	if !isBindErr {
+		t.Fatalf("expected BindError, got %v", err)
+	}
+	fields := bindErr.Fields()
+	if len(fields) != 3 || fields[0].Field != "debug" || fields[1].Field != "page" ||
+		fields[2].Field != "X-Unknown" || fields[2].Source != binder.SourceHeader {
+		t.Fatalf("unexpected unknown params: %+v", fields)
+	}
+
+	var collect struct {
+		All map[string]string `query:",all"`
+	}
+	if err := b.Bind(context.Background(), httptest.NewRequest(http.MethodGet, "/?anything=1", nil), &collect); err != nil {
+		t.Fatalf("expected all-collecting input to accept any query, got %v", err)
+	}
+}
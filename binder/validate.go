@@ -0,0 +1,393 @@
+package binder
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Validator validates a bound destination after decoding. Validation failures
+// should be reported as *BindError; any other error is returned unchanged.
+type Validator interface {
+	Validate(ctx context.Context, dest any) error
+}
+
+// ValidatorFunc lifts a function into a Validator.
+type ValidatorFunc func(ctx context.Context, dest any) error
+
+// Validate implements Validator.
+func (f ValidatorFunc) Validate(ctx context.Context, dest any) error {
+	return f(ctx, dest)
+}
+
+// ValidationRule checks a single value against the rule parameter and returns
+// a human-readable message describing the failure, or "" when valid.
+type ValidationRule func(value reflect.Value, param string) string
+
+// TagValidator validates struct fields using `validate:"..."` tags. Rules are
+// separated by semicolons, e.g. `validate:"string;min=3;max=20"`. Leading type
+// hints (string, int, float, ...) are accepted and ignored.
+type TagValidator struct {
+	mu    sync.RWMutex
+	rules map[string]ValidationRule
+}
+
+// NewTagValidator returns a TagValidator with the built-in rules registered.
+func NewTagValidator() *TagValidator {
+	v := &TagValidator{rules: make(map[string]ValidationRule)}
+	for name, rule := range builtinRules() {
+		v.rules[name] = rule
+	}
+	return v
+}
+
+// RegisterRule adds or replaces a named rule.
+func (v *TagValidator) RegisterRule(name string, rule ValidationRule) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("validation rule name must not be empty")
+	}
+	if rule == nil {
+		return fmt.Errorf("validation rule %s must not be nil", name)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = rule
+	return nil
+}
+
+// Clone returns a validator with the same rules that can be extended
+// independently.
+func (v *TagValidator) Clone() *TagValidator {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	clone := &TagValidator{rules: make(map[string]ValidationRule, len(v.rules))}
+	for name, rule := range v.rules {
+		clone.rules[name] = rule
+	}
+	return clone
+}
+
+func (v *TagValidator) rule(name string) (ValidationRule, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	rule, ok := v.rules[name]
+	return rule, ok
+}
+
+// Validate implements Validator.
+func (v *TagValidator) Validate(ctx context.Context, dest any) error {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	rv := reflect.ValueOf(dest)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	var fieldErrors []FieldError
+	if err := v.validateStruct(rv, "", "", &fieldErrors); err != nil {
+		return err
+	}
+	if len(fieldErrors) > 0 {
+		return &BindError{
+			message: "Request failed validation",
+			fields:  fieldErrors,
+		}
+	}
+	return nil
+}
+
+func (v *TagValidator) validateStruct(rv reflect.Value, parent string, source FieldSource, fieldErrors *[]FieldError) error {
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Field(i)
+		fieldType := rt.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+		name := fieldType.Name
+		if parent != "" {
+			name = parent + "." + name
+		}
+		fieldSource := source
+		if tagSource := bindingSource(fieldType); tagSource != "" {
+			fieldSource = tagSource
+		}
+
+		if tag, ok := fieldType.Tag.Lookup("validate"); ok && tag != "-" {
+			if err := v.validateField(field, tag, name, fieldSource, fieldErrors); err != nil {
+				return err
+			}
+		}
+
+		nested := field
+		for nested.Kind() == reflect.Pointer {
+			if nested.IsNil() {
+				break
+			}
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && !isLeafStruct(nested.Type()) {
+			childParent := name
+			if fieldType.Anonymous {
+				childParent = parent
+			}
+			if err := v.validateStruct(nested, childParent, fieldSource, fieldErrors); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *TagValidator) validateField(field reflect.Value, tag, name string, source FieldSource, fieldErrors *[]FieldError) error {
+	rules := parseRules(tag)
+	omitEmpty := false
+	for _, r := range rules {
+		if r.name == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	for _, r := range rules {
+		if r.name == "omitempty" || typeHints[r.name] {
+			continue
+		}
+		if r.name == "required" {
+			if isZeroValue(field) {
+				appendFieldError(fieldErrors, FieldError{Field: name, Source: source, Message: "missing required value", Code: "required"})
+				return nil
+			}
+			continue
+		}
+		rule, ok := v.rule(r.name)
+		if !ok {
+			return fmt.Errorf("unknown validation rule %q on field %s", r.name, name)
+		}
+		value := field
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return nil
+			}
+			value = value.Elem()
+		}
+		if omitEmpty && value.IsZero() {
+			return nil
+		}
+		if msg := rule(value, r.param); msg != "" {
+			appendFieldError(fieldErrors, FieldError{Field: name, Source: source, Message: msg, Code: r.name})
+			return nil
+		}
+	}
+	return nil
+}
+
+// CheckType reports the first unknown rule in the `validate` tags of t and
+// its nested structs. The engine calls it when an endpoint is declared, so a
+// misspelled rule fails at startup instead of on every request.
+func (v *TagValidator) CheckType(t reflect.Type) error {
+	return v.checkType(t, map[reflect.Type]bool{})
+}
+
+func (v *TagValidator) checkType(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || isLeafStruct(t) || seen[t] {
+		return nil
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if tag, ok := field.Tag.Lookup("validate"); ok && tag != "-" {
+			for _, r := range parseRules(tag) {
+				if r.name == "omitempty" || r.name == "required" || typeHints[r.name] {
+					continue
+				}
+				if _, ok := v.rule(r.name); !ok {
+					return fmt.Errorf("unknown validation rule %q on field %s.%s", r.name, t.Name(), field.Name)
+				}
+			}
+		}
+		if err := v.checkType(field.Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateValue checks value against rules written as in a `validate` tag,
+// e.g. "uuid" or "string;min=3;regex=[a-z]+", for values that are not struct
+// fields. It returns the code and message of the first failure, or empty
+// strings when value is valid.
+func (v *TagValidator) ValidateValue(value any, rules string) (code, message string, err error) {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() {
+		// Treat nil like an unset pointer: only required fails.
+		rv = reflect.ValueOf((*struct{})(nil))
+	}
+	var fieldErrors []FieldError
+	if err := v.validateField(rv, rules, "", "", &fieldErrors); err != nil {
+		return "", "", err
+	}
+	if len(fieldErrors) == 0 {
+		return "", "", nil
+	}
+	return fieldErrors[0].Code, fieldErrors[0].Message, nil
+}
+
+type parsedRule struct {
+	name  string
+	param string
+}
+
+var typeHints = map[string]bool{
+	"string": true, "int": true, "uint": true, "float": true,
+	"bool": true, "time": true, "slice": true, "map": true, "struct": true,
+}
+
+func parseRules(tag string) []parsedRule {
+	parts := strings.Split(tag, ";")
+	out := make([]parsedRule, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(part, "=")
+		out = append(out, parsedRule{name: strings.TrimSpace(name), param: strings.TrimSpace(param)})
+	}
+	return out
+}
+
+func bindingSource(field reflect.StructField) FieldSource {
+	for _, source := range []FieldSource{SourcePath, SourceQuery, SourceHeader, SourceCookie, SourceBody} {
+		if _, ok := field.Tag.Lookup(string(source)); ok {
+			return source
+		}
+	}
+	return ""
+}
+
+func isLeafStruct(typ reflect.Type) bool {
+	return typ.Implements(textUnmarshalerType) || reflect.PointerTo(typ).Implements(textUnmarshalerType)
+}
+
+func isZeroValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+func builtinRules() map[string]ValidationRule {
+	return map[string]ValidationRule{
+		"min":          ruleMin,
+		"max":          ruleMax,
+		"len":          ruleLen,
+		"oneof":        ruleOneOf,
+		"uuid":         stringRule("uuid", ruleUUID),
+		"url":          stringRule("url", ruleURL),
+		"ip":           stringRule("ip", ruleIP(0)),
+		"ipv4":         stringRule("ipv4", ruleIP(4)),
+		"ipv6":         stringRule("ipv6", ruleIP(6)),
+		"hostname":     stringRule("hostname", ruleHostname),
+		"regex":        stringRule("regex", ruleRegex),
+		"datetime":     stringRule("datetime", ruleDatetime),
+		"alpha":        stringRule("alpha", ruleAlpha),
+		"alphanumeric": stringRule("alphanumeric", ruleAlphanumeric),
+		"json":         ruleJSON,
+	}
+}
+
+func ruleMin(value reflect.Value, param string) string {
+	return compareBound(value, param, func(got, limit float64) bool { return got >= limit }, "at least")
+}
+
+func ruleMax(value reflect.Value, param string) string {
+	return compareBound(value, param, func(got, limit float64) bool { return got <= limit }, "at most")
+}
+
+func ruleLen(value reflect.Value, param string) string {
+	want, err := strconv.Atoi(param)
+	if err != nil {
+		return fmt.Sprintf("invalid len parameter %q", param)
+	}
+	switch value.Kind() {
+	case reflect.String:
+		if utf8.RuneCountInString(value.String()) != want {
+			return fmt.Sprintf("must be exactly %d characters", want)
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if value.Len() != want {
+			return fmt.Sprintf("must contain exactly %d items", want)
+		}
+	default:
+		return fmt.Sprintf("len is not supported for %s", value.Kind())
+	}
+	return ""
+}
+
+func ruleOneOf(value reflect.Value, param string) string {
+	options := strings.Split(param, "|")
+	got := fmt.Sprint(value.Interface())
+	for _, option := range options {
+		if got == strings.TrimSpace(option) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("must be one of %s", strings.Join(options, ", "))
+}
+
+func compareBound(value reflect.Value, param string, ok func(got, limit float64) bool, word string) string {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Sprintf("invalid bound parameter %q", param)
+	}
+	switch value.Kind() {
+	case reflect.String:
+		if !ok(float64(utf8.RuneCountInString(value.String())), limit) {
+			return fmt.Sprintf("must be %s %s characters", word, param)
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if !ok(float64(value.Len()), limit) {
+			return fmt.Sprintf("must contain %s %s items", word, param)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !ok(float64(value.Int()), limit) {
+			return fmt.Sprintf("must be %s %s", word, param)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !ok(float64(value.Uint()), limit) {
+			return fmt.Sprintf("must be %s %s", word, param)
+		}
+	case reflect.Float32, reflect.Float64:
+		if !ok(value.Float(), limit) {
+			return fmt.Sprintf("must be %s %s", word, param)
+		}
+	default:
+		return fmt.Sprintf("bound checks are not supported for %s", value.Kind())
+	}
+	return ""
+}
@@ -0,0 +1,246 @@
+package binder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validatedBody struct {
+	Email string `json:"email" validate:"required"`
+	Tags  []string
+}
+
+type validatedInput struct {
+	Name   string         `query:"name" validate:"string;min=3;max=10"`
+	Limit  int            `query:"limit" validate:"int;min=1;max=100"`
+	Sort   string         `query:"sort" validate:"omitempty;oneof=asc|desc"`
+	Mode   *string        `query:"mode" validate:"len=4"`
+	Body   validatedBody  `body:""`
+	Nested *validatedBody `validate:"-"`
+}
+
+func TestTagValidator_ReportsFieldErrors(t *testing.T) {
+	b := NewDefaultBinder().WithValidator(NewTagValidator())
+
+	req := httptest.NewRequest(http.MethodPost, "/?name=al&limit=500&sort=up&mode=fast", strings.NewReader(`{"email":""}`))
+	var dst validatedInput
+	err := b.Bind(req.Context(), req, &dst)
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("expected BindError, got %v", err)
+	}
+	got := map[string]FieldError{}
+	for _, fe := range bindErr.Fields() {
+		got[fe.Field] = fe
+	}
+	for _, field := range []string{"Name", "Limit", "Sort", "Body.Email"} {
+		if _, ok := got[field]; !ok {
+			t.Fatalf("expected field error for %s, got %+v", field, bindErr.Fields())
+		}
+	}
+	if _, ok := got["Mode"]; ok {
+		t.Fatalf("did not expect error for valid mode")
+	}
+	if got["Name"].Source != SourceQuery || got["Body.Email"].Source != SourceBody {
+		t.Fatalf("unexpected sources: %+v", bindErr.Fields())
+	}
+}
+
+func TestTagValidator_PassesValidInput(t *testing.T) {
+	b := NewDefaultBinder().WithValidator(NewTagValidator())
+
+	req := httptest.NewRequest(http.MethodPost, "/?name=alice&limit=5", strings.NewReader(`{"email":"a@example.com"}`))
+	var dst validatedInput
+	if err := b.Bind(req.Context(), req, &dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTagValidator_CustomAndUnknownRules(t *testing.T) {
+	v := NewTagValidator()
+	if err := v.RegisterRule("even", func(value reflect.Value, _ string) string {
+		if value.Int()%2 != 0 {
+			return "must be even"
+		}
+		return ""
+	}); err != nil {
+		t.Fatalf("register rule: %v", err)
+	}
+
+	type input struct {
+		N int `validate:"even"`
+	}
+	err := v.Validate(context.Background(), &input{N: 3})
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) || bindErr.Fields()[0].Message != "must be even" {
+		t.Fatalf("expected custom rule failure, got %v", err)
+	}
+
+	type unknown struct {
+		N int `validate:"bogus"`
+	}
+	err = v.Validate(context.Background(), &unknown{})
+	if err == nil || errors.As(err, &bindErr) {
+		t.Fatalf("expected configuration error for unknown rule, got %v", err)
+	}
+}
+
+func TestTagValidator_CheckTypeReportsUnknownRules(t *testing.T) {
+	v := NewTagValidator()
+	type nested struct {
+		Code string `validate:"omitempty;uuidv4"`
+	}
+	type input struct {
+		Name  string `validate:"required;string;min=3"`
+		Inner *nested
+	}
+	err := v.CheckType(reflect.TypeOf(input{}))
+	if err == nil || !strings.Contains(err.Error(), `"uuidv4"`) || !strings.Contains(err.Error(), "nested.Code") {
+		t.Fatalf("expected unknown nested rule reported, got %v", err)
+	}
+	if err := v.CheckType(reflect.TypeOf(&validatedInput{})); err != nil {
+		t.Fatalf("unexpected error for known rules: %v", err)
+	}
+}
+
+func TestStructValidator_MapsValidateErrors(t *testing.T) {
+	v := NewStructValidator(nil)
+	err := v.Validate(context.Background(), &validatedInput{Name: "ab"})
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("expected bind error, got %v", err)
+	}
+	field := bindErr.Fields()[0]
+	if field.Field != "Name" || field.Source != SourceQuery || field.Code != "min" || field.Message == "" {
+		t.Fatalf("unexpected field error: %+v", field)
+	}
+	if err := v.Validate(context.Background(), &validatedInput{Name: "alice"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBind_AggregatesConversionAndValidationFailures(t *testing.T) {
+	b := NewDefaultBinder().WithValidator(NewTagValidator())
+
+	req := httptest.NewRequest(http.MethodPost, "/?name=al&limit=many", strings.NewReader(`{"email":""}`))
+	var dst validatedInput
+	err := b.Bind(req.Context(), req, &dst)
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("expected BindError, got %v", err)
+	}
+	counts := map[string]int{}
+	for _, fe := range bindErr.Fields() {
+		counts[fe.Field]++
+	}
+	if counts["Limit"] != 1 || counts["Name"] != 1 || counts["Body.Email"] != 1 {
+		t.Fatalf("expected one failure each for Limit, Name, and Body.Email, got %+v", bindErr.Fields())
+	}
+}
+
+func TestValidationErrors_CollectsAcrossLayers(t *testing.T) {
+	violations := NewValidationErrors()
+	ctx := WithValidationErrors(context.Background(), violations)
+	if violations.Err() != nil {
+		t.Fatalf("expected no error when empty")
+	}
+	if !violations.AddError(NewBindError("Unknown request parameters", []FieldError{NewFieldError("q", SourceQuery, "unknown")})) {
+		t.Fatalf("expected bind error absorbed")
+	}
+	if violations.AddError(NewBindError("Invalid body", nil).WithCause(errors.New("syntax"))) {
+		t.Fatalf("expected bind error with cause left to the caller")
+	}
+	if !ReportFieldError(ctx, "Email", SourceBody, "already registered") {
+		t.Fatalf("expected collector in context")
+	}
+	if ReportFieldError(context.Background(), "Email", SourceBody, "ignored") {
+		t.Fatalf("expected no collector outside a request")
+	}
+
+	var bindErr *BindError
+	if err := violations.Err(); !errors.As(err, &bindErr) || bindErr.Message() != "Unknown request parameters" || len(bindErr.Fields()) != 2 {
+		t.Fatalf("unexpected aggregate: %v", err)
+	}
+	var nilCollector *ValidationErrors
+	nilCollector.Add(NewFieldError("x", SourceQuery, "ignored"))
+	if nilCollector.Len() != 0 || nilCollector.Err() != nil {
+		t.Fatalf("expected nil collector to be inert")
+	}
+}
+
+func TestTagValidator_BuiltinFormatRules(t *testing.T) {
+	type input struct {
+		ID       string          `validate:"uuid"`
+		Site     string          `validate:"url=https"`
+		Addr     string          `validate:"ipv4"`
+		Addr6    string          `validate:"ipv6"`
+		Host     string          `validate:"hostname"`
+		Slug     string          `validate:"regex=[a-z0-9-]+"`
+		At       string          `validate:"datetime=2006-01-02"`
+		Name     string          `validate:"alpha"`
+		Code     string          `validate:"alphanumeric"`
+		Meta     json.RawMessage `validate:"json"`
+		Ratio    float64         `validate:"float;min=0.5;max=1.5"`
+		Optional string          `validate:"omitempty;uuid"`
+	}
+	valid := input{
+		ID: "7f1c2b9e-4a59-4d1e-9c1a-2f0b8e6d5c4a", Site: "https://example.com/a", Addr: "10.0.0.1",
+		Addr6: "2001:db8::1", Host: "api.example.com", Slug: "hello-world", At: "2026-10-16",
+		Name: "Zoë", Code: "abc123", Meta: json.RawMessage(`{"a":1}`), Ratio: 1.0,
+	}
+	v := NewTagValidator()
+	if err := v.Validate(context.Background(), &valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := input{
+		ID: "not-a-uuid", Site: "http://example.com", Addr: "2001:db8::1", Addr6: "10.0.0.1",
+		Host: "bad_host!", Slug: "Hello World", At: "16/10/2026", Name: "abc1", Code: "a-b",
+		Meta: json.RawMessage(`{"a":`), Ratio: 1.75,
+	}
+	err := v.Validate(context.Background(), &invalid)
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("expected BindError, got %v", err)
+	}
+	codes := map[string]string{}
+	for _, fe := range bindErr.Fields() {
+		codes[fe.Field] = fe.Code
+	}
+	want := map[string]string{
+		"ID": "uuid", "Site": "url", "Addr": "ipv4", "Addr6": "ipv6", "Host": "hostname", "Slug": "regex",
+		"At": "datetime", "Name": "alpha", "Code": "alphanumeric", "Meta": "json", "Ratio": "max",
+	}
+	for field, code := range want {
+		if codes[field] != code {
+			t.Fatalf("expected %s to fail %s, got %+v", field, code, bindErr.Fields())
+		}
+	}
+	if _, ok := codes["Optional"]; ok || len(codes) != len(want) {
+		t.Fatalf("unexpected failures: %+v", bindErr.Fields())
+	}
+}
+
+func TestTagValidator_ValidateValue(t *testing.T) {
+	v := NewTagValidator()
+	if code, msg, err := v.ValidateValue("example.com", "required;hostname"); err != nil || code != "" || msg != "" {
+		t.Fatalf("expected valid hostname, got %q %q %v", code, msg, err)
+	}
+	if code, msg, _ := v.ValidateValue("192.168.0.300", "ip"); code != "ip" || msg != "must be a valid IP address" {
+		t.Fatalf("expected ip failure, got %q %q", code, msg)
+	}
+	if code, _, _ := v.ValidateValue(nil, "required"); code != "required" {
+		t.Fatalf("expected required failure for nil, got %q", code)
+	}
+	if _, _, err := v.ValidateValue("x", "bogus"); err == nil {
+		t.Fatalf("expected unknown rule error")
+	}
+}
@@ -0,0 +1,74 @@
+package binder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/aatuh/validate/v3"
+)
+
+// StructValidator is the part of github.com/aatuh/validate/v3's validator
+// used by NewStructValidator.
+type StructValidator interface {
+	ValidateStruct(s any) error
+}
+
+// NewStructValidator adapts a github.com/aatuh/validate/v3 validator, or
+// validate.New() when v is nil, to Validator. Its validate.Errors become a
+// *BindError with one FieldError per failure; the source comes from the
+// binding tag of the top-level field the failure's path names.
+func NewStructValidator(v StructValidator) Validator {
+	if v == nil {
+		v = validate.New()
+	}
+	return ValidatorFunc(func(ctx context.Context, dest any) error {
+		if ctx != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		rv := reflect.ValueOf(dest)
+		for rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return nil
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return nil
+		}
+		err := v.ValidateStruct(rv.Interface())
+		var failures validate.Errors
+		if !errors.As(err, &failures) {
+			return err
+		}
+		fields := make([]FieldError, 0, len(failures))
+		for _, failure := range failures {
+			message := failure.Msg
+			if message == "" {
+				message = failure.Code
+			}
+			fields = append(fields, FieldError{
+				Field:   failure.Path,
+				Source:  pathSource(rv.Type(), failure.Path),
+				Message: message,
+				Code:    failure.Code,
+			})
+		}
+		return NewBindError("Request failed validation", fields).WithCause(err)
+	})
+}
+
+// pathSource returns the binding source of the top-level field named by a
+// path such as "Name" or "Filter.Tags[0]".
+func pathSource(t reflect.Type, path string) FieldSource {
+	name, _, _ := strings.Cut(path, ".")
+	name, _, _ = strings.Cut(name, "[")
+	field, ok := t.FieldByName(name)
+	if !ok {
+		return ""
+	}
+	return bindingSource(field)
+}
@@ -0,0 +1,131 @@
+package binder
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ValidationErrors collects field failures reported by the binder, input
+// hooks, and handlers so they render together as one "invalid_request"
+// response. Its methods are safe for concurrent use and do nothing on a nil
+// collector.
+type ValidationErrors struct {
+	mu      sync.Mutex
+	message string
+	fields  []FieldError
+}
+
+type validationErrorsKey struct{}
+
+// NewValidationErrors returns an empty collector.
+func NewValidationErrors() *ValidationErrors {
+	return &ValidationErrors{}
+}
+
+// WithValidationErrors stores v in ctx.
+func WithValidationErrors(ctx context.Context, v *ValidationErrors) context.Context {
+	return context.WithValue(ctx, validationErrorsKey{}, v)
+}
+
+// ValidationErrorsFromContext returns the collector of the request served by
+// ctx, or nil outside an engine endpoint.
+func ValidationErrorsFromContext(ctx context.Context) *ValidationErrors {
+	if ctx == nil {
+		return nil
+	}
+	v, _ := ctx.Value(validationErrorsKey{}).(*ValidationErrors)
+	return v
+}
+
+// ReportFieldError adds a field failure to the collector in ctx without
+// interrupting the caller, e.g. from an input hook that checks several
+// fields. It reports whether a collector was present.
+func ReportFieldError(ctx context.Context, field string, source FieldSource, message string) bool {
+	v := ValidationErrorsFromContext(ctx)
+	if v == nil {
+		return false
+	}
+	v.Add(NewFieldError(field, source, message))
+	return true
+}
+
+// Add appends field failures.
+func (v *ValidationErrors) Add(fields ...FieldError) {
+	if v == nil || len(fields) == 0 {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.fields = append(v.fields, fields...)
+}
+
+// AddError absorbs the fields of a validation failure and reports whether err
+// was one. Only *BindError values carrying fields and no cause qualify;
+// malformed bodies, oversized requests, and other errors are left to the
+// caller.
+func (v *ValidationErrors) AddError(err error) bool {
+	var bindErr *BindError
+	if v == nil || !errors.As(err, &bindErr) || bindErr.cause != nil || len(bindErr.fields) == 0 {
+		return false
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.message == "" {
+		v.message = bindErr.message
+	}
+	v.fields = append(v.fields, bindErr.fields...)
+	return true
+}
+
+// Len returns the number of collected failures.
+func (v *ValidationErrors) Len() int {
+	if v == nil {
+		return 0
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.fields)
+}
+
+// Fields returns a copy of the collected failures in report order.
+func (v *ValidationErrors) Fields() []FieldError {
+	if v == nil {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]FieldError(nil), v.fields...)
+}
+
+// Err returns the collected failures as a *BindError, or nil when none were
+// reported.
+func (v *ValidationErrors) Err() error {
+	if v == nil {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.fields) == 0 {
+		return nil
+	}
+	message := v.message
+	if message == "" {
+		message = "Request failed validation"
+	}
+	return NewBindError(message, v.fields)
+}
+
+// mergeFieldErrors appends the fields of extra whose names are not in dst.
+func mergeFieldErrors(dst []FieldError, extra []FieldError) []FieldError {
+	seen := make(map[string]bool, len(dst))
+	for _, fe := range dst {
+		seen[fe.Field] = true
+	}
+	for _, fe := range extra {
+		if !seen[fe.Field] {
+			dst = append(dst, fe)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,273 @@
+package clientkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
+	"github.com/aatuh/pureapi-framework/obs/accesslog"
+)
+
+// DefaultTraceHeaders are copied from the inbound request to outbound calls.
+var DefaultTraceHeaders = []string{"traceparent", "tracestate", "baggage"}
+
+// Call describes one outbound call, after all its attempts.
+type Call struct {
+	Method    string
+	Host      string
+	Path      string
+	Status    int
+	Duration  time.Duration
+	Attempts  int
+	RequestID string
+	Err       error
+}
+
+// Config controls a Client.
+type Config struct {
+	// Transport performs the attempts. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Timeout bounds each attempt, including reading the response body.
+	// Zero leaves attempts bounded only by the caller's context.
+	Timeout time.Duration
+	// MaxAttempts bounds attempts for idempotent requests. Defaults to 3;
+	// 1 disables retries.
+	MaxAttempts int
+	// Backoff returns the delay before retry attempt n (1-based). Defaults
+	// to exponential backoff starting at 100ms. A Retry-After header on the
+	// failed response takes precedence when it is shorter than MaxBackoff.
+	Backoff func(attempt int) time.Duration
+	// MaxBackoff caps the delay between attempts. Defaults to five seconds.
+	MaxBackoff time.Duration
+	// Retryable decides whether an attempt is retried. Defaults to
+	// transport errors and 429, 502, 503, and 504 responses.
+	Retryable func(resp *http.Response, err error) bool
+	// RequestIDHeader carries the current request ID. Defaults to
+	// requestid.DefaultHeader.
+	RequestIDHeader string
+	// TraceHeaders are copied from the inbound request in the call's
+	// context. Defaults to DefaultTraceHeaders.
+	TraceHeaders []string
+	// AccessLoggers receive an entry per outbound call, with the fields
+	// "outbound", "host", and "attempts".
+	AccessLoggers []accesslog.AccessLogger
+	// OnCall is called after every outbound call, e.g. to record metrics.
+	OnCall func(ctx context.Context, call Call)
+}
+
+// New returns an http.Client whose transport propagates request IDs and
+// trace headers, retries idempotent requests, and records every call.
+func New(cfg Config) *http.Client {
+	return &http.Client{Transport: NewTransport(cfg)}
+}
+
+// Transport is the http.RoundTripper behind New.
+type Transport struct {
+	cfg Config
+}
+
+// NewTransport applies cfg's defaults and returns a Transport.
+func NewTransport(cfg Config) *Transport {
+	if cfg.Transport == nil {
+		cfg.Transport = http.DefaultTransport
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.Backoff == nil {
+		cfg.Backoff = func(attempt int) time.Duration {
+			return 100 * time.Millisecond << uint(attempt-1)
+		}
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Second
+	}
+	if cfg.Retryable == nil {
+		cfg.Retryable = DefaultRetryable
+	}
+	if cfg.RequestIDHeader == "" {
+		cfg.RequestIDHeader = requestid.DefaultHeader
+	}
+	if cfg.TraceHeaders == nil {
+		cfg.TraceHeaders = DefaultTraceHeaders
+	}
+	return &Transport{cfg: cfg}
+}
+
+// DefaultRetryable retries transport errors and 429, 502, 503, and 504
+// responses.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// Idempotent reports whether req may be retried: its method is idempotent
+// or it carries an Idempotency-Key, and its body can be replayed.
+func Idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	default:
+		if req.Header.Get("Idempotency-Key") == "" {
+			return false
+		}
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	req = t.propagate(req)
+	start := time.Now()
+
+	attempts := 1
+	if Idempotent(req) {
+		attempts = t.cfg.MaxAttempts
+	}
+	var resp *http.Response
+	var err error
+	n := 1
+	for ; ; n++ {
+		attempt := req
+		if n > 1 && req.GetBody != nil {
+			attempt = req.Clone(ctx)
+			if attempt.Body, err = req.GetBody(); err != nil {
+				break
+			}
+		}
+		resp, err = t.attempt(attempt)
+		if n >= attempts || !t.cfg.Retryable(resp, err) {
+			break
+		}
+		delay := t.delay(n, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			resp = nil
+		}
+		if err = sleep(ctx, delay); err != nil {
+			break
+		}
+	}
+	t.record(ctx, req, resp, err, n, time.Since(start))
+	return resp, err
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// propagate copies the request ID and trace headers onto a clone of req,
+// leaving headers the caller set untouched.
+func (t *Transport) propagate(req *http.Request) *http.Request {
+	ctx := req.Context()
+	inbound := frameworkcontext.RequestFromContext(ctx)
+	id := requestid.FromContext(ctx)
+	if id == "" && inbound == nil {
+		return req
+	}
+	out := req.Clone(ctx)
+	if id != "" && out.Header.Get(t.cfg.RequestIDHeader) == "" {
+		out.Header.Set(t.cfg.RequestIDHeader, id)
+	}
+	if inbound != nil {
+		for _, name := range t.cfg.TraceHeaders {
+			if v := inbound.Header.Get(name); v != "" && out.Header.Get(name) == "" {
+				out.Header.Set(name, v)
+			}
+		}
+	}
+	return out
+}
+
+// attempt runs one round trip under the per-attempt timeout. The timeout
+// stays armed until the response body is closed.
+func (t *Transport) attempt(req *http.Request) (*http.Response, error) {
+	if t.cfg.Timeout <= 0 {
+		return t.cfg.Transport.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.cfg.Timeout)
+	resp, err := t.cfg.Transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *Transport) delay(attempt int, resp *http.Response) time.Duration {
+	delay := t.cfg.Backoff(attempt)
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			delay = time.Duration(seconds) * time.Second
+		}
+	}
+	if delay > t.cfg.MaxBackoff {
+		delay = t.cfg.MaxBackoff
+	}
+	return delay
+}
+
+func (t *Transport) record(ctx context.Context, req *http.Request, resp *http.Response, err error, attempts int, duration time.Duration) {
+	call := Call{
+		Method:    req.Method,
+		Host:      req.URL.Host,
+		Path:      req.URL.Path,
+		Duration:  duration,
+		Attempts:  attempts,
+		RequestID: req.Header.Get(t.cfg.RequestIDHeader),
+		Err:       err,
+	}
+	if resp != nil {
+		call.Status = resp.StatusCode
+	}
+	if t.cfg.OnCall != nil {
+		t.cfg.OnCall(ctx, call)
+	}
+	if len(t.cfg.AccessLoggers) == 0 {
+		return
+	}
+	entry := accesslog.Entry{
+		Method:    call.Method,
+		Path:      call.Path,
+		Status:    call.Status,
+		Duration:  call.Duration,
+		RequestID: call.RequestID,
+		Err:       call.Err,
+		Fields:    map[string]any{"outbound": true, "host": call.Host, "attempts": call.Attempts},
+	}
+	for _, logger := range t.cfg.AccessLoggers {
+		if logger != nil {
+			logger.Log(ctx, entry)
+		}
+	}
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
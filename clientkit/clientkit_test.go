@@ -0,0 +1,83 @@
+package clientkit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/clientkit"
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
+)
+
+func TestClient_PropagatesAndRetriesIdempotentCalls(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Request-ID") != "req-1" || r.Header.Get("traceparent") != "00-abc-def-01" {
+			t.Errorf("missing propagated headers: %v", r.Header)
+		}
+		if hits.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var calls []clientkit.Call
+	client := clientkit.New(clientkit.Config{
+		Backoff: func(int) time.Duration { return time.Millisecond },
+		OnCall:  func(ctx context.Context, call clientkit.Call) { calls = append(calls, call) },
+	})
+
+	inbound := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	inbound.Header.Set("traceparent", "00-abc-def-01")
+	ctx := frameworkcontext.WithRequest(requestid.WithID(context.Background(), "req-1"), inbound)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stock", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || hits.Load() != 3 {
+		t.Fatalf("expected success on third attempt, got %d after %d", resp.StatusCode, hits.Load())
+	}
+	if len(calls) != 1 || calls[0].Attempts != 3 || calls[0].Status != http.StatusOK || calls[0].Path != "/stock" || calls[0].RequestID != "req-1" {
+		t.Fatalf("unexpected call records: %+v", calls)
+	}
+
+	// Non-idempotent requests are sent once.
+	hits.Store(0)
+	req, _ = http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/stock", strings.NewReader("{}"))
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || hits.Load() != 1 {
+		t.Fatalf("expected single POST attempt, got %d after %d", resp.StatusCode, hits.Load())
+	}
+}
+
+func TestClient_EnforcesPerCallTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+	}))
+	defer server.Close()
+
+	client := clientkit.New(clientkit.Config{Timeout: 20 * time.Millisecond, MaxAttempts: 1})
+	start := time.Now()
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("expected per-call timeout, got %v after %s", err, time.Since(start))
+	}
+}
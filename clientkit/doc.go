@@ -0,0 +1,2 @@
+// Package clientkit wraps http.Client for outbound calls made while serving requests.
+package clientkit
@@ -0,0 +1,2 @@
+// Package codegen generates API clients from the endpoints declared on an engine.
+package codegen
@@ -0,0 +1,570 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aatuh/pureapi-framework/engine"
+	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
+)
+
+// GoClientConfig controls Go client generation.
+type GoClientConfig struct {
+	// Package names the generated package. Defaults to "client".
+	Package string
+	// Endpoints are the endpoints to expose, typically Engine.Endpoints().
+	Endpoints []engine.EndpointDescription
+	// Errors become typed sentinel errors, typically
+	// Engine.ErrorCatalog().Entries().
+	Errors []frameworkerrors.CatalogEntry
+}
+
+// GoClientFromEngine generates a Go client for every endpoint declared on e.
+func GoClientFromEngine(e *engine.Engine, pkg string) ([]byte, error) {
+	cfg := GoClientConfig{Package: pkg, Endpoints: e.Endpoints()}
+	if catalog := e.ErrorCatalog(); catalog != nil {
+		cfg.Errors = catalog.Entries()
+	}
+	return GoClient(cfg)
+}
+
+// GoClient renders a typed Go client with one method per endpoint. Request
+// and response types are referenced from their declaring packages, so they
+// must not live in package main. Server-sent event endpoints are skipped.
+func GoClient(cfg GoClientConfig) ([]byte, error) {
+	pkg := cfg.Package
+	if pkg == "" {
+		pkg = "client"
+	}
+	imports := newImportSet()
+	var methods bytes.Buffer
+	names := uniqueNames(cfg.Endpoints)
+	for i, desc := range cfg.Endpoints {
+		if desc.Output != nil && desc.Output.Implements(streamerType) {
+			continue
+		}
+		in, err := imports.typeExpr(desc.Input)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s input: %w", desc.Method, desc.Path, err)
+		}
+		out := "json.RawMessage"
+		if desc.Output != nil && desc.Output.Implements(arrayStreamerType) {
+			out = "[]json.RawMessage"
+		} else if out, err = imports.typeExpr(desc.Output); err != nil {
+			return nil, fmt.Errorf("%s %s output: %w", desc.Method, desc.Path, err)
+		}
+		doc := desc.Meta.Summary
+		if doc == "" {
+			doc = "calls " + desc.Method + " " + desc.Path
+		}
+		fmt.Fprintf(&methods, "\n// %s %s.\n", names[i], strings.TrimSuffix(lowerFirst(doc), "."))
+		fmt.Fprintf(&methods, "func (c *Client) %s(ctx context.Context, in %s) (%s, error) {\n", names[i], in, out)
+		fmt.Fprintf(&methods, "\tvar out %s\n", out)
+		fmt.Fprintf(&methods, "\terr := c.do(ctx, %q, %q, in, &out, %t)\n", desc.Method, desc.Path, desc.Envelope)
+		methods.WriteString("\treturn out, err\n}\n")
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by pureapi-framework codegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", pkg)
+	for _, imp := range goClientStdImports {
+		fmt.Fprintf(&src, "\t%q\n", imp)
+	}
+	if len(imports.paths) > 0 {
+		src.WriteString("\n")
+		for _, p := range imports.sorted() {
+			fmt.Fprintf(&src, "\t%s %q\n", imports.paths[p], p)
+		}
+	}
+	src.WriteString(")\n")
+	src.WriteString(goClientRuntime)
+	if len(cfg.Errors) > 0 {
+		src.WriteString("\n// Catalog errors returned by the API; match them with errors.Is.\nvar (\n")
+		for _, entry := range cfg.Errors {
+			fmt.Fprintf(&src, "\tErr%s = &APIError{Status: %d, ID: %q, Message: %q}\n",
+				exportedName(entry.ID), entry.Status, entry.ID, entry.Message)
+		}
+		src.WriteString(")\n")
+	}
+	src.Write(methods.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated client: %w", err)
+	}
+	return formatted, nil
+}
+
+// WriteFile writes generated source to name, creating parent directories.
+func WriteFile(name string, src []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, src, 0o644)
+}
+
+var (
+	streamerType      = reflect.TypeFor[engine.Streamer]()
+	arrayStreamerType = reflect.TypeFor[engine.ArrayStreamer]()
+)
+
+// importSet assigns package aliases to the types referenced by the client.
+type importSet struct {
+	paths   map[string]string
+	aliases map[string]bool
+}
+
+func newImportSet() *importSet {
+	aliases := make(map[string]bool)
+	for _, imp := range goClientStdImports {
+		aliases[path.Base(imp)] = true
+	}
+	return &importSet{paths: make(map[string]string), aliases: aliases}
+}
+
+func (s *importSet) alias(pkgPath string) string {
+	if alias, ok := s.paths[pkgPath]; ok {
+		return alias
+	}
+	base := path.Base(pkgPath)
+	base = strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, base)
+	alias := base
+	for n := 2; s.aliases[alias]; n++ {
+		alias = base + strconv.Itoa(n)
+	}
+	s.aliases[alias] = true
+	s.paths[pkgPath] = alias
+	return alias
+}
+
+func (s *importSet) sorted() []string {
+	out := make([]string, 0, len(s.paths))
+	for p := range s.paths {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// typeExpr renders t as a Go type expression, importing named types from
+// their packages. Generic instantiations and function or channel types
+// degrade to json.RawMessage.
+func (s *importSet) typeExpr(t reflect.Type) (string, error) {
+	if t == nil {
+		return "any", nil
+	}
+	if t.Name() != "" {
+		if strings.Contains(t.Name(), "[") {
+			return "json.RawMessage", nil
+		}
+		if t.PkgPath() == "" {
+			return t.Name(), nil
+		}
+		if !token.IsExported(t.Name()) {
+			return "", fmt.Errorf("type %s is unexported", t.Name())
+		}
+		if t.PkgPath() == "main" {
+			return "", fmt.Errorf("type %s is declared in package main and cannot be imported", t.Name())
+		}
+		return s.alias(t.PkgPath()) + "." + t.Name(), nil
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := s.typeExpr(t.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		elem, err := s.typeExpr(t.Elem())
+		return "[]" + elem, err
+	case reflect.Array:
+		elem, err := s.typeExpr(t.Elem())
+		return "[" + strconv.Itoa(t.Len()) + "]" + elem, err
+	case reflect.Map:
+		key, err := s.typeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := s.typeExpr(t.Elem())
+		return "map[" + key + "]" + elem, err
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any", nil
+		}
+		return "json.RawMessage", nil
+	case reflect.Struct:
+		var b strings.Builder
+		b.WriteString("struct {")
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			expr, err := s.typeExpr(field.Type)
+			if err != nil {
+				return "", err
+			}
+			if field.Anonymous {
+				b.WriteString(" " + expr)
+			} else {
+				b.WriteString(" " + field.Name + " " + expr)
+			}
+			if field.Tag != "" {
+				b.WriteString(" " + quoteTag(string(field.Tag)))
+			}
+			b.WriteString(";")
+		}
+		b.WriteString(" }")
+		return b.String(), nil
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return "json.RawMessage", nil
+	default:
+		return t.String(), nil
+	}
+}
+
+func quoteTag(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+var goClientStdImports = []string{
+	"bytes",
+	"context",
+	"encoding",
+	"encoding/json",
+	"fmt",
+	"io",
+	"net/http",
+	"net/url",
+	"reflect",
+	"sort",
+	"strings",
+}
+
+// goClientRuntime is emitted verbatim into every generated client. It binds
+// request structs using the same path/query/header/cookie/body tags as the
+// server-side binder.
+const goClientRuntime = `
+// Client calls the API over HTTP.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// Header is added to every request.
+	Header http.Header
+}
+
+// New creates a Client for baseURL.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), HTTPClient: http.DefaultClient}
+}
+
+// APIError is an error response decoded from the API error catalog.
+type APIError struct {
+	Status  int             ` + "`json:\"-\"`" + `
+	ID      string          ` + "`json:\"id\"`" + `
+	Message string          ` + "`json:\"message,omitempty\"`" + `
+	Data    json.RawMessage ` + "`json:\"data,omitempty\"`" + `
+	Origin  string          ` + "`json:\"origin,omitempty\"`" + `
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return e.ID
+	}
+	return e.ID + ": " + e.Message
+}
+
+// Is matches catalog errors by ID.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.ID == e.ID
+}
+
+func (c *Client) do(ctx context.Context, method, pattern string, in, out any, envelope bool) error {
+	req, err := c.newRequest(ctx, method, pattern, in)
+	if err != nil {
+		return err
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{Status: resp.StatusCode}
+		if json.Unmarshal(body, apiErr) != nil || apiErr.ID == "" {
+			apiErr.ID = strings.ToLower(strings.ReplaceAll(http.StatusText(resp.StatusCode), " ", "_"))
+			apiErr.Message = strings.TrimSpace(string(body))
+		}
+		return apiErr
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if envelope {
+		var wrapped struct {
+			Data json.RawMessage ` + "`json:\"data\"`" + `
+		}
+		if err := json.Unmarshal(body, &wrapped); err != nil {
+			return fmt.Errorf("decode response envelope: %w", err)
+		}
+		body = wrapped.Data
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, pattern string, in any) (*http.Request, error) {
+	b := &requestBuilder{path: pattern, query: url.Values{}, header: http.Header{}}
+	if err := b.bind(reflect.ValueOf(in)); err != nil {
+		return nil, err
+	}
+	target := c.BaseURL + b.path
+	if len(b.query) > 0 {
+		target += "?" + b.query.Encode()
+	}
+	var body io.Reader
+	if b.body != nil {
+		body = bytes.NewReader(b.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, values := range c.Header {
+		req.Header[k] = append([]string(nil), values...)
+	}
+	for k, values := range b.header {
+		req.Header[k] = values
+	}
+	for _, cookie := range b.cookies {
+		req.AddCookie(cookie)
+	}
+	if b.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+type requestBuilder struct {
+	path    string
+	query   url.Values
+	header  http.Header
+	cookies []*http.Cookie
+	body    []byte
+}
+
+func (b *requestBuilder) bind(v reflect.Value) error {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if key, ok := field.Tag.Lookup("path"); ok {
+			b.path = strings.ReplaceAll(b.path, "{"+firstNonEmpty(key, field.Name)+"}", url.PathEscape(formatValue(value)))
+			continue
+		}
+		if tag, ok := field.Tag.Lookup("query"); ok {
+			key, opts, _ := strings.Cut(tag, ",")
+			if strings.Contains(opts, "all") {
+				eachEntry(value, func(k string, v reflect.Value) { b.addQuery(k, v, true) })
+				continue
+			}
+			b.addQuery(firstNonEmpty(key, field.Name), value, !strings.Contains(opts, "explode=false"))
+			continue
+		}
+		if tag, ok := field.Tag.Lookup("header"); ok {
+			key, opts, _ := strings.Cut(tag, ",")
+			if strings.Contains(opts, "all") {
+				eachEntry(value, func(k string, v reflect.Value) {
+					for _, s := range formatValues(v) {
+						b.header.Add(k, s)
+					}
+				})
+				continue
+			}
+			for _, s := range formatValues(value) {
+				b.header.Add(firstNonEmpty(key, field.Name), s)
+			}
+			continue
+		}
+		if key, ok := field.Tag.Lookup("cookie"); ok {
+			if isSet(value) {
+				b.cookies = append(b.cookies, &http.Cookie{Name: firstNonEmpty(key, field.Name), Value: formatValue(value)})
+			}
+			continue
+		}
+		if _, ok := field.Tag.Lookup("body"); ok {
+			if value.Kind() == reflect.Pointer && value.IsNil() {
+				continue
+			}
+			data, err := json.Marshal(value.Interface())
+			if err != nil {
+				return fmt.Errorf("encode request body: %w", err)
+			}
+			b.body = data
+			continue
+		}
+		if field.Anonymous && value.Kind() == reflect.Pointer || value.Kind() == reflect.Struct && (field.Anonymous || field.Tag == "") {
+			if err := b.bind(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addQuery writes lists as repeated (or, without explode, comma-joined)
+// parameters and nested structs and maps in bracket syntax.
+func (b *requestBuilder) addQuery(key string, v reflect.Value, explode bool) {
+	orig := v
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	_, leaf := v.Interface().(encoding.TextMarshaler)
+	switch {
+	case v.Kind() == reflect.Map:
+		eachEntry(v, func(k string, elem reflect.Value) { b.addQuery(key+"["+k+"]", elem, true) })
+	case v.Kind() == reflect.Struct && !leaf:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Anonymous {
+				b.addQuery(key, v.Field(i), true)
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("query"), ",")
+			if name == "" {
+				name, _, _ = strings.Cut(field.Tag.Get("json"), ",")
+			}
+			if name == "-" {
+				continue
+			}
+			b.addQuery(key+"["+firstNonEmpty(name, field.Name)+"]", v.Field(i), true)
+		}
+	default:
+		values := formatValues(orig)
+		if !explode && len(values) > 0 {
+			values = []string{strings.Join(values, ",")}
+		}
+		for _, s := range values {
+			b.query.Add(key, s)
+		}
+	}
+}
+
+// eachEntry visits the entries of a string-keyed map in key order.
+func eachEntry(v reflect.Value, fn func(key string, value reflect.Value)) {
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return
+	}
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	for _, k := range keys {
+		fn(k.String(), v.MapIndex(k))
+	}
+}
+
+// isSet reports whether v is sent: non-nil pointers always are, so explicit
+// zero values such as false or 0 override server defaults; other values only
+// when they are not zero.
+func isSet(v reflect.Value) bool {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		return !v.IsNil()
+	}
+	return v.IsValid() && !v.IsZero()
+}
+
+func formatValues(v reflect.Value) []string {
+	if !isSet(v) {
+		return nil
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		out := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out = append(out, formatValue(v.Index(i)))
+		}
+		return out
+	}
+	return []string{formatValue(v)}
+}
+
+func formatValue(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+`
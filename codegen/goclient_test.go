@@ -0,0 +1,59 @@
+package codegen_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/codegen"
+	"github.com/aatuh/pureapi-framework/codegen/internal/testapi"
+	"github.com/aatuh/pureapi-framework/engine"
+)
+
+func newTestEngine() *engine.Engine {
+	e := engine.NewEngine(engine.WithEnvelope())
+	engine.Endpoint[testapi.GetUserInput, testapi.User](e, http.MethodGet, "/users/{id}",
+		func(context.Context, testapi.GetUserInput) (testapi.User, error) { return testapi.User{}, nil })
+	engine.Endpoint[struct{}, engine.StreamFunc](e, http.MethodGet, "/events",
+		func(context.Context, struct{}) (engine.StreamFunc, error) { return nil, nil })
+	engine.Endpoint[testapi.CreateUserInput, testapi.User](e, http.MethodPost, "/users",
+		func(context.Context, testapi.CreateUserInput) (testapi.User, error) { return testapi.User{}, nil },
+		engine.WithMeta[testapi.CreateUserInput, testapi.User](engine.EndpointMeta{OperationID: "create_user", Summary: "Creates a user."}),
+	)
+	return e
+}
+
+func TestGoClientGeneratesTypedMethods(t *testing.T) {
+	src, err := codegen.GoClientFromEngine(newTestEngine(), "apiclient")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	code := string(src)
+	for _, want := range []string{
+		"package apiclient",
+		`testapi "github.com/aatuh/pureapi-framework/codegen/internal/testapi"`,
+		"func (c *Client) GetUsersByID(ctx context.Context, in testapi.GetUserInput) (testapi.User, error)",
+		`c.do(ctx, "GET", "/users/{id}", in, &out, true)`,
+		"// CreateUser creates a user.",
+		`ID: "not_found"`,
+		"if !isSet(v) {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("generated client missing %q:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "/events") {
+		t.Fatalf("expected SSE endpoint to be skipped")
+	}
+}
+
+func TestGoClientRejectsUnexportedTypes(t *testing.T) {
+	type hidden struct{}
+	e := engine.NewEngine()
+	engine.Endpoint[hidden, hidden](e, http.MethodGet, "/hidden",
+		func(context.Context, hidden) (hidden, error) { return hidden{}, nil })
+	if _, err := codegen.GoClientFromEngine(e, ""); err == nil {
+		t.Fatalf("expected error for unexported type")
+	}
+}
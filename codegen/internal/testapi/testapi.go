@@ -0,0 +1,23 @@
+// Package testapi declares request and response types used by codegen tests.
+package testapi
+
+import "time"
+
+// GetUserInput binds a user lookup.
+type GetUserInput struct {
+	ID      string    `path:"id"`
+	Expand  []string  `query:"expand"`
+	TraceID string    `header:"X-Trace-Id"`
+	Since   time.Time `query:"since"`
+}
+
+// CreateUserInput binds a user creation request.
+type CreateUserInput struct {
+	Body User `body:"-"`
+}
+
+// User is the user resource.
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
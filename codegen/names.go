@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aatuh/pureapi-framework/engine"
+)
+
+var initialisms = map[string]string{
+	"api": "API", "http": "HTTP", "id": "ID", "ids": "IDs", "json": "JSON",
+	"sql": "SQL", "uri": "URI", "url": "URL", "uuid": "UUID",
+}
+
+// exportedName converts s into an exported Go identifier.
+func exportedName(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		if up, ok := initialisms[strings.ToLower(word)]; ok {
+			b.WriteString(up)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// splitWords breaks s on non-alphanumeric runes and lower-to-upper case
+// transitions.
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 && i > 0 && unicode.IsLower(current[len(current)-1]) {
+			flush()
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+// operationName derives a method name from the operation ID or, failing
+// that, from the HTTP method and path ("GET /users/{id}" -> GetUsersByID).
+func operationName(desc engine.EndpointDescription) string {
+	if desc.Meta.OperationID != "" {
+		return exportedName(desc.Meta.OperationID)
+	}
+	var b strings.Builder
+	b.WriteString(exportedName(strings.ToLower(desc.Method)))
+	for _, segment := range strings.Split(desc.Path, "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			b.WriteString("By")
+			segment = strings.Trim(segment, "{}")
+		}
+		b.WriteString(exportedName(segment))
+	}
+	return b.String()
+}
+
+// uniqueNames assigns each endpoint a distinct operation name.
+func uniqueNames(endpoints []engine.EndpointDescription) []string {
+	seen := make(map[string]int, len(endpoints))
+	names := make([]string, len(endpoints))
+	for i, desc := range endpoints {
+		name := operationName(desc)
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			name += strconv.Itoa(n)
+		}
+		names[i] = name
+	}
+	return names
+}
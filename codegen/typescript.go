@@ -0,0 +1,415 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/engine"
+	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
+)
+
+// TypeScriptConfig controls TypeScript client generation.
+type TypeScriptConfig struct {
+	// Endpoints are the endpoints to expose, typically Engine.Endpoints().
+	Endpoints []engine.EndpointDescription
+	// Errors populate the APIErrorID union, typically
+	// Engine.ErrorCatalog().Entries().
+	Errors []frameworkerrors.CatalogEntry
+}
+
+// TypeScriptFromEngine generates a TypeScript client for every endpoint
+// declared on e.
+func TypeScriptFromEngine(e *engine.Engine) ([]byte, error) {
+	cfg := TypeScriptConfig{Endpoints: e.Endpoints()}
+	if catalog := e.ErrorCatalog(); catalog != nil {
+		cfg.Errors = catalog.Entries()
+	}
+	return TypeScript(cfg)
+}
+
+// TypeScript renders interfaces for endpoint input and output types and a
+// fetch-based Client with one method per endpoint. Properties follow the
+// encoding/json names of the Go fields. Server-sent event endpoints are
+// skipped.
+func TypeScript(cfg TypeScriptConfig) ([]byte, error) {
+	ts := &tsTypes{names: make(map[reflect.Type]string), taken: make(map[string]bool)}
+	var methods bytes.Buffer
+	names := uniqueNames(cfg.Endpoints)
+	for i, desc := range cfg.Endpoints {
+		if desc.Output != nil && desc.Output.Implements(streamerType) {
+			continue
+		}
+		in := ts.typeExpr(desc.Input)
+		out := "unknown[]"
+		if desc.Output == nil || !desc.Output.Implements(arrayStreamerType) {
+			out = ts.typeExpr(desc.Output)
+		}
+		if desc.SuccessStatus == 204 {
+			out = "void"
+		}
+		specs, err := json.Marshal(paramSpecs(desc.Input))
+		if err != nil {
+			return nil, err
+		}
+		doc := desc.Meta.Summary
+		if doc == "" {
+			doc = desc.Method + " " + desc.Path
+		}
+		fmt.Fprintf(&methods, "\n  /** %s */\n", strings.ReplaceAll(doc, "*/", "* /"))
+		fmt.Fprintf(&methods, "  %s(input: %s, init?: RequestInit): Promise<%s> {\n", lowerFirst(names[i]), in, out)
+		fmt.Fprintf(&methods, "    return this.request<%s>(%q, %q, input, %s, %t, init);\n  }\n",
+			out, desc.Method, desc.Path, specs, desc.Envelope)
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by pureapi-framework codegen. DO NOT EDIT.\n\n")
+	for _, decl := range ts.decls {
+		src.WriteString(decl)
+		src.WriteString("\n")
+	}
+	src.WriteString("export type APIErrorID =")
+	if len(cfg.Errors) == 0 {
+		src.WriteString(" string;\n")
+	} else {
+		for _, entry := range cfg.Errors {
+			fmt.Fprintf(&src, "\n  | %q", entry.ID)
+		}
+		src.WriteString(";\n")
+	}
+	src.WriteString(tsRuntime)
+	src.WriteString("\nexport class Client {\n  constructor(private readonly options: ClientOptions) {}\n")
+	src.Write(methods.Bytes())
+	src.WriteString(tsRequest)
+	src.WriteString("}\n")
+	return src.Bytes(), nil
+}
+
+// tsParam tells the runtime where an input property is sent.
+type tsParam struct {
+	Prop    string `json:"prop"`
+	In      string `json:"in"`
+	Key     string `json:"key"`
+	Explode *bool  `json:"explode,omitempty"`
+	All     bool   `json:"all,omitempty"`
+}
+
+func paramSpecs(t reflect.Type) []tsParam {
+	specs := []tsParam{}
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return specs
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		prop, _, skip := jsonName(field)
+		bound := false
+		for _, in := range []string{"path", "query", "header", "cookie", "body"} {
+			if tag, ok := field.Tag.Lookup(in); ok {
+				if !skip {
+					key, opts, _ := strings.Cut(tag, ",")
+					spec := tsParam{Prop: prop, In: in, Key: firstNonEmpty(key, field.Name)}
+					if in == "query" && strings.Contains(opts, "explode=false") {
+						explode := false
+						spec.Explode = &explode
+					}
+					spec.All = (in == "query" || in == "header") && strings.Contains(opts, "all")
+					specs = append(specs, spec)
+				}
+				bound = true
+				break
+			}
+		}
+		if !bound && field.Type.Kind() == reflect.Struct && (field.Anonymous || field.Tag == "") {
+			specs = append(specs, paramSpecs(field.Type)...)
+		}
+	}
+	return specs
+}
+
+type tsTypes struct {
+	names map[reflect.Type]string
+	taken map[string]bool
+	decls []string
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// typeExpr maps t to a TypeScript type, declaring interfaces for named
+// structs on first use.
+func (ts *tsTypes) typeExpr(t reflect.Type) string {
+	if t == nil {
+		return "unknown"
+	}
+	switch {
+	case t == timeType:
+		return "string"
+	case t == rawMessageType:
+		return "unknown"
+	case t.Implements(jsonMarshalerType):
+		return "unknown"
+	case t.Implements(textMarshalerType):
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return ts.typeExpr(t.Elem()) + " | null"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		return "Array<" + ts.typeExpr(t.Elem()) + ">"
+	case reflect.Map:
+		return "Record<string, " + ts.typeExpr(t.Elem()) + ">"
+	case reflect.Struct:
+		if t.Name() == "" {
+			return ts.structBody(t, "")
+		}
+		if name, ok := ts.names[t]; ok {
+			return name
+		}
+		name := ts.declName(t)
+		ts.names[t] = name
+		index := len(ts.decls)
+		ts.decls = append(ts.decls, "")
+		ts.decls[index] = "export interface " + name + " " + ts.structBody(t, "") + "\n"
+		return name
+	default:
+		return "unknown"
+	}
+}
+
+func (ts *tsTypes) structBody(t reflect.Type, indent string) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	ts.writeFields(&b, t, indent+"  ")
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func (ts *tsTypes) writeFields(b *strings.Builder, t reflect.Type, indent string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitempty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			ts.writeFields(b, field.Type, indent)
+			continue
+		}
+		optional := ""
+		if omitempty || field.Type.Kind() == reflect.Pointer {
+			optional = "?"
+		}
+		fmt.Fprintf(b, "%s%s%s: %s;\n", indent, tsPropName(name), optional, ts.typeExpr(field.Type))
+	}
+}
+
+// declName picks a unique interface name, flattening generic instantiations
+// such as Response[pkg.User] into ResponseUser.
+func (ts *tsTypes) declName(t reflect.Type) string {
+	name := t.Name()
+	if base, args, ok := strings.Cut(name, "["); ok {
+		var b strings.Builder
+		b.WriteString(base)
+		for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+			arg = arg[strings.LastIndex(arg, ".")+1:]
+			b.WriteString(exportedName(arg))
+		}
+		name = b.String()
+	}
+	unique := name
+	for n := 2; ts.taken[unique]; n++ {
+		unique = name + strconv.Itoa(n)
+	}
+	ts.taken[unique] = true
+	return unique
+}
+
+// jsonName mirrors encoding/json field naming.
+func jsonName(field reflect.StructField) (name string, omitempty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
+
+func tsPropName(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9')) {
+			return strconv.Quote(name)
+		}
+	}
+	return name
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+const tsRuntime = `
+export interface APIErrorBody {
+  id: APIErrorID;
+  message?: string;
+  data?: unknown;
+  origin?: string;
+}
+
+export class APIError extends Error {
+  constructor(
+    readonly status: number,
+    readonly id: APIErrorID,
+    message: string,
+    readonly data?: unknown,
+    readonly origin?: string,
+  ) {
+    super(message || id);
+    this.name = "APIError";
+  }
+}
+
+export interface ClientOptions {
+  baseURL: string;
+  headers?: Record<string, string>;
+  fetch?: typeof fetch;
+}
+
+interface ParamSpec {
+  prop: string;
+  in: "path" | "query" | "header" | "cookie" | "body";
+  key: string;
+  explode?: boolean;
+  all?: boolean;
+}
+
+// appendQuery writes lists as repeated (or, without explode, comma-joined)
+// parameters and objects in bracket syntax: filter[age][gte]=3.
+function appendQuery(query: URLSearchParams, key: string, value: unknown, explode: boolean): void {
+  if (value === undefined || value === null) return;
+  if (Array.isArray(value)) {
+    if (!explode) {
+      query.append(key, value.map(String).join(","));
+      return;
+    }
+    for (const v of value) query.append(key, String(v));
+    return;
+  }
+  if (typeof value === "object") {
+    for (const [k, v] of Object.entries(value as Record<string, unknown>)) {
+      appendQuery(query, key + "[" + k + "]", v, true);
+    }
+    return;
+  }
+  query.append(key, String(value));
+}
+`
+
+const tsRequest = `
+  private async request<T>(
+    method: string,
+    path: string,
+    input: unknown,
+    params: ParamSpec[],
+    envelope: boolean,
+    init?: RequestInit,
+  ): Promise<T> {
+    const values = (input ?? {}) as Record<string, unknown>;
+    const query = new URLSearchParams();
+    const headers: Record<string, string> = { Accept: "application/json", ...this.options.headers };
+    const cookies: string[] = [];
+    let body: string | undefined;
+    for (const p of params) {
+      const value = values[p.prop];
+      if (value === undefined || value === null) continue;
+      const list = Array.isArray(value) ? value : [value];
+      switch (p.in) {
+        case "path":
+          path = path.replace("{" + p.key + "}", encodeURIComponent(String(value)));
+          break;
+        case "query":
+          if (p.all) {
+            for (const [k, v] of Object.entries(value as Record<string, unknown>)) appendQuery(query, k, v, true);
+          } else {
+            appendQuery(query, p.key, value, p.explode !== false);
+          }
+          break;
+        case "header":
+          if (p.all) {
+            for (const [k, v] of Object.entries(value as Record<string, unknown>)) {
+              headers[k] = (Array.isArray(v) ? v : [v]).map(String).join(", ");
+            }
+          } else {
+            headers[p.key] = list.map(String).join(", ");
+          }
+          break;
+        case "cookie":
+          cookies.push(p.key + "=" + encodeURIComponent(String(value)));
+          break;
+        case "body":
+          body = JSON.stringify(value);
+          headers["Content-Type"] = "application/json";
+          break;
+      }
+    }
+    if (cookies.length > 0) headers["Cookie"] = cookies.join("; ");
+    const qs = query.toString();
+    const url = this.options.baseURL.replace(/\/+$/, "") + path + (qs ? "?" + qs : "");
+    const doFetch = this.options.fetch ?? fetch;
+    const res = await doFetch(url, { ...init, method, headers: { ...headers, ...(init?.headers as Record<string, string>) }, body });
+    const text = await res.text();
+    if (!res.ok) {
+      let parsed: Partial<APIErrorBody> = {};
+      try {
+        parsed = JSON.parse(text);
+      } catch {
+        parsed = { message: text };
+      }
+      throw new APIError(res.status, (parsed.id ?? String(res.status)) as APIErrorID, parsed.message ?? "", parsed.data, parsed.origin);
+    }
+    if (!text) return undefined as T;
+    const decoded = JSON.parse(text);
+    return (envelope ? decoded.data : decoded) as T;
+  }
+`
@@ -0,0 +1,33 @@
+package codegen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/codegen"
+)
+
+func TestTypeScriptGeneratesInterfacesAndClient(t *testing.T) {
+	src, err := codegen.TypeScriptFromEngine(newTestEngine())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	code := string(src)
+	for _, want := range []string{
+		"export interface GetUserInput {\n  ID: string;\n  Expand: Array<string>;\n  TraceID: string;\n  Since: string;\n}",
+		"export interface User {\n  id: string;\n  name: string;\n}",
+		"export interface CreateUserInput {\n  Body: User;\n}",
+		`| "not_found"`,
+		"getUsersByID(input: GetUserInput, init?: RequestInit): Promise<User>",
+		`{"prop":"ID","in":"path","key":"id"}`,
+		`{"prop":"Body","in":"body","key":"-"}`,
+		"createUser(input: CreateUserInput, init?: RequestInit): Promise<User>",
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("generated client missing %q:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "/events") {
+		t.Fatalf("expected SSE endpoint to be skipped")
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/aatuh/pureapi-framework/binder"
+	"github.com/aatuh/pureapi-framework/engine"
+	"github.com/aatuh/pureapi-framework/middleware/compress"
+	"github.com/aatuh/pureapi-framework/middleware/forwarded"
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
+	"github.com/aatuh/pureapi-framework/obs/accesslog"
+	"github.com/aatuh/pureapi-framework/security/cors"
+	securityheaders "github.com/aatuh/pureapi-framework/security/headers"
+	"github.com/aatuh/pureapi-framework/serverutil"
+)
+
+// EngineOptions translates c into engine options: the binder, handler
+// timeout and envelope, CORS and security header middlewares, compression,
+// request IDs, and access logging.
+func (c Config) EngineOptions() []engine.EngineOption {
+	b := binder.NewDefaultBinder()
+	b.MaxBodyBytes = c.Binder.MaxBodyBytes
+	b.ReadTimeout = c.Binder.ReadTimeout.Std()
+	b.StrictJSONBodies = c.Binder.StrictJSON
+	b.Strict = binder.StrictParams{Query: c.Binder.StrictQuery, Headers: c.Binder.StrictHeaders}
+
+	opts := []engine.EngineOption{
+		engine.WithBinder(b),
+		engine.WithTimeout(c.Handler.Timeout.Std()),
+		engine.WithRequestID(requestid.Config{
+			Header:         c.RequestID.Header,
+			ResponseHeader: c.RequestID.ResponseHeader,
+			IgnoreIncoming: c.RequestID.IgnoreIncoming,
+		}),
+	}
+	if len(c.Server.TrustedProxies) > 0 {
+		opts = append(opts, engine.WithForwardedHeaders(forwarded.Config{TrustedProxies: c.Server.TrustedProxies}))
+	}
+	if c.Handler.Envelope {
+		opts = append(opts, engine.WithEnvelope())
+	}
+	if c.CORS.Enabled {
+		opts = append(opts, engine.WithGlobalMiddlewares(cors.Middleware(cors.Config{
+			AllowOrigins:     c.CORS.AllowOrigins,
+			AllowMethods:     c.CORS.AllowMethods,
+			AllowHeaders:     c.CORS.AllowHeaders,
+			ExposeHeaders:    c.CORS.ExposeHeaders,
+			AllowCredentials: c.CORS.AllowCredentials,
+			MaxAge:           c.CORS.MaxAge,
+		})))
+	}
+	if c.SecurityHeaders.Enabled {
+		opts = append(opts, engine.WithGlobalMiddlewares(securityheaders.Middleware(securityheaders.Config{
+			NoSniff:        c.SecurityHeaders.NoSniff,
+			FrameOptions:   c.SecurityHeaders.FrameOptions,
+			XSSProtection:  c.SecurityHeaders.XSSProtection,
+			ReferrerPolicy: c.SecurityHeaders.ReferrerPolicy,
+		})))
+	}
+	if c.Compression.Enabled {
+		opts = append(opts, engine.WithCompression(compress.Config{
+			Level:   c.Compression.Level,
+			MinSize: c.Compression.MinSize,
+		}))
+	}
+	if c.Logging.AccessLog {
+		opts = append(opts, engine.WithAccessLoggers(accesslog.NewStdLogger(log.New(os.Stderr, c.Logging.Prefix, log.LstdFlags))))
+	}
+	return opts
+}
+
+// NewEngine builds an engine from c. opts apply after the configured
+// options, so they can add endpoints' shared pieces or override settings.
+func (c Config) NewEngine(opts ...engine.EngineOption) *engine.Engine {
+	return engine.NewEngine(append(c.EngineOptions(), opts...)...)
+}
+
+// ServerConfig returns the serverutil.Run configuration serving handler.
+func (c Config) ServerConfig(handler http.Handler) serverutil.Config {
+	return serverutil.Config{
+		Addr:    c.Server.Addr,
+		Handler: handler,
+		Server: &http.Server{
+			ReadHeaderTimeout: c.Server.ReadHeaderTimeout.Std(),
+			IdleTimeout:       c.Server.IdleTimeout.Std(),
+		},
+		DrainTimeout: c.Server.DrainTimeout.Std(),
+	}
+}
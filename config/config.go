@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/middleware/forwarded"
+)
+
+// Config is the complete framework configuration. Field names follow the
+// json (and yaml/toml) tags; environment variables use the upper-cased path,
+// e.g. APP_BINDER_MAX_BODY_BYTES.
+type Config struct {
+	Server          Server          `json:"server" yaml:"server" toml:"server"`
+	Binder          Binder          `json:"binder" yaml:"binder" toml:"binder"`
+	Handler         Handler         `json:"handler" yaml:"handler" toml:"handler"`
+	CORS            CORS            `json:"cors" yaml:"cors" toml:"cors"`
+	SecurityHeaders SecurityHeaders `json:"security_headers" yaml:"security_headers" toml:"security_headers"`
+	Compression     Compression     `json:"compression" yaml:"compression" toml:"compression"`
+	RequestID       RequestID       `json:"request_id" yaml:"request_id" toml:"request_id"`
+	Logging         Logging         `json:"logging" yaml:"logging" toml:"logging"`
+}
+
+// Server configures the HTTP server run by serverutil.
+type Server struct {
+	Addr              string   `json:"addr" yaml:"addr" toml:"addr"`
+	ReadHeaderTimeout Duration `json:"read_header_timeout" yaml:"read_header_timeout" toml:"read_header_timeout"`
+	IdleTimeout       Duration `json:"idle_timeout" yaml:"idle_timeout" toml:"idle_timeout"`
+	DrainTimeout      Duration `json:"drain_timeout" yaml:"drain_timeout" toml:"drain_timeout"`
+	// TrustedProxies enables forwarded header handling for these addresses
+	// or CIDR ranges.
+	TrustedProxies []string `json:"trusted_proxies" yaml:"trusted_proxies" toml:"trusted_proxies"`
+}
+
+// Binder configures the default binder.
+type Binder struct {
+	MaxBodyBytes  int64    `json:"max_body_bytes" yaml:"max_body_bytes" toml:"max_body_bytes"`
+	ReadTimeout   Duration `json:"read_timeout" yaml:"read_timeout" toml:"read_timeout"`
+	StrictJSON    bool     `json:"strict_json" yaml:"strict_json" toml:"strict_json"`
+	StrictQuery   bool     `json:"strict_query" yaml:"strict_query" toml:"strict_query"`
+	StrictHeaders bool     `json:"strict_headers" yaml:"strict_headers" toml:"strict_headers"`
+}
+
+// Handler configures endpoint execution.
+type Handler struct {
+	Timeout  Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+	Envelope bool     `json:"envelope" yaml:"envelope" toml:"envelope"`
+}
+
+// CORS configures the CORS middleware.
+type CORS struct {
+	Enabled          bool     `json:"enabled" yaml:"enabled" toml:"enabled"`
+	AllowOrigins     []string `json:"allow_origins" yaml:"allow_origins" toml:"allow_origins"`
+	AllowMethods     []string `json:"allow_methods" yaml:"allow_methods" toml:"allow_methods"`
+	AllowHeaders     []string `json:"allow_headers" yaml:"allow_headers" toml:"allow_headers"`
+	ExposeHeaders    []string `json:"expose_headers" yaml:"expose_headers" toml:"expose_headers"`
+	AllowCredentials bool     `json:"allow_credentials" yaml:"allow_credentials" toml:"allow_credentials"`
+	MaxAge           int      `json:"max_age" yaml:"max_age" toml:"max_age"`
+}
+
+// SecurityHeaders configures the security headers middleware.
+type SecurityHeaders struct {
+	Enabled        bool   `json:"enabled" yaml:"enabled" toml:"enabled"`
+	NoSniff        bool   `json:"no_sniff" yaml:"no_sniff" toml:"no_sniff"`
+	FrameOptions   string `json:"frame_options" yaml:"frame_options" toml:"frame_options"`
+	XSSProtection  string `json:"xss_protection" yaml:"xss_protection" toml:"xss_protection"`
+	ReferrerPolicy string `json:"referrer_policy" yaml:"referrer_policy" toml:"referrer_policy"`
+}
+
+// Compression configures response compression.
+type Compression struct {
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	Level   int  `json:"level" yaml:"level" toml:"level"`
+	MinSize int  `json:"min_size" yaml:"min_size" toml:"min_size"`
+}
+
+// RequestID configures the request ID middleware.
+type RequestID struct {
+	Header         string `json:"header" yaml:"header" toml:"header"`
+	ResponseHeader string `json:"response_header" yaml:"response_header" toml:"response_header"`
+	IgnoreIncoming bool   `json:"ignore_incoming" yaml:"ignore_incoming" toml:"ignore_incoming"`
+}
+
+// Logging configures access logging.
+type Logging struct {
+	AccessLog bool   `json:"access_log" yaml:"access_log" toml:"access_log"`
+	Prefix    string `json:"prefix" yaml:"prefix" toml:"prefix"`
+}
+
+// Default returns the configuration used before any layer is applied. It
+// matches the engine and binder defaults.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Addr:              ":8080",
+			ReadHeaderTimeout: Duration(10 * time.Second),
+			IdleTimeout:       Duration(2 * time.Minute),
+			DrainTimeout:      Duration(15 * time.Second),
+		},
+		Binder: Binder{
+			MaxBodyBytes: 1 << 20,
+			ReadTimeout:  Duration(5 * time.Second),
+		},
+		CORS: CORS{
+			AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		},
+		SecurityHeaders: SecurityHeaders{
+			Enabled:        true,
+			NoSniff:        true,
+			FrameOptions:   "DENY",
+			XSSProtection:  "0",
+			ReferrerPolicy: "no-referrer",
+		},
+		Compression: Compression{MinSize: 1024},
+		RequestID:   RequestID{Header: "X-Request-ID"},
+		Logging:     Logging{AccessLog: true},
+	}
+}
+
+// Validate reports every invalid setting.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	check(c.Server.Addr != "", "server.addr must not be empty")
+	check(c.Server.ReadHeaderTimeout >= 0, "server.read_header_timeout must not be negative")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout must not be negative")
+	check(c.Server.DrainTimeout >= 0, "server.drain_timeout must not be negative")
+	if _, err := forwarded.NewResolver(forwarded.Config{TrustedProxies: c.Server.TrustedProxies}); err != nil {
+		errs = append(errs, fmt.Errorf("server.trusted_proxies: %w", err))
+	}
+	check(c.Binder.MaxBodyBytes > 0, "binder.max_body_bytes must be positive")
+	check(c.Binder.ReadTimeout >= 0, "binder.read_timeout must not be negative")
+	check(c.Handler.Timeout >= 0, "handler.timeout must not be negative")
+	if c.CORS.Enabled {
+		check(len(c.CORS.AllowOrigins) > 0, "cors.allow_origins must not be empty when cors is enabled")
+		for _, origin := range c.CORS.AllowOrigins {
+			check(!(origin == "*" && c.CORS.AllowCredentials), "cors.allow_origins must not contain * with allow_credentials")
+		}
+		check(c.CORS.MaxAge >= 0, "cors.max_age must not be negative")
+	}
+	if c.SecurityHeaders.Enabled && c.SecurityHeaders.FrameOptions != "" {
+		fo := strings.ToUpper(c.SecurityHeaders.FrameOptions)
+		check(fo == "DENY" || fo == "SAMEORIGIN", "security_headers.frame_options must be DENY or SAMEORIGIN")
+	}
+	check(c.Compression.Level >= -2 && c.Compression.Level <= 9, "compression.level must be between -2 and 9")
+	check(c.Compression.MinSize >= 0, "compression.min_size must not be negative")
+	return errors.Join(errs...)
+}
+
+// Duration is a time.Duration written as "5s" or "1m30s" in configuration
+// files and environment variables.
+type Duration time.Duration
+
+// Std returns d as a time.Duration.
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// String formats d like time.Duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", text)
+	}
+	*d = Duration(parsed)
+	return nil
+}
@@ -0,0 +1,113 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/aatuh/pureapi-core/server"
+	"github.com/aatuh/pureapi-framework/config"
+	"github.com/aatuh/pureapi-framework/engine"
+)
+
+func TestLoad_LayersFilesAndEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	if err := os.WriteFile(base, []byte(`{"server":{"addr":":9000"},"handler":{"timeout":"2s"},"cors":{"enabled":true,"allow_origins":["https://a.example"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// A custom format stands in for YAML or TOML decoders.
+	local := filepath.Join(dir, "local.conf")
+	if err := os.WriteFile(local, []byte(`{"binder":{"max_body_bytes":2048}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"APP_SERVER_ADDR":        ":9100",
+		"APP_CORS_ALLOW_ORIGINS": "https://a.example, https://b.example",
+		"APP_BINDER_STRICT_JSON": "true",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	cfg, err := config.Load(
+		config.WithFormat(json.Unmarshal, "conf"),
+		config.WithFile(base),
+		config.WithOptionalFile(filepath.Join(dir, "missing.json")),
+		config.WithFile(local),
+		config.WithLookup("APP", lookup),
+	)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Server.Addr != ":9100" || cfg.Handler.Timeout.Std() != 2*time.Second || cfg.Binder.MaxBodyBytes != 2048 || !cfg.Binder.StrictJSON {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if len(cfg.CORS.AllowOrigins) != 2 || cfg.CORS.AllowOrigins[1] != "https://b.example" {
+		t.Fatalf("unexpected origins: %v", cfg.CORS.AllowOrigins)
+	}
+	if cfg.Binder.ReadTimeout.Std() != 5*time.Second || !cfg.SecurityHeaders.Enabled {
+		t.Fatalf("expected defaults to survive layering: %+v", cfg)
+	}
+}
+
+func TestLoad_ValidatesSettings(t *testing.T) {
+	env := map[string]string{"APP_BINDER_MAX_BODY_BYTES": "0", "APP_CORS_ENABLED": "true"}
+	_, err := config.Load(config.WithLookup("APP", func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}))
+	if err == nil || !strings.Contains(err.Error(), "binder.max_body_bytes") || !strings.Contains(err.Error(), "cors.allow_origins") {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+
+	_, err = config.Load(config.WithLookup("APP", func(key string) (string, bool) {
+		return "soon", key == "APP_HANDLER_TIMEOUT"
+	}))
+	if err == nil || !strings.Contains(err.Error(), "APP_HANDLER_TIMEOUT") {
+		t.Fatalf("expected parse error naming the variable, got %v", err)
+	}
+}
+
+func TestConfig_NewEngineAppliesSettings(t *testing.T) {
+	cfg := config.Default()
+	cfg.Logging.AccessLog = false
+	cfg.CORS = config.CORS{Enabled: true, AllowOrigins: []string{"https://a.example"}}
+
+	type in struct{}
+	type out struct {
+		OK bool `json:"ok"`
+	}
+	eng := cfg.NewEngine()
+	ep := engine.Endpoint[in, out](eng, http.MethodGet, "/ok", func(ctx context.Context, _ in) (out, error) {
+		return out{OK: true}, nil
+	})
+	h := server.NewHandler(event.NewNoopEventEmitter())
+	h.Register(endpoint.ToEndpoints(ep))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "https://a.example" || rec.Header().Get("X-Frame-Options") != "DENY" {
+		t.Fatalf("expected CORS and security headers, got %v", rec.Header())
+	}
+	if rec.Header().Get("X-Request-ID") == "" {
+		t.Fatalf("expected request id header")
+	}
+
+	srv := cfg.ServerConfig(h)
+	if srv.Addr != ":8080" || srv.Server.ReadHeaderTimeout != 10*time.Second || srv.DrainTimeout != 15*time.Second {
+		t.Fatalf("unexpected server config: %+v", srv)
+	}
+}
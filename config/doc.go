@@ -0,0 +1,2 @@
+// Package config loads layered service configuration and builds a ready engine from it.
+package config
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalFunc decodes a configuration file into v. yaml.Unmarshal and
+// toml.Unmarshal from the common libraries satisfy it.
+type UnmarshalFunc func(data []byte, v any) error
+
+// Option adds a layer or a file format to Load.
+type Option func(*loader)
+
+type layer func(l *loader, cfg *Config) error
+
+type loader struct {
+	formats map[string]UnmarshalFunc
+	layers  []layer
+}
+
+// Load starts from Default, applies the layers in order (later layers win),
+// and validates the result.
+func Load(opts ...Option) (Config, error) {
+	l := &loader{formats: map[string]UnmarshalFunc{".json": json.Unmarshal}}
+	for _, opt := range opts {
+		opt(l)
+	}
+	cfg := Default()
+	for _, apply := range l.layers {
+		if err := apply(l, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("config: %w", err)
+	}
+	return cfg, nil
+}
+
+// WithFormat decodes files with the given extensions using fn, e.g.
+// WithFormat(yaml.Unmarshal, ".yaml", ".yml"). JSON is built in.
+func WithFormat(fn UnmarshalFunc, exts ...string) Option {
+	return func(l *loader) {
+		for _, ext := range exts {
+			if !strings.HasPrefix(ext, ".") {
+				ext = "." + ext
+			}
+			l.formats[strings.ToLower(ext)] = fn
+		}
+	}
+}
+
+// WithFile layers the file at path, decoded by its extension.
+func WithFile(path string) Option {
+	return withFile(path, false)
+}
+
+// WithOptionalFile is WithFile but skips files that do not exist, e.g. a
+// local override file.
+func WithOptionalFile(path string) Option {
+	return withFile(path, true)
+}
+
+func withFile(path string, optional bool) Option {
+	return func(l *loader) {
+		l.layers = append(l.layers, func(l *loader, cfg *Config) error {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				if optional && os.IsNotExist(err) {
+					return nil
+				}
+				return fmt.Errorf("config: %w", err)
+			}
+			ext := strings.ToLower(filepath.Ext(path))
+			unmarshal, ok := l.formats[ext]
+			if !ok {
+				return fmt.Errorf("config: no format registered for %s files", ext)
+			}
+			if err := unmarshal(data, cfg); err != nil {
+				return fmt.Errorf("config: %s: %w", path, err)
+			}
+			return nil
+		})
+	}
+}
+
+// WithEnv layers environment variables named prefix + "_" + the upper-cased
+// field path, e.g. APP_SERVER_ADDR or APP_CORS_ALLOW_ORIGINS (comma-separated).
+func WithEnv(prefix string) Option {
+	return WithLookup(prefix, os.LookupEnv)
+}
+
+// WithLookup is WithEnv reading variables through lookup.
+func WithLookup(prefix string, lookup func(string) (string, bool)) Option {
+	return func(l *loader) {
+		l.layers = append(l.layers, func(_ *loader, cfg *Config) error {
+			return applyEnv(reflect.ValueOf(cfg).Elem(), strings.TrimSuffix(prefix, "_"), lookup)
+		})
+	}
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+func applyEnv(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		key := strings.ToUpper(firstNonEmpty(name, field.Name))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		fv := v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(fv, key, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+		raw, ok := lookup(key)
+		if !ok {
+			continue
+		}
+		if err := setValue(fv, raw); err != nil {
+			return fmt.Errorf("config: %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("expected boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected integer")
+		}
+		v.SetInt(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
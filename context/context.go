@@ -1,10 +1,17 @@
 package context
 
-import "context"
+import (
+	"context"
+	"net/http"
+)
 
 type contextKey string
 
-const pathParamsContextKey contextKey = "pureapi-framework/path-params"
+const (
+	pathParamsContextKey contextKey = "pureapi-framework/path-params"
+	requestContextKey    contextKey = "pureapi-framework/request"
+	writerContextKey     contextKey = "pureapi-framework/response-writer"
+)
 
 // WithPathParams annotates context with path parameters for custom routers.
 func WithPathParams(ctx context.Context, params map[string]string) context.Context {
@@ -37,3 +44,40 @@ func PathParamsFromContext(ctx context.Context) map[string]string {
 	}
 	return nil
 }
+
+// WithRequest stores the inbound request so handlers can reach it from context.
+func WithRequest(ctx context.Context, r *http.Request) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if r == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, requestContextKey, r)
+}
+
+// RequestFromContext returns the request stored via WithRequest.
+func RequestFromContext(ctx context.Context) *http.Request {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(requestContextKey).(*http.Request)
+	return r
+}
+
+// WithResponseWriter stores the endpoint's response writer in ctx.
+func WithResponseWriter(ctx context.Context, w http.ResponseWriter) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, writerContextKey, w)
+}
+
+// ResponseWriterFromContext returns the writer stored via WithResponseWriter.
+func ResponseWriterFromContext(ctx context.Context) http.ResponseWriter {
+	if ctx == nil {
+		return nil
+	}
+	w, _ := ctx.Value(writerContextKey).(http.ResponseWriter)
+	return w
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"net/http"
+
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+// WithEndpointMaxBodyBytes caps this endpoint's request body at n bytes,
+// overriding the binder-wide MaxBodyBytes. Larger bodies render the
+// "payload_too_large" catalog entry (413).
+func WithEndpointMaxBodyBytes[TIn any, TOut any](n int64) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		if n > 0 {
+			ep.maxBodyBytes = n
+		}
+	}
+}
+
+// bodyLimit returns the effective body limit: the endpoint's own, else the
+// DefaultBinder's MaxBodyBytes, else 0 for none.
+func (d *DeclarativeEndpoint[TIn, TOut]) bodyLimit(b binder.Binder) int64 {
+	if d.maxBodyBytes > 0 {
+		return d.maxBodyBytes
+	}
+	if db, ok := b.(*binder.DefaultBinder); ok {
+		return db.MaxBodyBytes
+	}
+	return 0
+}
+
+// withMaxBodyBytes applies the endpoint limit to a DefaultBinder so binding
+// agrees with the reader installed by limitBody.
+func withMaxBodyBytes(b binder.Binder, n int64) binder.Binder {
+	if db, ok := b.(*binder.DefaultBinder); ok && n > 0 {
+		return db.WithMaxBodyBytes(n)
+	}
+	return b
+}
+
+// limitBody rejects requests that declare an oversized body and wraps the
+// rest in http.MaxBytesReader, so no reader consumes more than limit bytes.
+func limitBody(w http.ResponseWriter, r *http.Request, limit int64) error {
+	if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	if r.ContentLength > limit {
+		return binder.ErrBodyTooLarge
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	return nil
+}
@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
+)
+
+// ErrPreconditionFailed is reported when If-Match or If-None-Match rejects a
+// state-changing request.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// ETagger is implemented by outputs that know their entity tag, e.g. from a
+// version column. It takes precedence over hashed ETags.
+type ETagger interface {
+	ETag() string
+}
+
+// WithEndpointETag emits an ETag computed from the serialized output and
+// answers matching If-None-Match requests with 304 Not Modified.
+func WithEndpointETag[TIn any, TOut any]() EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		ep.etag = true
+	}
+}
+
+// ComputeETag returns a strong entity tag derived from the JSON form of v.
+func ComputeETag(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// CheckPreconditions evaluates If-Match and If-None-Match against the current
+// entity tag of the resource being modified. An empty currentETag means the
+// resource does not exist. Handlers call it before applying updates or deletes.
+func CheckPreconditions(ctx context.Context, currentETag string) error {
+	r := frameworkcontext.RequestFromContext(ctx)
+	if r == nil {
+		return nil
+	}
+	currentETag = quoteETag(currentETag)
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if currentETag == "" || !etagListMatches(ifMatch, currentETag, false) {
+			return ErrPreconditionFailed
+		}
+	}
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && !isSafeMethod(r.Method) {
+		if currentETag != "" && etagListMatches(ifNoneMatch, currentETag, true) {
+			return ErrPreconditionFailed
+		}
+	}
+	return nil
+}
+
+// outputETag resolves the entity tag of the representation sent, if any.
+// payload is output after transforms and field projection, so each field
+// selection gets its own tag.
+func outputETag(output, payload any, fields []string, compute bool) string {
+	if tagger, ok := output.(ETagger); ok {
+		tag := quoteETag(tagger.ETag())
+		if tag == "" || len(fields) == 0 {
+			return tag
+		}
+		sum := sha256.Sum256([]byte(strings.Join(fields, ",")))
+		return strings.TrimSuffix(tag, `"`) + "." + hex.EncodeToString(sum[:4]) + `"`
+	}
+	if compute {
+		return ComputeETag(payload)
+	}
+	return ""
+}
+
+// notModified reports whether a safe request already holds the current representation.
+func notModified(r *http.Request, etag string) bool {
+	if etag == "" || r == nil || !isSafeMethod(r.Method) {
+		return false
+	}
+	header := r.Header.Get("If-None-Match")
+	return header != "" && etagListMatches(header, etag, true)
+}
+
+// etagListMatches compares etag against a header list. Weak comparison ignores
+// the W/ prefix, as required for If-None-Match.
+func etagListMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+			continue
+		}
+		if !strings.HasPrefix(candidate, "W/") && candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteETag(tag string) string {
+	if tag == "" || strings.HasPrefix(tag, `"`) || strings.HasPrefix(tag, `W/"`) {
+		return tag
+	}
+	return `"` + tag + `"`
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"net/http"
+	"sync"
+)
+
+// contextWriter is the response writer hooks and handlers reach through
+// context.ResponseWriterFromContext. It turns into a no-op once the endpoint
+// stops serving, so a handler still running after a timeout cannot write
+// into the timeout response or into a writer the server has reclaimed.
+type contextWriter struct {
+	mu       sync.Mutex
+	w        http.ResponseWriter
+	closed   bool
+	detached http.Header
+}
+
+func newContextWriter(w http.ResponseWriter) *contextWriter {
+	return &contextWriter{w: w}
+}
+
+// Header returns the response headers, or a throwaway map once closed.
+func (c *contextWriter) Header() http.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		if c.detached == nil {
+			c.detached = http.Header{}
+		}
+		return c.detached
+	}
+	return c.w.Header()
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (c *contextWriter) WriteHeader(status int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.w.WriteHeader(status)
+	}
+}
+
+// Write implements http.ResponseWriter. It reports http.ErrHandlerTimeout
+// once closed.
+func (c *contextWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return 0, http.ErrHandlerTimeout
+	}
+	return c.w.Write(p)
+}
+
+// Flush implements http.Flusher.
+func (c *contextWriter) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	if flusher, ok := c.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// close detaches the writer. It waits for a write in progress, so the
+// serving goroutine may use the underlying writer once it returns.
+func (c *contextWriter) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ErrInvalidCursor is reported when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// EncodeCursor serializes the last-seen sort keys into an opaque cursor.
+func EncodeCursor(keys any) (string, error) {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor restores sort keys previously produced by EncodeCursor. Errors
+// wrap ErrInvalidCursor and render as invalid_request.
+func DecodeCursor(cursor string, dest any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return nil
+}
+
+// WithCursor returns a copy with keyset pagination metadata. An empty
+// nextCursor marks the last page.
+func (r Response[T]) WithCursor(req *http.Request, nextCursor string, limit int) Response[T] {
+	r.Pagination = &Pagination{Limit: limit, NextCursor: nextCursor}
+	if req == nil || req.URL == nil {
+		return r
+	}
+	r.Links = &Links{Self: cursorLink(req.URL, req.URL.Query().Get("cursor"), limit)}
+	if nextCursor != "" {
+		r.Links.Next = cursorLink(req.URL, nextCursor, limit)
+	}
+	return r
+}
+
+func cursorLink(u *url.URL, cursor string, limit int) string {
+	query := u.Query()
+	query.Del("offset")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	} else {
+		query.Del("cursor")
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	link := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return link.String()
+}
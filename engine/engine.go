@@ -2,19 +2,36 @@ package engine
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aatuh/pureapi-core/endpoint"
 	"github.com/aatuh/pureapi-framework/binder"
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
 	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
 	"github.com/aatuh/pureapi-framework/hooks"
+	"github.com/aatuh/pureapi-framework/middleware/cache"
+	"github.com/aatuh/pureapi-framework/middleware/compress"
+	"github.com/aatuh/pureapi-framework/middleware/forwarded"
+	"github.com/aatuh/pureapi-framework/middleware/loadshed"
+	"github.com/aatuh/pureapi-framework/middleware/maintenance"
+	"github.com/aatuh/pureapi-framework/middleware/querybudget"
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
 	"github.com/aatuh/pureapi-framework/obs/accesslog"
+	"github.com/aatuh/pureapi-framework/obs/audit"
+	csvrenderer "github.com/aatuh/pureapi-framework/renderer/csv"
 	codecjson "github.com/aatuh/pureapi-framework/renderer/json"
+	"github.com/aatuh/pureapi-framework/renderer/ndjson"
 	"github.com/aatuh/pureapi-framework/renderer/registry"
+	"github.com/aatuh/pureapi-framework/settings"
+	"github.com/aatuh/pureapi-framework/transform"
 )
 
 // HandlerFunc is the generic endpoint handler signature.
@@ -23,17 +40,42 @@ type HandlerFunc[TIn any, TOut any] func(ctx context.Context, input TIn) (TOut,
 // Engine wires declarative endpoints to pureapi-core primitives.
 type Engine struct {
 	binder                binder.Binder
+	validator             binder.Validator
+	validationRules       map[string]binder.ValidationRule
 	renderRegistry        *registry.Registry
+	defaultContentType    string
 	errorMapper           *frameworkerrors.ErrorMapper
 	catalog               *frameworkerrors.ErrorCatalog
+	extraCatalogs         []*frameworkerrors.ErrorCatalog
+	requestIDMiddleware   endpoint.Middleware
+	forwardedMiddleware   endpoint.Middleware
 	globalMiddlewares     []endpoint.Middleware
 	contextEnrichers      []hooks.ContextEnricher
 	authorizationPolicies []hooks.AuthorizationPolicy
 	accessLoggers         []accesslog.AccessLogger
 	inputHooks            []hooks.InputHook
 	outputHooks           []hooks.OutputHook
+	envelope              bool
+	timeout               time.Duration
+	compression           *compress.Config
+	converters            map[reflect.Type]binder.Converter
+	modifiers             map[string]binder.Modifier
+	strict                *binder.StrictParams
+	runtimeSettings       *settings.Store
+	maintenance           *maintenance.Config
+	transforms            transform.Rules
+	autoMethods           AutoMethods
+	pathBadRequest        bool
+	loadShedder           *loadshed.Limiter
+	routeDump             io.Writer
+
+	endpointsMu sync.Mutex
+	endpoints   []describedEndpoint
 }
 
+// ErrHandlerTimeout is reported when an endpoint exceeds its configured timeout.
+var ErrHandlerTimeout = errors.New("handler timed out")
+
 // EngineOption configures a new Engine.
 type EngineOption func(*Engine)
 
@@ -51,6 +93,31 @@ func WithBinder(b binder.Binder) EngineOption {
 	}
 }
 
+// WithValidator enables post-binding validation on the engine's DefaultBinder.
+func WithValidator(v binder.Validator) EngineOption {
+	return func(e *Engine) {
+		if v != nil {
+			e.validator = v
+		}
+	}
+}
+
+// WithValidators registers custom rules, e.g. "iban" or "phone", for the
+// `validate` tags of every endpoint. They are added to a copy of the
+// validator set with WithValidator when it is a *binder.TagValidator, or to
+// a new TagValidator when none is set; other validators ignore them.
+// NewEngine panics on an empty name or nil rule.
+func WithValidators(rules map[string]binder.ValidationRule) EngineOption {
+	return func(e *Engine) {
+		if e.validationRules == nil {
+			e.validationRules = make(map[string]binder.ValidationRule, len(rules))
+		}
+		for name, rule := range rules {
+			e.validationRules[name] = rule
+		}
+	}
+}
+
 // WithRenderer overrides the default renderer implementation.
 func WithRenderer(contentType string, fn registry.RenderFunc) EngineOption {
 	return func(e *Engine) {
@@ -65,6 +132,55 @@ func WithRenderer(contentType string, fn registry.RenderFunc) EngineOption {
 	}
 }
 
+// WithBufferPool sets the pool buffered renderers such as the default JSON
+// renderer encode into. A nil pool disables pooling.
+func WithBufferPool(pool registry.BufferPool) EngineOption {
+	return func(e *Engine) {
+		if e.renderRegistry == nil {
+			e.renderRegistry = newDefaultRenderRegistry()
+		}
+		e.renderRegistry.SetBufferPool(pool)
+	}
+}
+
+// WithConverter registers a parser for bound path, query, header, cookie,
+// and form values of type T on the default binder, so types such as UUIDs
+// bind without implementing encoding.TextUnmarshaler.
+func WithConverter[T any](fn func(string) (T, error)) EngineOption {
+	return func(e *Engine) {
+		if fn == nil {
+			return
+		}
+		if e.converters == nil {
+			e.converters = make(map[reflect.Type]binder.Converter)
+		}
+		typ, conv := binder.ConverterFor(fn)
+		e.converters[typ] = conv
+	}
+}
+
+// WithModifier registers a `mod` tag modifier on the default binder, e.g. a
+// Unicode normalization form, replacing a built-in of the same name.
+func WithModifier(name string, fn binder.Modifier) EngineOption {
+	return func(e *Engine) {
+		if name == "" || fn == nil {
+			return
+		}
+		if e.modifiers == nil {
+			e.modifiers = make(map[string]binder.Modifier)
+		}
+		e.modifiers[name] = fn
+	}
+}
+
+// WithDefaultContentType selects the registered renderer used when the client
+// expresses no preference or accepts any media type.
+func WithDefaultContentType(contentType string) EngineOption {
+	return func(e *Engine) {
+		e.defaultContentType = contentType
+	}
+}
+
 // WithErrorMapper overrides the default error mapper.
 func WithErrorMapper(mapper *frameworkerrors.ErrorMapper) EngineOption {
 	return func(e *Engine) {
@@ -74,6 +190,15 @@ func WithErrorMapper(mapper *frameworkerrors.ErrorMapper) EngineOption {
 	}
 }
 
+// WithErrorCatalogs merges subsystem catalogs into the catalog of the
+// engine's error mapper. NewEngine panics when an ID is declared twice with
+// different settings.
+func WithErrorCatalogs(catalogs ...*frameworkerrors.ErrorCatalog) EngineOption {
+	return func(e *Engine) {
+		e.extraCatalogs = append(e.extraCatalogs, catalogs...)
+	}
+}
+
 // WithGlobalMiddlewares adds middlewares applied to every endpoint.
 func WithGlobalMiddlewares(mw ...endpoint.Middleware) EngineOption {
 	return func(e *Engine) {
@@ -147,18 +272,61 @@ func WithOutputHooks(hooks ...hooks.OutputHook) EngineOption {
 	}
 }
 
+// WithEnvelope wraps every successful endpoint output in a Response envelope.
+func WithEnvelope() EngineOption {
+	return func(e *Engine) {
+		e.envelope = true
+	}
+}
+
+// WithTimeout bounds the time every endpoint may spend binding, running hooks,
+// and executing its handler. Exceeding it renders the "timeout" catalog entry.
+func WithTimeout(timeout time.Duration) EngineOption {
+	return func(e *Engine) {
+		if timeout > 0 {
+			e.timeout = timeout
+		}
+	}
+}
+
+// WithRequestID replaces the default request ID middleware, controlling header
+// names, incoming ID validation, and the ID generator.
+func WithRequestID(cfg requestid.Config) EngineOption {
+	return func(e *Engine) {
+		e.requestIDMiddleware = requestid.Middleware(cfg)
+	}
+}
+
+// WithForwardedHeaders trusts Forwarded and X-Forwarded-* headers from the
+// listed proxies. The resolved client IP, scheme, and host are available via
+// forwarded.ClientIP, forwarded.Scheme, and forwarded.Host, and access log
+// entries report the client IP. It panics on an invalid proxy entry.
+func WithForwardedHeaders(cfg forwarded.Config) EngineOption {
+	return func(e *Engine) {
+		e.forwardedMiddleware = forwarded.Middleware(cfg)
+	}
+}
+
+// WithCompression compresses endpoint responses according to Accept-Encoding.
+// Access logs report the compressed byte count.
+func WithCompression(cfg compress.Config) EngineOption {
+	return func(e *Engine) {
+		e.compression = &cfg
+	}
+}
+
 // NewEngine builds an Engine using framework defaults.
 func NewEngine(opts ...EngineOption) *Engine {
 	catalog := frameworkerrors.DefaultErrorCatalog()
 	mapper, _ := frameworkerrors.NewErrorMapper(catalog, "internal_error")
 
-	jsonRenderer := codecjson.Renderer{}
 	engine := &Engine{
-		binder:            binder.NewDefaultBinder(),
-		renderRegistry:    registry.New("application/json", jsonRenderer.RenderFunc()),
-		errorMapper:       mapper,
-		catalog:           catalog,
-		globalMiddlewares: []endpoint.Middleware{endpoint.RequestIDMiddleware()},
+		binder:              binder.NewDefaultBinder(),
+		renderRegistry:      newDefaultRenderRegistry(),
+		errorMapper:         mapper,
+		catalog:             catalog,
+		requestIDMiddleware: endpoint.RequestIDMiddleware(),
+		autoMethods:         AutoMethods{Head: true, Options: true},
 	}
 	for _, opt := range opts {
 		opt(engine)
@@ -171,9 +339,44 @@ func (e *Engine) ensureDefaults() {
 	if e.binder == nil {
 		e.binder = binder.NewDefaultBinder()
 	}
+	if len(e.validationRules) > 0 {
+		e.registerValidationRules()
+	}
+	if e.validator != nil {
+		if db, ok := e.binder.(*binder.DefaultBinder); ok && db.Validator == nil {
+			e.binder = db.WithValidator(e.validator)
+		}
+	}
+	if e.strict != nil {
+		if db, ok := e.binder.(*binder.DefaultBinder); ok {
+			e.binder = db.WithStrictParams(*e.strict)
+		}
+	}
+	if len(e.converters) > 0 {
+		if db, ok := e.binder.(*binder.DefaultBinder); ok {
+			for typ, fn := range e.converters {
+				if _, exists := db.Converters[typ]; !exists {
+					db = db.WithConverter(typ, fn)
+				}
+			}
+			e.binder = db
+		}
+	}
+	if len(e.modifiers) > 0 {
+		if db, ok := e.binder.(*binder.DefaultBinder); ok {
+			for name, fn := range e.modifiers {
+				if _, exists := db.Modifiers[name]; !exists {
+					db = db.WithModifier(name, fn)
+				}
+			}
+			e.binder = db
+		}
+	}
 	if e.renderRegistry == nil {
-		jsonRenderer := codecjson.Renderer{}
-		e.renderRegistry = registry.New("application/json", jsonRenderer.RenderFunc())
+		e.renderRegistry = newDefaultRenderRegistry()
+	}
+	if e.defaultContentType != "" {
+		_ = e.renderRegistry.SetDefault(e.defaultContentType)
 	}
 	if e.catalog == nil {
 		e.catalog = frameworkerrors.DefaultErrorCatalog()
@@ -182,7 +385,44 @@ func (e *Engine) ensureDefaults() {
 		mapper, _ := frameworkerrors.NewErrorMapper(e.catalog, "internal_error")
 		e.errorMapper = mapper
 	}
+	for _, catalog := range e.extraCatalogs {
+		if err := e.errorMapper.Catalog().Merge(catalog); err != nil {
+			panic("framework NewEngine: " + err.Error())
+		}
+	}
 	_ = e.errorMapper.RegisterType((*binder.BindError)(nil), "invalid_request")
+	_ = e.errorMapper.RegisterIs(frameworkerrors.ErrNotFound, "not_found")
+	_ = e.errorMapper.RegisterIs(sql.ErrNoRows, "not_found")
+	_ = e.errorMapper.RegisterIs(frameworkerrors.ErrConflict, "conflict")
+	_ = e.errorMapper.RegisterIs(registry.ErrNotAcceptable, "not_acceptable")
+	_ = e.errorMapper.RegisterIs(ErrHandlerTimeout, "timeout")
+	_ = e.errorMapper.RegisterIs(ErrPreconditionFailed, "precondition_failed")
+	_ = e.errorMapper.RegisterIs(ErrInvalidCursor, "invalid_request")
+	_ = e.errorMapper.RegisterIs(maintenance.ErrUnavailable, maintenance.CatalogID)
+	_ = e.errorMapper.RegisterIs(querybudget.ErrExceeded, querybudget.CatalogID)
+	_ = e.errorMapper.RegisterIs(ErrUnsupportedVersion, "not_found")
+	_ = e.errorMapper.RegisterIs(ErrMethodNotAllowed, "method_not_allowed")
+	_ = e.errorMapper.RegisterIs(binder.ErrBodyTooLarge, "payload_too_large")
+}
+
+// registerValidationRules adds the WithValidators rules to the engine's tag
+// validator.
+func (e *Engine) registerValidationRules() {
+	var tv *binder.TagValidator
+	switch v := e.validator.(type) {
+	case nil:
+		tv = binder.NewTagValidator()
+	case *binder.TagValidator:
+		tv = v.Clone()
+	default:
+		return
+	}
+	for name, rule := range e.validationRules {
+		if err := tv.RegisterRule(name, rule); err != nil {
+			panic("framework NewEngine: " + err.Error())
+		}
+	}
+	e.validator = tv
 }
 
 // EndpointOption configures a declarative endpoint.
@@ -195,6 +435,10 @@ type EndpointMeta struct {
 	OperationID string
 	Tags        []string
 	Extras      map[string]any
+	// Version and Deprecated are filled in for endpoints declared in a
+	// VersionSet group.
+	Version    string
+	Deprecated bool
 }
 
 // WithMeta sets the endpoint metadata.
@@ -312,27 +556,89 @@ func WithEndpointOutputHooks[TIn any, TOut any](hooks ...hooks.OutputHook) Endpo
 	}
 }
 
+// WithEndpointEnvelope overrides the engine envelope setting for this endpoint.
+func WithEndpointEnvelope[TIn any, TOut any](enabled bool) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		ep.envelope = &enabled
+	}
+}
+
+// WithEndpointTimeout overrides the engine timeout for this endpoint. A zero
+// duration disables the timeout.
+func WithEndpointTimeout[TIn any, TOut any](timeout time.Duration) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		if timeout < 0 {
+			timeout = 0
+		}
+		ep.timeout = &timeout
+	}
+}
+
+// WithEndpointCache caches rendered GET/HEAD responses for this endpoint.
+func WithEndpointCache[TIn any, TOut any](cfg cache.Config) EndpointOption[TIn, TOut] {
+	return WithEndpointMiddlewares[TIn, TOut](cache.Middleware(cfg))
+}
+
+// WithEndpointAudit records redacted request and response bodies for this endpoint.
+func WithEndpointAudit[TIn any, TOut any](cfg audit.Config) EndpointOption[TIn, TOut] {
+	return WithEndpointMiddlewares[TIn, TOut](audit.Middleware(cfg))
+}
+
 // Endpoint creates a declarative endpoint definition bound to engine.
 func Endpoint[TIn any, TOut any](engine *Engine, method, path string, handler HandlerFunc[TIn, TOut], opts ...EndpointOption[TIn, TOut]) *DeclarativeEndpoint[TIn, TOut] {
 	if engine == nil {
 		panic("framework Endpoint: engine must not be nil")
 	}
+	routePath, pathParams, err := parsePathTemplate(path)
+	if err != nil {
+		panic("framework Endpoint: " + err.Error())
+	}
 	declarative := &DeclarativeEndpoint[TIn, TOut]{
 		engine:        engine,
 		Method:        strings.ToUpper(strings.TrimSpace(method)),
-		Path:          path,
+		Path:          routePath,
 		handler:       handler,
 		successStatus: 0,
+		pathParams:    pathParams,
 	}
 	for _, opt := range opts {
 		opt(declarative)
 	}
+	if err := declarative.checkValidationTags(); err != nil {
+		panic("framework Endpoint: " + err.Error())
+	}
+	engine.track(declarative)
 	return declarative
 }
 
+// typeChecker is implemented by validators, such as *binder.TagValidator,
+// that can vet an input type's tags before any request arrives.
+type typeChecker interface {
+	CheckType(t reflect.Type) error
+}
+
+// checkValidationTags vets TIn against the validator of the endpoint's
+// binder.
+func (d *DeclarativeEndpoint[TIn, TOut]) checkValidationTags() error {
+	b := d.binder
+	if b == nil {
+		b = d.engine.binder
+	}
+	db, ok := b.(*binder.DefaultBinder)
+	if !ok {
+		return nil
+	}
+	checker, ok := db.Validator.(typeChecker)
+	if !ok {
+		return nil
+	}
+	return checker.CheckType(reflect.TypeFor[TIn]())
+}
+
 // DeclarativeEndpoint describes an endpoint with binding and rendering details.
 type DeclarativeEndpoint[TIn any, TOut any] struct {
 	engine *Engine
+	group  *Group
 	Method string
 	Path   string
 	Meta   EndpointMeta
@@ -349,42 +655,82 @@ type DeclarativeEndpoint[TIn any, TOut any] struct {
 	inputHooks            []hooks.InputHook
 	outputHooks           []hooks.OutputHook
 	successStatus         int
+	envelope              *bool
+	timeout               *time.Duration
+	etag                  bool
+	fields                *fieldSelection
+	bodySchema            binder.BodySchema
+	strict                *binder.StrictParams
+	transforms            transform.Rules
+	pathParams            []PathParam
+	maxBodyBytes          int64
+	loadShedder           *loadshed.Limiter
+	csvExport             *csvrenderer.Config
 }
 
 var _ endpoint.EndpointSpec = (*DeclarativeEndpoint[any, any])(nil)
 
+// baseMiddlewares returns the engine-wide middlewares every endpoint starts
+// with, outermost first.
+func (e *Engine) baseMiddlewares() []endpoint.Middleware {
+	var combined []endpoint.Middleware
+	if e.forwardedMiddleware != nil {
+		combined = append(combined, e.forwardedMiddleware)
+	}
+	if e.requestIDMiddleware != nil {
+		combined = append(combined, e.requestIDMiddleware)
+	}
+	if e.runtimeSettings != nil {
+		combined = append(combined, settings.Middleware(e.runtimeSettings))
+	}
+	return append(combined, e.globalMiddlewares...)
+}
+
+// resolvedMiddlewares merges engine, group, and endpoint middlewares,
+// outermost first.
+func (d *DeclarativeEndpoint[TIn, TOut]) resolvedMiddlewares() []endpoint.Middleware {
+	combined := d.engine.baseMiddlewares()
+	for _, g := range d.group.chain() {
+		combined = append(combined, g.middlewares...)
+	}
+	return append(combined, d.middlewares...)
+}
+
 // ToEndpoint converts the declarative endpoint into a pureapi-core endpoint.
 func (d *DeclarativeEndpoint[TIn, TOut]) ToEndpoint() endpoint.Endpoint {
 	binder := d.binder
 	if binder == nil {
 		binder = d.engine.binder
 	}
+	binder = withBodySchema(binder, d.bodySchema)
+	binder = withStrictParams(binder, d.strict, d.fields)
+	binder = withMaxBodyBytes(binder, d.maxBodyBytes)
 	mapper := d.errorMapper
+	if mapper == nil {
+		mapper = d.group.resolvedErrorMapper()
+	}
 	if mapper == nil {
 		mapper = d.engine.errorMapper
 	}
-	combined := make([]endpoint.Middleware, 0, len(d.engine.globalMiddlewares)+len(d.middlewares))
-	combined = append(combined, d.engine.globalMiddlewares...)
-	combined = append(combined, d.middlewares...)
-
+	combined := d.resolvedMiddlewares()
 	contextEnrichers := append([]hooks.ContextEnricher{}, d.engine.contextEnrichers...)
-	contextEnrichers = append(contextEnrichers, d.contextEnrichers...)
 	authorizationPolicies := append([]hooks.AuthorizationPolicy{}, d.engine.authorizationPolicies...)
+	for _, g := range d.group.chain() {
+		contextEnrichers = append(contextEnrichers, g.contextEnrichers...)
+		authorizationPolicies = append(authorizationPolicies, g.authorizationPolicies...)
+	}
+	contextEnrichers = append(contextEnrichers, d.contextEnrichers...)
 	authorizationPolicies = append(authorizationPolicies, d.authorizationPolicies...)
 	accessLoggers := append([]accesslog.AccessLogger{}, d.engine.accessLoggers...)
 	accessLoggers = append(accessLoggers, d.accessLoggers...)
 	renderReg := d.engine.renderRegistry.Clone()
 	if renderReg == nil {
-		jsonRenderer := codecjson.Renderer{}
-		renderReg = registry.New("application/json", jsonRenderer.RenderFunc())
+		renderReg = newDefaultRenderRegistry()
 	}
 	for _, rr := range d.renderers {
 		renderReg.Register(rr.contentType, rr.fn)
 	}
-	inputHooks := append([]hooks.InputHook{}, d.engine.inputHooks...)
-	inputHooks = append(inputHooks, d.inputHooks...)
-	outputHooks := append([]hooks.OutputHook{}, d.engine.outputHooks...)
-	outputHooks = append(outputHooks, d.outputHooks...)
+	inputHooks, outputHooks := d.resolvedHooks()
 
 	handler := d.wrapHandler(binder, renderReg, mapper, contextEnrichers, authorizationPolicies, accessLoggers, inputHooks, outputHooks)
 	var core endpoint.Endpoint = endpoint.NewEndpoint(d.Path, d.Method)
@@ -405,12 +751,24 @@ func (d *DeclarativeEndpoint[TIn, TOut]) wrapHandler(
 	inputHooks []hooks.InputHook,
 	outputHooks []hooks.OutputHook,
 ) http.HandlerFunc {
+	timeout := d.effectiveTimeout()
+	compression := d.engine.compression
+	transforms := d.resolvedTransforms()
+	bodyLimit := d.bodyLimit(binder)
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
+		ctx := accesslog.WithFields(r.Context())
+		requestCtx := ctx
 		lw := newLoggingResponseWriter(w)
 		w = lw
 		start := time.Now()
 		var handlerErr error
+		timedOut := false
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
 
 		defer func() {
 			entry := accesslog.Entry{
@@ -418,11 +776,14 @@ func (d *DeclarativeEndpoint[TIn, TOut]) wrapHandler(
 				Path:         r.URL.Path,
 				Status:       lw.Status(),
 				Duration:     time.Since(start),
-				RequestID:    endpoint.RequestIDFromContext(ctx),
+				RequestID:    requestid.FromContext(ctx),
 				RemoteAddr:   r.RemoteAddr,
+				ClientIP:     forwarded.ClientIP(r),
 				UserAgent:    r.UserAgent(),
 				ResponseSize: lw.BytesWritten(),
 				Err:          handlerErr,
+				TimedOut:     timedOut,
+				Fields:       accesslog.FieldsFromContext(ctx),
 			}
 			for _, logger := range accessLoggers {
 				if logger == nil {
@@ -432,6 +793,17 @@ func (d *DeclarativeEndpoint[TIn, TOut]) wrapHandler(
 			}
 		}()
 
+		var out http.ResponseWriter = lw
+		if compression != nil {
+			cw := compress.NewWriter(lw, r, *compression)
+			defer func() { _ = cw.Close() }()
+			// Audit records keep the body as written, before encoding.
+			out = audit.TapResponse(r.Context(), cw)
+		}
+		meta := &ResponseController{}
+		out = &metaResponseWriter{ResponseWriter: out, meta: meta}
+		ctx = withResponseMeta(ctx, meta)
+
 		defer func() {
 			if rec := recover(); rec != nil {
 				var panicErr error
@@ -442,78 +814,251 @@ func (d *DeclarativeEndpoint[TIn, TOut]) wrapHandler(
 					panicErr = fmt.Errorf("panic: %v", v)
 				}
 				handlerErr = panicErr
-				d.writeError(ctx, lw, renderRegistry, r, mapper, panicErr)
+				writeError(ctx, out, renderRegistry, r, mapper, panicErr)
 			}
 		}()
 
-		var err error
-		if ctx, err = executeContextEnrichers(ctx, r, contextEnrichers); err != nil {
+		fail := func(err error) {
+			if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) && requestCtx.Err() == nil {
+				timedOut = true
+				err = fmt.Errorf("%w after %s", ErrHandlerTimeout, timeout)
+			}
 			handlerErr = err
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			if timedOut {
+				writeError(context.WithoutCancel(ctx), out, renderRegistry, r, mapper, err)
 				return
 			}
-			d.writeError(ctx, lw, renderRegistry, r, mapper, err)
-			return
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || hooks.IsRendered(err) {
+				return
+			}
+			writeError(ctx, out, renderRegistry, r, mapper, err)
 		}
-		r = r.WithContext(ctx)
 
-		var input TIn
-		if binder != nil {
-			if err = binder.Bind(ctx, r, &input); err != nil {
-				handlerErr = err
-				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-					return
-				}
-				d.writeError(ctx, lw, renderRegistry, r, mapper, err)
+		var err error
+		if err = d.engine.checkMaintenance(r); err != nil {
+			maintenance.SetRetryAfter(out.Header(), err)
+			fail(err)
+			return
+		}
+		if err = d.checkPathParams(r); err != nil {
+			fail(err)
+			return
+		}
+		release, err := d.acquireSlots(r)
+		if err != nil {
+			loadshed.SetRetryAfter(out.Header(), err)
+			fail(err)
+			return
+		}
+		defer release()
+		if err = limitBody(out, r, bodyLimit); err != nil {
+			fail(err)
+			return
+		}
+		if !streamsOutput[TOut]() {
+			if _, _, err = renderRegistry.Negotiate(r.Header.Get("Accept")); err != nil {
+				fail(err)
 				return
 			}
 		}
-		if err = executeInputHooks(ctx, &input, inputHooks); err != nil {
-			handlerErr = err
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		if ctx, err = executeContextEnrichers(ctx, r, contextEnrichers); err != nil {
+			fail(err)
+			return
+		}
+		r = r.WithContext(ctx)
+		ctx = frameworkcontext.WithRequest(ctx, r)
+		ctxWriter := newContextWriter(out)
+		defer ctxWriter.close()
+		ctx = frameworkcontext.WithResponseWriter(ctx, ctxWriter)
+
+		var selectedFields []string
+		if d.fields != nil {
+			if selectedFields, err = d.fields.parse(r); err != nil {
+				fail(err)
 				return
 			}
-			d.writeError(ctx, lw, renderRegistry, r, mapper, err)
+			if selectedFields != nil {
+				ctx = context.WithValue(ctx, fieldsContextKey{}, selectedFields)
+			}
+		}
+
+		var input TIn
+		ctx = withValidationErrors(ctx)
+		if err = bindInput(ctx, binder, r, &input, inputHooks); err != nil {
+			fail(err)
 			return
 		}
 		if err = executeAuthorizationPolicies(ctx, &input, authorizationPolicies); err != nil {
-			handlerErr = err
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				return
-			}
-			d.writeError(ctx, lw, renderRegistry, r, mapper, err)
+			fail(err)
 			return
 		}
 
 		var output TOut
-		if output, err = d.handler(ctx, input); err != nil {
-			handlerErr = err
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				return
+		output, err = d.invoke(ctx, input, timeout > 0, ctxWriter.close)
+		if err = reportedValidation(ctx, err); err != nil {
+			fail(err)
+			return
+		}
+		if result, ok := resultOutput(output); ok {
+			writeResult(out, result)
+			return
+		}
+		if file, ok := fileOutput(output); ok {
+			status := d.successStatus
+			if status == 0 {
+				status = http.StatusOK
+			}
+			status = meta.statusOr(status)
+			started, fileErr := serveFileResult(out, r, status, file)
+			if fileErr != nil {
+				if started {
+					handlerErr = fileErr
+					return
+				}
+				fail(fileErr)
 			}
-			d.writeError(ctx, lw, renderRegistry, r, mapper, err)
 			return
 		}
-		if err = executeOutputHooks(ctx, &output, outputHooks); err != nil {
-			handlerErr = err
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				return
+		if streamer, ok := any(output).(Streamer); ok {
+			status := d.successStatus
+			if status == 0 {
+				status = http.StatusOK
+			}
+			status = meta.statusOr(status)
+			started, streamErr := streamEvents(ctx, out, status, streamer, outputHooks, mapper, requestid.FromContext(ctx))
+			if streamErr != nil {
+				if started {
+					handlerErr = streamErr
+					return
+				}
+				fail(streamErr)
 			}
-			d.writeError(ctx, lw, renderRegistry, r, mapper, err)
+			return
+		}
+		if streamer, ok := any(output).(ArrayStreamer); ok {
+			status := d.successStatus
+			if status == 0 {
+				status = http.StatusOK
+			}
+			status = meta.statusOr(status)
+			var started bool
+			var streamErr error
+			switch {
+			case d.wantsCSV(renderRegistry, r):
+				started, streamErr = streamCSV(ctx, out, status, streamer, outputHooks, *d.csvExport)
+			case wantsNDJSON(renderRegistry, r):
+				started, streamErr = streamArray(ctx, out, status, streamer, outputHooks, ndjsonArray)
+			default:
+				started, streamErr = streamArray(ctx, out, status, streamer, outputHooks, jsonArray)
+			}
+			if streamErr != nil {
+				if started {
+					handlerErr = streamErr
+					return
+				}
+				fail(streamErr)
+			}
+			return
+		}
+		if err = executeOutputHooks(ctx, &output, outputHooks); err != nil {
+			fail(err)
 			return
 		}
 		status := d.successStatus
 		if status == 0 {
 			status = defaultSuccessStatus(d.Method)
 		}
-		if err = renderRegistry.Render(ctx, lw, r, status, output); err != nil {
+		status = meta.statusOr(status)
+		var payload any = output
+		if payload, err = transforms.Apply(ctx, payload); err != nil {
+			fail(err)
+			return
+		}
+		if selectedFields != nil {
+			if payload, err = projectFields(payload, selectedFields); err != nil {
+				fail(err)
+				return
+			}
+		}
+		if etag := outputETag(output, payload, selectedFields, d.etag); etag != "" {
+			out.Header().Set("ETag", etag)
+			if notModified(r, etag) {
+				out.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		if d.wantsCSV(renderRegistry, r) {
+			if exported, started, csvErr := writeCSV(out, status, payload, *d.csvExport); exported {
+				if csvErr != nil && !started {
+					fail(csvErr)
+				} else {
+					handlerErr = csvErr
+				}
+				return
+			}
+		}
+		if d.envelopeEnabled() {
+			payload = wrapEnvelope(payload)
+		}
+		if err = renderRegistry.Render(ctx, out, r, status, payload); err != nil {
 			handlerErr = err
-			http.Error(lw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			http.Error(out, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	}
+}
+
+// invoke runs the handler. When enforce is set the handler runs in its own
+// goroutine so the response can be written as soon as ctx expires, even if the
+// handler ignores cancellation; abandon is called before returning without
+// it.
+func (d *DeclarativeEndpoint[TIn, TOut]) invoke(ctx context.Context, input TIn, enforce bool, abandon func()) (TOut, error) {
+	if !enforce {
+		return d.handler(ctx, input)
+	}
+	type result struct {
+		output   TOut
+		err      error
+		panicked bool
+		rec      any
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- result{panicked: true, rec: rec}
+			}
+		}()
+		output, err := d.handler(ctx, input)
+		done <- result{output: output, err: err}
+	}()
+	select {
+	case res := <-done:
+		if res.panicked {
+			panic(res.rec)
 		}
+		return res.output, res.err
+	case <-ctx.Done():
+		abandon()
+		var zero TOut
+		return zero, ctx.Err()
 	}
 }
 
-func (d *DeclarativeEndpoint[TIn, TOut]) writeError(
+func (d *DeclarativeEndpoint[TIn, TOut]) effectiveTimeout() time.Duration {
+	if d.timeout != nil {
+		return *d.timeout
+	}
+	return d.engine.timeout
+}
+
+func (d *DeclarativeEndpoint[TIn, TOut]) envelopeEnabled() bool {
+	if d.envelope != nil {
+		return *d.envelope
+	}
+	return d.engine.envelope
+}
+
+func writeError(
 	ctx context.Context,
 	w http.ResponseWriter,
 	renderRegistry *registry.Registry,
@@ -523,11 +1068,15 @@ func (d *DeclarativeEndpoint[TIn, TOut]) writeError(
 ) {
 	mapped := mapper.Map(err)
 	payload := frameworkerrors.RenderError(mapped)
-	if requestID := endpoint.RequestIDFromContext(ctx); requestID != "" {
+	if requestID := requestid.FromContext(ctx); requestID != "" {
 		payload = payload.WithOrigin(requestID)
 	}
 	if renderRegistry != nil {
-		if renderErr := renderRegistry.Render(ctx, w, req, mapped.Entry.Status, payload); renderErr == nil {
+		renderErr := renderRegistry.Render(ctx, w, req, mapped.Entry.Status, payload)
+		if errors.Is(renderErr, registry.ErrNotAcceptable) {
+			renderErr = renderRegistry.RenderDefault(ctx, w, mapped.Entry.Status, payload)
+		}
+		if renderErr == nil {
 			return
 		}
 	}
@@ -569,7 +1118,7 @@ func executeContextEnrichers(ctx context.Context, r *http.Request, enrichers []h
 	return current, nil
 }
 
-func executeInputHooks(ctx context.Context, value any, hooks []hooks.InputHook) error {
+func executeOutputHooks(ctx context.Context, value any, hooks []hooks.OutputHook) error {
 	if len(hooks) == 0 {
 		return nil
 	}
@@ -584,19 +1133,13 @@ func executeInputHooks(ctx context.Context, value any, hooks []hooks.InputHook)
 	return nil
 }
 
-func executeOutputHooks(ctx context.Context, value any, hooks []hooks.OutputHook) error {
-	if len(hooks) == 0 {
-		return nil
-	}
-	for _, hook := range hooks {
-		if hook == nil {
-			continue
-		}
-		if err := hook.Process(ctx, value); err != nil {
-			return err
-		}
-	}
-	return nil
+// newDefaultRenderRegistry returns a registry with the pooled JSON renderer
+// and the NDJSON renderer for list payloads.
+func newDefaultRenderRegistry() *registry.Registry {
+	reg := registry.New("application/json", nil)
+	reg.RegisterBuffered("application/json", codecjson.Renderer{}.BufferRenderFunc())
+	reg.RegisterBuffered(ndjson.ContentType, ndjson.Renderer{}.BufferRenderFunc())
+	return reg
 }
 
 func defaultSuccessStatus(method string) int {
@@ -634,6 +1177,21 @@ func (lw *loggingResponseWriter) Write(p []byte) (int, error) {
 	return n, err
 }
 
+// Flush implements http.Flusher when the underlying writer supports it.
+func (lw *loggingResponseWriter) Flush() {
+	if lw.status == 0 {
+		lw.status = http.StatusOK
+	}
+	if flusher, ok := lw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (lw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
+
 func (lw *loggingResponseWriter) Status() int {
 	if lw.status == 0 {
 		return http.StatusOK
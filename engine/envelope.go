@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Pagination describes the window of a paginated collection.
+type Pagination struct {
+	Total  int64 `json:"total"`
+	Offset int   `json:"offset"`
+	Limit  int   `json:"limit"`
+	// Count tells how Total was computed; empty means an exact count.
+	Count      CountStrategy `json:"count,omitempty"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// CountStrategy describes how the total of a paginated collection was counted.
+type CountStrategy string
+
+const (
+	// CountExact totals come from a full count.
+	CountExact CountStrategy = "exact"
+	// CountEstimated totals come from planner or table statistics.
+	CountEstimated CountStrategy = "estimated"
+	// CountCapped totals stop at a limit: at least Total items exist.
+	CountCapped CountStrategy = "capped"
+)
+
+// Links carries navigation links for a response.
+type Links struct {
+	Self string `json:"self,omitempty"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// Response wraps handler data with optional pagination, links, and warnings.
+type Response[T any] struct {
+	Data       T           `json:"data"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Links      *Links      `json:"links,omitempty"`
+	Warnings   []string    `json:"warnings,omitempty"`
+}
+
+func (Response[T]) isEnvelope() {}
+
+type envelope interface {
+	isEnvelope()
+}
+
+// NewResponse wraps data in a Response.
+func NewResponse[T any](data T) Response[T] {
+	return Response[T]{Data: data}
+}
+
+// WithPagination returns a copy with pagination and links computed from req.
+// Handlers can obtain req via context.RequestFromContext.
+func (r Response[T]) WithPagination(req *http.Request, total int64, offset, limit int) Response[T] {
+	r.Pagination, r.Links = Paginate(req, total, offset, limit)
+	return r
+}
+
+// WithCountedPagination is like WithPagination for totals counted with
+// strategy. Capped totals keep a next link past the cap, since more items may
+// follow.
+func (r Response[T]) WithCountedPagination(req *http.Request, total int64, strategy CountStrategy, offset, limit int) Response[T] {
+	r.Pagination, r.Links = paginate(req, total, strategy, offset, limit)
+	return r
+}
+
+// WithWarnings returns a copy with warnings appended.
+func (r Response[T]) WithWarnings(warnings ...string) Response[T] {
+	r.Warnings = append(append([]string{}, r.Warnings...), warnings...)
+	return r
+}
+
+// Paginate builds pagination metadata and self/next/prev links by rewriting the
+// offset and limit query parameters of req.
+func Paginate(req *http.Request, total int64, offset, limit int) (*Pagination, *Links) {
+	return paginate(req, total, "", offset, limit)
+}
+
+func paginate(req *http.Request, total int64, strategy CountStrategy, offset, limit int) (*Pagination, *Links) {
+	if offset < 0 {
+		offset = 0
+	}
+	pagination := &Pagination{Total: total, Offset: offset, Limit: limit, Count: strategy}
+	if req == nil || req.URL == nil {
+		return pagination, nil
+	}
+	links := &Links{Self: pageLink(req.URL, offset, limit)}
+	if limit > 0 {
+		if int64(offset+limit) < total || strategy == CountCapped {
+			links.Next = pageLink(req.URL, offset+limit, limit)
+		}
+		if offset > 0 {
+			prev := offset - limit
+			if prev < 0 {
+				prev = 0
+			}
+			links.Prev = pageLink(req.URL, prev, limit)
+		}
+	}
+	return pagination, links
+}
+
+func pageLink(u *url.URL, offset, limit int) string {
+	query := u.Query()
+	query.Set("offset", strconv.Itoa(offset))
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	link := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return link.String()
+}
+
+// wrapEnvelope wraps output unless it is already an envelope.
+func wrapEnvelope(output any) any {
+	if _, ok := output.(envelope); ok {
+		return output
+	}
+	return Response[any]{Data: output}
+}
@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/aatuh/pureapi-framework/hooks"
+	csvrenderer "github.com/aatuh/pureapi-framework/renderer/csv"
+	"github.com/aatuh/pureapi-framework/renderer/registry"
+)
+
+// WithEndpointCSVExport lets clients fetch this endpoint's list output as CSV
+// by sending Accept: text/csv. Slice outputs, the first slice field of struct
+// outputs, and ArrayStreamer outputs are exported; rows are written to the
+// client as they are encoded rather than buffered. Headers come from `csv`
+// tags, then `json` tags. Error responses stay JSON.
+func WithEndpointCSVExport[TIn any, TOut any](cfg csvrenderer.Config) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		ep.csvExport = &cfg
+		ep.renderers = append(ep.renderers, rendererRegistration{
+			contentType: csvrenderer.MediaType,
+			fn:          csvrenderer.Renderer{Config: cfg}.RenderFunc(),
+		})
+	}
+}
+
+// wantsCSV reports whether the endpoint exports CSV and the client prefers it.
+func (d *DeclarativeEndpoint[TIn, TOut]) wantsCSV(renderRegistry *registry.Registry, r *http.Request) bool {
+	if d.csvExport == nil {
+		return false
+	}
+	ct, _, err := renderRegistry.Negotiate(r.Header.Get("Accept"))
+	return err == nil && ct == csvrenderer.MediaType
+}
+
+// csvResponse writes CSV rows to w, sending the headers with the first write.
+type csvResponse struct {
+	w       http.ResponseWriter
+	status  int
+	cfg     csvrenderer.Config
+	started bool
+}
+
+func (c *csvResponse) start() {
+	if c.started {
+		return
+	}
+	c.started = true
+	c.w.Header().Set("Content-Type", csvrenderer.ContentType)
+	c.w.Header().Set("X-Accel-Buffering", "no")
+	if disposition := c.cfg.ContentDisposition(); disposition != "" {
+		c.w.Header().Set("Content-Disposition", disposition)
+	}
+	c.w.WriteHeader(c.status)
+}
+
+func (c *csvResponse) Write(p []byte) (int, error) {
+	c.start()
+	return c.w.Write(p)
+}
+
+// writeCSV streams the rows of payload to w. It reports whether payload had
+// rows to export and whether any bytes were written.
+func writeCSV(w http.ResponseWriter, status int, payload any, cfg csvrenderer.Config) (bool, bool, error) {
+	rows, ok := csvrenderer.Rows(payload)
+	if !ok {
+		return false, false, nil
+	}
+	resp := &csvResponse{w: w, status: status, cfg: cfg}
+	cw := csvrenderer.NewWriter(resp, cfg)
+	err := cw.Header(rows.Type().Elem())
+	for i := 0; err == nil && i < rows.Len(); i++ {
+		if err = cw.Write(rows.Index(i).Interface()); err == nil && (i+1)%arrayFlushEvery == 0 {
+			err = flushCSV(cw, w)
+		}
+	}
+	if err == nil {
+		err = flushCSV(cw, w)
+	}
+	if err != nil {
+		return true, resp.started, err
+	}
+	resp.start()
+	return true, true, nil
+}
+
+// streamCSV writes the streamer output as CSV, running output hooks against
+// each item. It reports whether any bytes were written.
+func streamCSV(
+	ctx context.Context,
+	w http.ResponseWriter,
+	status int,
+	streamer ArrayStreamer,
+	outputHooks []hooks.OutputHook,
+	cfg csvrenderer.Config,
+) (bool, error) {
+	resp := &csvResponse{w: w, status: status, cfg: cfg}
+	cw := csvrenderer.NewWriter(resp, cfg)
+	// Timer flushes surface write errors on the next write or final flush.
+	idle := &idleFlusher{flush: func() { _ = flushCSV(cw, w) }}
+	count := 0
+	err := streamer.StreamArray(ctx, func(item any) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := processEventData(ctx, item, outputHooks)
+		if err != nil {
+			return err
+		}
+		idle.mu.Lock()
+		defer idle.mu.Unlock()
+		if err := cw.Write(data); err != nil {
+			return err
+		}
+		count++
+		idle.wrote()
+		if count%arrayFlushEvery == 0 {
+			idle.dirty = false
+			return flushCSV(cw, w)
+		}
+		return nil
+	})
+	idle.mu.Lock()
+	idle.stop()
+	idle.mu.Unlock()
+	if flushErr := flushCSV(cw, w); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		return resp.started, err
+	}
+	resp.start()
+	return true, nil
+}
+
+func flushCSV(cw *csvrenderer.Writer, w io.Writer) error {
+	if err := cw.Flush(); err != nil {
+		return err
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
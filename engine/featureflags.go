@@ -0,0 +1,13 @@
+package engine
+
+import (
+	"github.com/aatuh/pureapi-framework/featureflags"
+)
+
+// WithEndpointFeatureFlag hides the endpoint while cfg.Flag is off: requests
+// render "not_found" (or "forbidden" with cfg.Forbidden) before binding. The
+// flag is read from the snapshot taken by a featureflags.Enricher registered
+// on the engine or group, falling back to cfg.Provider.
+func WithEndpointFeatureFlag[TIn any, TOut any](cfg featureflags.GateConfig) EndpointOption[TIn, TOut] {
+	return WithEndpointContextEnrichers[TIn, TOut](featureflags.Gate(cfg))
+}
@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+const defaultFieldsParam = "fields"
+
+type fieldsContextKey struct{}
+
+// fieldSelection configures sparse fieldsets for an endpoint.
+type fieldSelection struct {
+	param   string
+	allowed map[string]struct{}
+}
+
+// WithEndpointFieldSelection lets clients request a subset of output fields
+// through the "fields" query parameter (e.g. ?fields=id,name). Only allowed
+// fields may be requested; an empty allow-list accepts any field of the
+// output, or of its rows for list outputs.
+// Selected fields are available to handlers via SelectedFields.
+func WithEndpointFieldSelection[TIn any, TOut any](allowed ...string) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		selection := &fieldSelection{param: defaultFieldsParam}
+		if len(allowed) > 0 {
+			selection.allowed = make(map[string]struct{}, len(allowed))
+			for _, field := range allowed {
+				selection.allowed[field] = struct{}{}
+			}
+		}
+		ep.fields = selection
+	}
+}
+
+// SelectedFields returns the fields requested by the client, or nil when the
+// full representation should be returned.
+func SelectedFields(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsContextKey{}).([]string)
+	return append([]string(nil), fields...)
+}
+
+// parse reads and validates the requested fields from r.
+func (s *fieldSelection) parse(r *http.Request) ([]string, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(s.param))
+	if raw == "" {
+		return nil, nil
+	}
+	var fields []string
+	var fieldErrors []binder.FieldError
+	seen := map[string]struct{}{}
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if _, dup := seen[field]; dup {
+			continue
+		}
+		seen[field] = struct{}{}
+		if s.allowed != nil {
+			if _, ok := s.allowed[field]; !ok {
+				fieldErrors = append(fieldErrors, binder.NewFieldError(s.param, binder.SourceQuery, fmt.Sprintf("field %q cannot be selected", field)))
+				continue
+			}
+		}
+		fields = append(fields, field)
+	}
+	if len(fieldErrors) > 0 {
+		return nil, binder.NewBindError("Invalid field selection", fieldErrors)
+	}
+	return fields, nil
+}
+
+// projectFields reduces output to the selected fields. Slices are projected
+// element by element, envelopes inside "data", and list outputs such as
+// {"entities":[...],"count":n} row by row.
+func projectFields(output any, fields []string) (any, error) {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("project fields: %w", err)
+	}
+	// Numbers stay json.Number so large integers keep their precision.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("project fields: %w", err)
+	}
+	keep := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		keep[field] = struct{}{}
+	}
+	if _, ok := output.(envelope); ok {
+		if m, ok := generic.(map[string]any); ok {
+			m["data"] = projectValue(m["data"], keep)
+			return m, nil
+		}
+	}
+	return projectValue(generic, keep), nil
+}
+
+// projectValue prunes value to the selected fields. An object lacking one of
+// them that holds exactly one list wraps rows, so the rows are pruned and
+// the wrapper's other members, such as counts, are kept.
+func projectValue(value any, keep map[string]struct{}) any {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return pruneFields(value, keep)
+	}
+	for field := range keep {
+		if _, ok := m[field]; ok {
+			continue
+		}
+		if key, ok := singleList(m); ok {
+			m[key] = pruneFields(m[key], keep)
+			return m
+		}
+		break
+	}
+	return pruneFields(m, keep)
+}
+
+// singleList returns the key of the only array member of m.
+func singleList(m map[string]any) (string, bool) {
+	found := ""
+	for key, member := range m {
+		if _, ok := member.([]any); !ok {
+			continue
+		}
+		if found != "" {
+			return "", false
+		}
+		found = key
+	}
+	return found, found != ""
+}
+
+func pruneFields(value any, keep map[string]struct{}) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key := range v {
+			if _, ok := keep[key]; !ok {
+				delete(v, key)
+			}
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = pruneFields(v[i], keep)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// FileResult is returned by handlers to send a download. Readers that are
+// also io.Seekers are served with range and conditional request support;
+// others are copied as they are read. Readers implementing io.Closer are
+// closed once the response is written.
+type FileResult struct {
+	Reader io.Reader
+	// ContentType defaults to the type of Filename's extension, then
+	// application/octet-stream.
+	ContentType string
+	// Filename is sent in the Content-Disposition header.
+	Filename string
+	// Size is sent as Content-Length for non-seekable readers when positive.
+	Size int64
+	// ModTime is sent as Last-Modified for seekable readers when set.
+	ModTime time.Time
+	// Inline asks browsers to display the file instead of downloading it.
+	Inline bool
+}
+
+// errNoFileReader is reported for a FileResult without a Reader.
+var errNoFileReader = errors.New("file result has no reader")
+
+// fileOutput returns the FileResult held by output, if any.
+func fileOutput(output any) (FileResult, bool) {
+	switch v := output.(type) {
+	case FileResult:
+		return v, true
+	case *FileResult:
+		if v != nil {
+			return *v, true
+		}
+	}
+	return FileResult{}, false
+}
+
+// serveFileResult writes f with status, or 200 and 206 for seekable readers.
+// It reports whether any bytes were written.
+func serveFileResult(w http.ResponseWriter, r *http.Request, status int, f FileResult) (bool, error) {
+	if closer, ok := f.Reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if f.Reader == nil {
+		return false, errNoFileReader
+	}
+	contentType := f.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(f.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	disposition := "attachment"
+	if f.Inline {
+		disposition = "inline"
+	}
+	if f.Filename != "" {
+		disposition = mime.FormatMediaType(disposition, map[string]string{"filename": f.Filename})
+	}
+	w.Header().Set("Content-Disposition", disposition)
+
+	if seeker, ok := f.Reader.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, f.Filename, f.ModTime, seeker)
+		return true, nil
+	}
+	if f.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(f.Size, 10))
+	}
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return true, nil
+	}
+	_, err := io.Copy(w, f.Reader)
+	return true, err
+}
@@ -0,0 +1,178 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
+	"github.com/aatuh/pureapi-framework/hooks"
+	"github.com/aatuh/pureapi-framework/transform"
+)
+
+// Group declares endpoints under a shared path prefix with shared options.
+type Group struct {
+	engine *Engine
+	parent *Group
+	prefix string
+
+	middlewares           []endpoint.Middleware
+	contextEnrichers      []hooks.ContextEnricher
+	authorizationPolicies []hooks.AuthorizationPolicy
+	errorMapper           *frameworkerrors.ErrorMapper
+	version               *versionBinding
+	transforms            transform.Rules
+}
+
+// GroupOption configures a Group.
+type GroupOption func(*Group)
+
+// WithGroupMiddlewares attaches middlewares applied to every endpoint in the group.
+func WithGroupMiddlewares(mw ...endpoint.Middleware) GroupOption {
+	return func(g *Group) {
+		if len(mw) == 0 {
+			return
+		}
+		combined := make([]endpoint.Middleware, 0, len(g.middlewares)+len(mw))
+		combined = append(combined, g.middlewares...)
+		combined = append(combined, mw...)
+		g.middlewares = combined
+	}
+}
+
+// WithGroupContextEnrichers attaches context enrichers executed for every endpoint in the group.
+func WithGroupContextEnrichers(enrichers ...hooks.ContextEnricher) GroupOption {
+	return func(g *Group) {
+		for _, enricher := range enrichers {
+			if enricher == nil {
+				continue
+			}
+			g.contextEnrichers = append(g.contextEnrichers, enricher)
+		}
+	}
+}
+
+// WithGroupAuthorizationPolicies attaches authorization policies evaluated for every endpoint in the group.
+func WithGroupAuthorizationPolicies(policies ...hooks.AuthorizationPolicy) GroupOption {
+	return func(g *Group) {
+		for _, policy := range policies {
+			if policy == nil {
+				continue
+			}
+			g.authorizationPolicies = append(g.authorizationPolicies, policy)
+		}
+	}
+}
+
+// WithGroupErrorMapper overrides the error mapper for endpoints in the group.
+func WithGroupErrorMapper(mapper *frameworkerrors.ErrorMapper) GroupOption {
+	return func(g *Group) {
+		if mapper != nil {
+			g.errorMapper = mapper
+		}
+	}
+}
+
+// Group creates an endpoint group rooted at prefix.
+func (e *Engine) Group(prefix string, opts ...GroupOption) *Group {
+	g := &Group{engine: e, prefix: normalizePrefix(prefix)}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Group creates a nested group whose prefix and options extend the parent.
+func (g *Group) Group(prefix string, opts ...GroupOption) *Group {
+	child := &Group{engine: g.engine, parent: g, prefix: normalizePrefix(prefix)}
+	for _, opt := range opts {
+		opt(child)
+	}
+	return child
+}
+
+// Prefix returns the full path prefix including parent groups.
+func (g *Group) Prefix() string {
+	if g == nil {
+		return ""
+	}
+	if g.parent == nil {
+		return g.prefix
+	}
+	return g.parent.Prefix() + g.prefix
+}
+
+// Engine returns the engine the group belongs to.
+func (g *Group) Engine() *Engine {
+	if g == nil {
+		return nil
+	}
+	return g.engine
+}
+
+// GroupEndpoint creates a declarative endpoint under the group's prefix.
+func GroupEndpoint[TIn any, TOut any](group *Group, method, path string, handler HandlerFunc[TIn, TOut], opts ...EndpointOption[TIn, TOut]) *DeclarativeEndpoint[TIn, TOut] {
+	if group == nil {
+		panic("framework GroupEndpoint: group must not be nil")
+	}
+	declarative := Endpoint(group.engine, method, joinPath(group.Prefix(), path), handler, opts...)
+	declarative.group = group
+	if binding := group.versionBinding(); binding != nil {
+		declarative.Meta.Version = binding.version.Name
+		declarative.Meta.Deprecated = declarative.Meta.Deprecated || binding.version.Deprecated
+		binding.set.add(binding.version.Name, declarative, declarative.Method, declarative.Path)
+	}
+	return declarative
+}
+
+// chain returns the group lineage ordered from the outermost group.
+func (g *Group) chain() []*Group {
+	var out []*Group
+	for current := g; current != nil; current = current.parent {
+		out = append([]*Group{current}, out...)
+	}
+	return out
+}
+
+// versionBinding returns the version of the nearest versioned group.
+func (g *Group) versionBinding() *versionBinding {
+	for current := g; current != nil; current = current.parent {
+		if current.version != nil {
+			return current.version
+		}
+	}
+	return nil
+}
+
+func (g *Group) resolvedErrorMapper() *frameworkerrors.ErrorMapper {
+	for current := g; current != nil; current = current.parent {
+		if current.errorMapper != nil {
+			return current.errorMapper
+		}
+	}
+	return nil
+}
+
+func normalizePrefix(prefix string) string {
+	prefix = strings.TrimSpace(prefix)
+	prefix = strings.TrimRight(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
+
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" || path == "/" {
+		return prefix
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return prefix + path
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"reflect"
+
+	"github.com/aatuh/pureapi-framework/binder"
+	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
+	"github.com/aatuh/pureapi-framework/hooks"
+)
+
+// HookChain lists the effective input and output hooks of an endpoint in
+// execution order.
+type HookChain struct {
+	Method string
+	Path   string
+	Input  []hooks.HookInfo
+	Output []hooks.HookInfo
+}
+
+// EndpointDescription exposes the static shape of a declared endpoint for
+// documentation and client generation.
+type EndpointDescription struct {
+	Method        string
+	Path          string
+	PathParams    []PathParam
+	Meta          EndpointMeta
+	Input         reflect.Type
+	Output        reflect.Type
+	SuccessStatus int
+	Envelope      bool
+}
+
+type describedEndpoint interface {
+	HookChain() HookChain
+	Describe() EndpointDescription
+}
+
+func (e *Engine) track(ep describedEndpoint) {
+	e.endpointsMu.Lock()
+	defer e.endpointsMu.Unlock()
+	e.endpoints = append(e.endpoints, ep)
+}
+
+// HookChains returns the hook chain of every endpoint declared on the engine,
+// in declaration order.
+func (e *Engine) HookChains() []HookChain {
+	e.endpointsMu.Lock()
+	endpoints := append([]describedEndpoint(nil), e.endpoints...)
+	e.endpointsMu.Unlock()
+	chains := make([]HookChain, 0, len(endpoints))
+	for _, ep := range endpoints {
+		chains = append(chains, ep.HookChain())
+	}
+	return chains
+}
+
+// Endpoints describes every endpoint declared on the engine, in declaration
+// order.
+func (e *Engine) Endpoints() []EndpointDescription {
+	e.endpointsMu.Lock()
+	endpoints := append([]describedEndpoint(nil), e.endpoints...)
+	e.endpointsMu.Unlock()
+	out := make([]EndpointDescription, 0, len(endpoints))
+	for _, ep := range endpoints {
+		out = append(out, ep.Describe())
+	}
+	return out
+}
+
+// ErrorCatalog returns the catalog used to render errors.
+func (e *Engine) ErrorCatalog() *frameworkerrors.ErrorCatalog {
+	if e.errorMapper != nil {
+		return e.errorMapper.Catalog()
+	}
+	return e.catalog
+}
+
+// Validator returns the validator applied after binding, or nil. With
+// WithValidators it holds the custom rules, so handlers can check standalone
+// values through TagValidator.ValidateValue.
+func (e *Engine) Validator() binder.Validator {
+	return e.validator
+}
+
+// Describe returns the static shape of this endpoint.
+func (d *DeclarativeEndpoint[TIn, TOut]) Describe() EndpointDescription {
+	status := d.successStatus
+	if status == 0 {
+		status = defaultSuccessStatus(d.Method)
+	}
+	return EndpointDescription{
+		Method:        d.Method,
+		Path:          d.Path,
+		PathParams:    append([]PathParam(nil), d.pathParams...),
+		Meta:          d.Meta,
+		Input:         reflect.TypeFor[TIn](),
+		Output:        reflect.TypeFor[TOut](),
+		SuccessStatus: status,
+		Envelope:      d.envelopeEnabled(),
+	}
+}
+
+// HookChain returns the effective hooks for this endpoint in execution order.
+func (d *DeclarativeEndpoint[TIn, TOut]) HookChain() HookChain {
+	inputHooks, outputHooks := d.resolvedHooks()
+	chain := HookChain{Method: d.Method, Path: d.Path}
+	for _, hook := range inputHooks {
+		if hook != nil {
+			chain.Input = append(chain.Input, hooks.Info(hook))
+		}
+	}
+	for _, hook := range outputHooks {
+		if hook != nil {
+			chain.Output = append(chain.Output, hooks.Info(hook))
+		}
+	}
+	return chain
+}
+
+// resolvedHooks merges engine and endpoint hooks and orders them by priority.
+func (d *DeclarativeEndpoint[TIn, TOut]) resolvedHooks() ([]hooks.InputHook, []hooks.OutputHook) {
+	inputHooks := append([]hooks.InputHook{}, d.engine.inputHooks...)
+	inputHooks = append(inputHooks, d.inputHooks...)
+	outputHooks := append([]hooks.OutputHook{}, d.engine.outputHooks...)
+	outputHooks = append(outputHooks, d.outputHooks...)
+	hooks.SortByPriority(inputHooks)
+	hooks.SortByPriority(outputHooks)
+	return inputHooks, outputHooks
+}
@@ -0,0 +1,229 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/hooks"
+	"github.com/aatuh/pureapi-framework/renderer/ndjson"
+	"github.com/aatuh/pureapi-framework/renderer/registry"
+)
+
+const (
+	// arrayFlushEvery is the number of items written between flushes.
+	arrayFlushEvery = 64
+	// arrayFlushInterval bounds how long written items wait for a flush
+	// when the producer is slow or blocked.
+	arrayFlushInterval = 100 * time.Millisecond
+)
+
+// idleFlusher flushes a stream once written items have waited
+// arrayFlushInterval, so items sent before a slow producer stalls still
+// reach the client. Writes and flushes happen with mu held because the
+// timer fires on its own goroutine.
+type idleFlusher struct {
+	mu     sync.Mutex
+	flush  func()
+	timer  *time.Timer
+	dirty  bool
+	closed bool
+}
+
+// wrote records unflushed output and arms the timer. mu must be held.
+func (f *idleFlusher) wrote() {
+	f.dirty = true
+	if f.timer == nil && !f.closed {
+		f.timer = time.AfterFunc(arrayFlushInterval, f.fire)
+	}
+}
+
+func (f *idleFlusher) fire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.timer = nil
+	if !f.closed {
+		f.flushNow()
+	}
+}
+
+// flushNow flushes pending output. mu must be held.
+func (f *idleFlusher) flushNow() {
+	if f.dirty {
+		f.dirty = false
+		f.flush()
+	}
+}
+
+// stop flushes pending output and disarms the timer before the handler
+// returns. mu must be held.
+func (f *idleFlusher) stop() {
+	f.flushNow()
+	f.closed = true
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+}
+
+// ItemYielder writes one element of a streamed JSON array.
+type ItemYielder func(item any) error
+
+// ArrayStreamer is implemented by handler outputs that stream a JSON array
+// item by item instead of rendering a buffered payload. Clients accepting
+// application/x-ndjson receive one item per line instead.
+type ArrayStreamer interface {
+	StreamArray(ctx context.Context, yield ItemYielder) error
+}
+
+// ArrayStreamFunc lifts a function into an ArrayStreamer.
+type ArrayStreamFunc func(ctx context.Context, yield ItemYielder) error
+
+// StreamArray implements ArrayStreamer.
+func (f ArrayStreamFunc) StreamArray(ctx context.Context, yield ItemYielder) error {
+	if f == nil {
+		return nil
+	}
+	return f(ctx, yield)
+}
+
+// StreamChannel streams items received from ch until it is closed.
+func StreamChannel[T any](ch <-chan T) ArrayStreamFunc {
+	return func(ctx context.Context, yield ItemYielder) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case item, ok := <-ch:
+				if !ok {
+					return nil
+				}
+				if err := yield(item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// StreamSeq streams items from seq, stopping at the first error it yields.
+// It suits iterators over database rows.
+func StreamSeq[T any](seq iter.Seq2[T, error]) ArrayStreamFunc {
+	return func(ctx context.Context, yield ItemYielder) error {
+		for item, err := range seq {
+			if err != nil {
+				return err
+			}
+			if err := yield(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// arrayFormat frames the items of a streamed array.
+type arrayFormat struct {
+	contentType string
+	open        string
+	separator   string
+	close       string
+}
+
+var (
+	// jsonArray streams a JSON array.
+	jsonArray = arrayFormat{contentType: "application/json", open: "[", separator: ",", close: "]"}
+	// ndjsonArray streams one JSON value per line.
+	ndjsonArray = arrayFormat{contentType: ndjson.ContentType, separator: "\n", close: "\n"}
+)
+
+// wantsNDJSON reports whether the client prefers newline-delimited JSON.
+func wantsNDJSON(renderRegistry *registry.Registry, r *http.Request) bool {
+	ct, _, err := renderRegistry.Negotiate(r.Header.Get("Accept"))
+	return err == nil && ct == ndjson.ContentType
+}
+
+// streamArray writes the streamer output in format, running output hooks
+// against each item. Items are flushed every arrayFlushEvery items, or once
+// they have waited arrayFlushInterval. An error after the opening bracket
+// leaves a JSON array unterminated so clients detect truncation. It reports
+// whether any bytes were written.
+func streamArray(
+	ctx context.Context,
+	w http.ResponseWriter,
+	status int,
+	streamer ArrayStreamer,
+	outputHooks []hooks.OutputHook,
+	format arrayFormat,
+) (bool, error) {
+	idle := &idleFlusher{flush: func() {}}
+	if flusher, ok := w.(http.Flusher); ok {
+		idle.flush = flusher.Flush
+	}
+	started := false
+	count := 0
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(status)
+		_, err := w.Write([]byte(format.open))
+		return err
+	}
+	yield := func(item any) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := processEventData(ctx, item, outputHooks)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("encode stream item: %w", err)
+		}
+		idle.mu.Lock()
+		defer idle.mu.Unlock()
+		if err := start(); err != nil {
+			return err
+		}
+		if count > 0 {
+			encoded = append([]byte(format.separator), encoded...)
+		}
+		if _, err := w.Write(encoded); err != nil {
+			return err
+		}
+		count++
+		idle.wrote()
+		if count%arrayFlushEvery == 0 {
+			idle.flushNow()
+		}
+		return nil
+	}
+
+	err := streamer.StreamArray(ctx, yield)
+	idle.mu.Lock()
+	defer idle.mu.Unlock()
+	defer idle.stop()
+	if err != nil {
+		return started, err
+	}
+	if err := start(); err != nil {
+		return true, err
+	}
+	if count > 0 || format.open != "" {
+		if _, err := w.Write([]byte(format.close)); err != nil {
+			return true, err
+		}
+	}
+	// Flush even an empty array so the status reaches the client now.
+	idle.dirty = true
+	return true, nil
+}
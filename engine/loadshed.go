@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"net/http"
+
+	"github.com/aatuh/pureapi-framework/middleware/loadshed"
+)
+
+// WithLoadShedding caps in-flight requests across all endpoints. Requests
+// beyond cfg.MaxInFlight queue up to cfg.MaxQueue deep for at most
+// cfg.QueueTimeout, and the rest render the "unavailable" (503) or
+// "too_many_requests" (429) catalog entry with a Retry-After header.
+func WithLoadShedding(cfg loadshed.Config) EngineOption {
+	return func(e *Engine) {
+		if cfg.Name == "" {
+			cfg.Name = "global"
+		}
+		e.loadShedder = loadshed.NewLimiter(cfg)
+	}
+}
+
+// WithEndpointLoadShedding caps in-flight requests for this endpoint, in
+// addition to any engine-wide limit. cfg.Name defaults to "METHOD path".
+func WithEndpointLoadShedding[TIn any, TOut any](cfg loadshed.Config) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		if cfg.Name == "" {
+			cfg.Name = ep.Method + " " + ep.Path
+		}
+		ep.loadShedder = loadshed.NewLimiter(cfg)
+	}
+}
+
+// acquireSlots takes the endpoint slot, then the engine-wide slot, and
+// returns the function releasing both. Queueing for the endpoint first keeps
+// a saturated endpoint from holding global slots that other endpoints need.
+func (d *DeclarativeEndpoint[TIn, TOut]) acquireSlots(r *http.Request) (func(), error) {
+	releaseEndpoint, err := d.loadShedder.Acquire(r)
+	if err != nil {
+		return nil, err
+	}
+	releaseGlobal, err := d.engine.loadShedder.Acquire(r)
+	if err != nil {
+		releaseEndpoint()
+		return nil, err
+	}
+	return func() {
+		releaseGlobal()
+		releaseEndpoint()
+	}, nil
+}
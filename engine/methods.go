@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+)
+
+// AutoMethods selects the methods the engine answers on its own for
+// registered paths.
+type AutoMethods struct {
+	// Head answers HEAD for every GET endpoint with the GET headers and
+	// Content-Length but no body.
+	Head bool
+	// Options answers OPTIONS with 204 and an Allow header listing the
+	// methods registered for the path.
+	Options bool
+}
+
+// WithAutoMethods overrides which methods Register answers automatically.
+// Both HEAD and OPTIONS are answered by default.
+func WithAutoMethods(auto AutoMethods) EngineOption {
+	return func(e *Engine) {
+		e.autoMethods = auto
+	}
+}
+
+// Registrar receives the endpoints produced by Engine.Register, e.g. a
+// pureapi-core server handler.
+type Registrar interface {
+	Register(endpoints []endpoint.Endpoint)
+}
+
+// ErrMethodNotAllowed is reported for requests whose method is not registered
+// for an existing path. It maps to the "method_not_allowed" catalog entry.
+var ErrMethodNotAllowed = errors.New("method not allowed")
+
+// standardMethods are answered with 405 when a path does not register them.
+var standardMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// Register converts specs and registers them on h together with the HEAD and
+// OPTIONS endpoints selected by WithAutoMethods, printing them first when
+// WithRouteDump is set. Endpoints with explicit HEAD
+// or OPTIONS declarations keep them. Other standard methods on a registered
+// path render the "method_not_allowed" catalog entry with status 405 and an
+// Allow header.
+func (e *Engine) Register(h Registrar, specs ...endpoint.EndpointSpec) {
+	if h == nil || len(specs) == 0 {
+		return
+	}
+	e.dumpRoutes(specs)
+	h.Register(e.completeEndpoints(endpoint.ToEndpoints(specs...)))
+}
+
+// routeGroup collects the endpoints registered for one path template.
+type routeGroup struct {
+	path    string
+	methods map[string]endpoint.Endpoint
+}
+
+// allow lists the methods of the group in the Allow header format.
+func (g *routeGroup) allow() string {
+	methods := make([]string, 0, len(g.methods))
+	for method := range g.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
+// completeEndpoints appends the automatic endpoints for each path template.
+func (e *Engine) completeEndpoints(endpoints []endpoint.Endpoint) []endpoint.Endpoint {
+	groups := groupRoutes(endpoints)
+	out := append([]endpoint.Endpoint{}, endpoints...)
+	for _, g := range groups {
+		// A HEAD pattern ending in a wildcard would conflict with more
+		// specific GET patterns; the mux already routes HEAD to GET there.
+		if _, ok := g.methods[http.MethodGet]; ok && strings.HasSuffix(g.path, "...}") {
+			if _, declared := g.methods[http.MethodHead]; !declared {
+				g.methods[http.MethodHead] = nil
+			}
+		}
+		if get, ok := g.methods[http.MethodGet]; ok && e.autoMethods.Head {
+			if _, declared := g.methods[http.MethodHead]; !declared {
+				// The GET chain already starts with the base middlewares,
+				// so HEAD runs them exactly once through it.
+				head := endpoint.NewEndpoint(get.Path(), http.MethodHead).WithHandler(headHandler(chainEndpoint(get)))
+				g.methods[http.MethodHead] = head
+				out = append(out, head)
+			}
+		}
+		if _, declared := g.methods[http.MethodOptions]; !declared && e.autoMethods.Options {
+			g.methods[http.MethodOptions] = nil
+			out = append(out, e.withBaseMiddlewares(endpoint.NewEndpoint(g.path, http.MethodOptions).WithHandler(optionsHandler(g))))
+		}
+		allow := g.allow()
+		for _, method := range standardMethods {
+			if _, declared := g.methods[method]; declared {
+				continue
+			}
+			out = append(out, e.withBaseMiddlewares(endpoint.NewEndpoint(g.path, method).WithHandler(e.methodNotAllowedHandler(allow))))
+		}
+	}
+	return out
+}
+
+// withBaseMiddlewares wraps an automatic endpoint in the engine-wide
+// middlewares, e.g. CORS, that declared endpoints start with.
+func (e *Engine) withBaseMiddlewares(ep endpoint.Endpoint) endpoint.Endpoint {
+	if mw := e.baseMiddlewares(); len(mw) > 0 {
+		return ep.WithMiddlewares(endpoint.NewMiddlewares(mw...))
+	}
+	return ep
+}
+
+func (e *Engine) methodNotAllowedHandler(allow string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		writeError(r.Context(), w, e.renderRegistry, r, e.errorMapper, ErrMethodNotAllowed)
+	}
+}
+
+// groupRoutes groups endpoints by path template, ignoring parameter names,
+// in registration order.
+func groupRoutes(endpoints []endpoint.Endpoint) []*routeGroup {
+	var groups []*routeGroup
+	index := map[string]*routeGroup{}
+	for _, ep := range endpoints {
+		key := routeKey(ep.Path())
+		g, ok := index[key]
+		if !ok {
+			g = &routeGroup{path: ep.Path(), methods: map[string]endpoint.Endpoint{}}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.methods[strings.ToUpper(ep.Method())] = ep
+	}
+	return groups
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// routeKey normalises a path template so /items/{id} and /items/{name} match.
+func routeKey(path string) string {
+	return pathParam.ReplaceAllStringFunc(path, func(param string) string {
+		if strings.HasSuffix(param, "...}") {
+			return "{...}"
+		}
+		return "{}"
+	})
+}
+
+func chainEndpoint(ep endpoint.Endpoint) http.Handler {
+	var h http.Handler = ep.Handler()
+	if mw := ep.Middlewares(); mw != nil {
+		h = mw.Chain(h)
+	}
+	return h
+}
+
+func optionsHandler(g *routeGroup) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", g.allow())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// headHandler serves HEAD through the GET handler, discarding the body and
+// reporting its length.
+func headHandler(get http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hw := &headResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		getReq := r.Clone(r.Context())
+		getReq.Method = http.MethodGet
+		get.ServeHTTP(hw, getReq)
+		if w.Header().Get("Content-Length") == "" && hw.status != http.StatusNoContent && hw.status != http.StatusNotModified {
+			w.Header().Set("Content-Length", strconv.Itoa(hw.size))
+		}
+		w.WriteHeader(hw.status)
+	}
+}
+
+// headResponseWriter records the status and counts body bytes without
+// writing them.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func (w *headResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+}
+
+func (w *headResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	w.size += len(p)
+	return len(p), nil
+}
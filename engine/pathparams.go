@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/aatuh/pureapi-core/server"
+	"github.com/aatuh/pureapi-framework/binder"
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
+	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
+)
+
+// PathParam describes a path parameter and its constraint.
+type PathParam struct {
+	Name string
+	// Type is "integer", "uuid", or "string".
+	Type string
+	// Pattern is the regular expression values must match, if any.
+	Pattern string
+
+	match *regexp.Regexp
+}
+
+// pathConstraints maps constraint names to their type and pattern.
+var pathConstraints = map[string]PathParam{
+	"int":   {Type: "integer", Pattern: `^-?[0-9]+$`},
+	"uuid":  {Type: "uuid", Pattern: `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`},
+	"alpha": {Type: "string", Pattern: `^[A-Za-z]+$`},
+}
+
+// WithPathConstraintBadRequest renders path constraint mismatches as
+// "invalid_request" with a field error instead of "not_found".
+func WithPathConstraintBadRequest() EngineOption {
+	return func(e *Engine) {
+		e.pathBadRequest = true
+	}
+}
+
+// parsePathTemplate strips constraints such as {id:int}, {id:uuid}, and
+// {slug:regexp([a-z-]+)} from path, returning the router path and the
+// parameters in order.
+func parsePathTemplate(path string) (string, []PathParam, error) {
+	var b strings.Builder
+	var params []PathParam
+	for i := 0; i < len(path); i++ {
+		if path[i] != '{' {
+			b.WriteByte(path[i])
+			continue
+		}
+		end, depth := -1, 0
+		for j := i; j < len(path); j++ {
+			switch path[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+			if depth == 0 {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			return "", nil, fmt.Errorf("unterminated parameter in %q", path)
+		}
+		name, constraint, constrained := strings.Cut(path[i+1:end], ":")
+		param := PathParam{Name: strings.TrimSuffix(name, "..."), Type: "string"}
+		if constrained {
+			resolved, err := resolveConstraint(constraint)
+			if err != nil {
+				return "", nil, fmt.Errorf("parameter %s in %q: %w", param.Name, path, err)
+			}
+			param.Type, param.Pattern, param.match = resolved.Type, resolved.Pattern, resolved.match
+		}
+		params = append(params, param)
+		b.WriteString("{" + name + "}")
+		i = end
+	}
+	return b.String(), params, nil
+}
+
+func resolveConstraint(constraint string) (PathParam, error) {
+	param, ok := pathConstraints[constraint]
+	if !ok {
+		inner, isRegexp := strings.CutPrefix(constraint, "regexp(")
+		if !isRegexp || !strings.HasSuffix(inner, ")") {
+			return PathParam{}, fmt.Errorf("unknown constraint %q", constraint)
+		}
+		param = PathParam{Type: "string", Pattern: "^(?:" + strings.TrimSuffix(inner, ")") + ")$"}
+	}
+	match, err := regexp.Compile(param.Pattern)
+	if err != nil {
+		return PathParam{}, err
+	}
+	param.match = match
+	return param, nil
+}
+
+// checkPathParams enforces the endpoint's constraints before binding.
+func (d *DeclarativeEndpoint[TIn, TOut]) checkPathParams(r *http.Request) error {
+	var fieldErrors []binder.FieldError
+	for _, param := range d.pathParams {
+		if param.match == nil {
+			continue
+		}
+		if value := pathValue(r, param.Name); !param.match.MatchString(value) {
+			if !d.engine.pathBadRequest {
+				return frameworkerrors.ErrNotFound
+			}
+			fieldErrors = append(fieldErrors, binder.NewFieldError(param.Name, binder.SourcePath, fmt.Sprintf("must match %s", param.Type)))
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return binder.NewBindError("Invalid path parameters", fieldErrors)
+	}
+	return nil
+}
+
+func pathValue(r *http.Request, name string) string {
+	if params := server.RouteParams(r); params != nil {
+		if value, ok := params[name]; ok {
+			return value
+		}
+	}
+	if params := frameworkcontext.PathParamsFromContext(r.Context()); params != nil {
+		if value, ok := params[name]; ok {
+			return value
+		}
+	}
+	return r.PathValue(name)
+}
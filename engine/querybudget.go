@@ -0,0 +1,27 @@
+package engine
+
+import (
+	"github.com/aatuh/pureapi-framework/middleware/querybudget"
+)
+
+// WithQueryBudget limits the database queries of every request. Data layers
+// account for queries with querybudget.Record; in reject mode an overrun
+// renders the "query_budget_exceeded" (500) catalog entry. cfg.Name defaults
+// to "global".
+func WithQueryBudget(cfg querybudget.Config) EngineOption {
+	if cfg.Name == "" {
+		cfg.Name = "global"
+	}
+	return WithGlobalMiddlewares(querybudget.Middleware(cfg))
+}
+
+// WithEndpointQueryBudget replaces the engine query budget for this endpoint;
+// a config without limits lifts it. cfg.Name defaults to "METHOD path".
+func WithEndpointQueryBudget[TIn any, TOut any](cfg querybudget.Config) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		if cfg.Name == "" {
+			cfg.Name = ep.Method + " " + ep.Path
+		}
+		WithEndpointMiddlewares[TIn, TOut](querybudget.Middleware(cfg))(ep)
+	}
+}
@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// ResponseController lets handlers and output hooks set headers, cookies,
+// and the success status of the response the engine writes for them.
+// Headers and cookies apply to every response, including errors; the status
+// replaces the endpoint's success status. Its methods are safe for
+// concurrent use and do nothing on a nil controller.
+type ResponseController struct {
+	mu      sync.Mutex
+	header  http.Header
+	status  int
+	cookies []*http.Cookie
+}
+
+type responseMetaKey struct{}
+
+// ResponseMeta returns the controller for the request being served by ctx.
+// Outside an engine endpoint it returns a detached controller whose changes
+// are discarded.
+func ResponseMeta(ctx context.Context) *ResponseController {
+	if ctx != nil {
+		if c, ok := ctx.Value(responseMetaKey{}).(*ResponseController); ok {
+			return c
+		}
+	}
+	return &ResponseController{}
+}
+
+func withResponseMeta(ctx context.Context, c *ResponseController) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, c)
+}
+
+// SetHeader replaces the values of key.
+func (c *ResponseController) SetHeader(key, value string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.header == nil {
+		c.header = http.Header{}
+	}
+	c.header.Set(key, value)
+}
+
+// AddHeader appends a value to key.
+func (c *ResponseController) AddHeader(key, value string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.header == nil {
+		c.header = http.Header{}
+	}
+	c.header.Add(key, value)
+}
+
+// DelHeader removes key from the headers set through the controller.
+func (c *ResponseController) DelHeader(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header.Del(key)
+}
+
+// Header returns a copy of the headers set so far.
+func (c *ResponseController) Header() http.Header {
+	if c == nil {
+		return http.Header{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.header.Clone()
+}
+
+// SetStatus overrides the success status. Zero restores the default.
+func (c *ResponseController) SetStatus(status int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
+// Status returns the overriding status, or 0.
+func (c *ResponseController) Status() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// AddCookie adds a Set-Cookie header.
+func (c *ResponseController) AddCookie(cookie *http.Cookie) {
+	if c == nil || cookie == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cookies = append(c.cookies, cookie)
+}
+
+// Cookies returns the cookies added so far.
+func (c *ResponseController) Cookies() []*http.Cookie {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*http.Cookie(nil), c.cookies...)
+}
+
+// statusOr returns the overriding status, or fallback.
+func (c *ResponseController) statusOr(fallback int) int {
+	if status := c.Status(); status != 0 {
+		return status
+	}
+	return fallback
+}
+
+// apply copies the headers and cookies onto h.
+func (c *ResponseController) apply(h http.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, values := range c.header {
+		h[key] = append([]string(nil), values...)
+	}
+	for _, cookie := range c.cookies {
+		if v := cookie.String(); v != "" {
+			h.Add("Set-Cookie", v)
+		}
+	}
+}
+
+// metaResponseWriter applies a ResponseController before the headers are
+// written.
+type metaResponseWriter struct {
+	http.ResponseWriter
+	meta        *ResponseController
+	wroteHeader bool
+}
+
+func (w *metaResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.meta.apply(w.ResponseWriter.Header())
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *metaResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher when the underlying writer supports it.
+func (w *metaResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *metaResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
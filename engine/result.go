@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"net/http"
+)
+
+// Result is returned by handlers whose response is a status and headers
+// without a body, such as redirects and 204 No Content.
+type Result struct {
+	status   int
+	location string
+}
+
+// Redirect returns a Result that redirects to location with status, which
+// should be a 3xx code such as http.StatusFound or
+// http.StatusPermanentRedirect. Other codes fall back to 302.
+func Redirect(status int, location string) Result {
+	if status < 300 || status > 399 {
+		status = http.StatusFound
+	}
+	return Result{status: status, location: location}
+}
+
+// NoContent returns a Result answering 204 No Content.
+func NoContent() Result {
+	return Result{status: http.StatusNoContent}
+}
+
+// Status returns the response status.
+func (r Result) Status() int {
+	if r.status == 0 {
+		return http.StatusNoContent
+	}
+	return r.status
+}
+
+// Location returns the redirect target, or "".
+func (r Result) Location() string {
+	return r.location
+}
+
+// resultOutput returns the Result held by output, if any.
+func resultOutput(output any) (Result, bool) {
+	switch v := output.(type) {
+	case Result:
+		return v, true
+	case *Result:
+		if v != nil {
+			return *v, true
+		}
+		return NoContent(), true
+	}
+	return Result{}, false
+}
+
+func writeResult(w http.ResponseWriter, result Result) {
+	if result.location != "" {
+		w.Header().Set("Location", result.location)
+	}
+	w.WriteHeader(result.Status())
+}
@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
+)
+
+// RouteInfo summarizes a declared endpoint for diagnostics.
+type RouteInfo struct {
+	Method        string `json:"method"`
+	Path          string `json:"path"`
+	SuccessStatus int    `json:"success_status"`
+	// Middlewares names the endpoint's middlewares, outermost first, e.g.
+	// "requestid.Middleware".
+	Middlewares []string `json:"middlewares,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	OperationID string   `json:"operation_id,omitempty"`
+	Version     string   `json:"version,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+}
+
+type routedEndpoint interface {
+	Route() RouteInfo
+}
+
+// Routes summarizes every endpoint declared on the engine, in declaration
+// order.
+func (e *Engine) Routes() []RouteInfo {
+	e.endpointsMu.Lock()
+	endpoints := append([]describedEndpoint(nil), e.endpoints...)
+	e.endpointsMu.Unlock()
+	out := make([]RouteInfo, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if routed, ok := ep.(routedEndpoint); ok {
+			out = append(out, routed.Route())
+		}
+	}
+	return out
+}
+
+// WriteRoutes prints the route table of the engine to w.
+func (e *Engine) WriteRoutes(w io.Writer) error {
+	return writeRoutes(w, e.Routes())
+}
+
+// WithRouteDump prints the routes passed to Register to w, e.g. os.Stderr
+// for a startup overview.
+func WithRouteDump(w io.Writer) EngineOption {
+	return func(e *Engine) {
+		e.routeDump = w
+	}
+}
+
+// dumpRoutes prints the declarative endpoints among specs to the route dump.
+func (e *Engine) dumpRoutes(specs []endpoint.EndpointSpec) {
+	if e.routeDump == nil {
+		return
+	}
+	var routes []RouteInfo
+	for _, spec := range specs {
+		if routed, ok := spec.(routedEndpoint); ok {
+			routes = append(routes, routed.Route())
+		}
+	}
+	_ = writeRoutes(e.routeDump, routes)
+}
+
+func writeRoutes(w io.Writer, routes []RouteInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tSTATUS\tSUMMARY\tMIDDLEWARES")
+	for _, route := range routes {
+		summary := route.Summary
+		if route.Deprecated {
+			summary = strings.TrimSpace("(deprecated) " + summary)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", route.Method, route.Path, route.SuccessStatus, summary, strings.Join(route.Middlewares, ", "))
+	}
+	return tw.Flush()
+}
+
+// RoutesEndpointConfig controls Engine.RoutesEndpoint.
+type RoutesEndpointConfig struct {
+	// Path serves the route table. Defaults to "/debug/routes".
+	Path string
+	// Allow admits requests, e.g. operators by token, or AllowLoopback.
+	// When nil every request is refused.
+	Allow func(r *http.Request) bool
+}
+
+// RoutesEndpoint returns a GET endpoint serving Engine.Routes as JSON. The
+// table reveals the API surface, so requests not admitted by cfg.Allow, and
+// all requests when it is nil, render the "not_found" catalog entry.
+func (e *Engine) RoutesEndpoint(cfg RoutesEndpointConfig) endpoint.EndpointSpec {
+	return &routesRoute{engine: e, cfg: cfg}
+}
+
+type routesRoute struct {
+	engine *Engine
+	cfg    RoutesEndpointConfig
+}
+
+// ToEndpoint implements endpoint.EndpointSpec.
+func (s *routesRoute) ToEndpoint() endpoint.Endpoint {
+	cfg := s.cfg
+	if cfg.Path == "" {
+		cfg.Path = "/debug/routes"
+	}
+	e := s.engine
+	ep := endpoint.NewEndpoint(cfg.Path, http.MethodGet).WithHandler(func(w http.ResponseWriter, r *http.Request) {
+		if cfg.Allow == nil || !cfg.Allow(r) {
+			writeError(r.Context(), w, e.renderRegistry, r, e.errorMapper, frameworkerrors.ErrNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(struct {
+			Routes []RouteInfo `json:"routes"`
+		}{Routes: e.Routes()})
+	})
+	return e.withBaseMiddlewares(ep)
+}
+
+// AllowLoopback admits requests whose peer address is a loopback address
+// and that carry no Forwarded, X-Forwarded-For, or X-Real-IP header. A
+// reverse proxy on the same host connects from loopback on behalf of remote
+// clients, so behind one that does not add those headers, such as a plain
+// TCP proxy, use a token check instead.
+func AllowLoopback(r *http.Request) bool {
+	for _, header := range []string{"Forwarded", "X-Forwarded-For", "X-Real-IP"} {
+		if r.Header.Get(header) != "" {
+			return false
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Route summarizes this endpoint for diagnostics.
+func (d *DeclarativeEndpoint[TIn, TOut]) Route() RouteInfo {
+	desc := d.Describe()
+	route := RouteInfo{
+		Method:        desc.Method,
+		Path:          desc.Path,
+		SuccessStatus: desc.SuccessStatus,
+		Summary:       desc.Meta.Summary,
+		OperationID:   desc.Meta.OperationID,
+		Version:       desc.Meta.Version,
+		Deprecated:    desc.Meta.Deprecated,
+	}
+	for _, mw := range d.resolvedMiddlewares() {
+		route.Middlewares = append(route.Middlewares, middlewareName(mw))
+	}
+	return route
+}
+
+// middlewareName derives a readable name such as "cache.Middleware" from the
+// function that built mw.
+func middlewareName(mw endpoint.Middleware) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(mw).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.ReplaceAll(name, "[...]", "")
+	// Strip closure suffixes such as ".func1", ".func1.2", or ".gowrap1".
+	for {
+		i := strings.LastIndex(name, ".")
+		if i < 0 || !isClosureSuffix(name[i+1:]) {
+			return name
+		}
+		name = name[:i]
+	}
+}
+
+func isClosureSuffix(s string) bool {
+	for _, prefix := range []string{"func", "gowrap", ""} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok && rest != "" {
+			if _, err := strconv.Atoi(rest); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package engine
+
+import (
+	"github.com/aatuh/pureapi-framework/binder"
+	"github.com/aatuh/pureapi-framework/binder/schema"
+)
+
+// WithEndpointBodySchema validates JSON request bodies against s before they
+// are decoded; violations render invalid_request with JSON Pointer fields.
+// It applies when the endpoint binds with a DefaultBinder.
+func WithEndpointBodySchema[TIn any, TOut any](s binder.BodySchema) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		ep.bodySchema = s
+	}
+}
+
+// WithEndpointGeneratedBodySchema is WithEndpointBodySchema with a schema
+// generated from the body field of TIn. It panics if TIn has no body field.
+func WithEndpointGeneratedBodySchema[TIn any, TOut any]() EndpointOption[TIn, TOut] {
+	return WithEndpointBodySchema[TIn, TOut](schema.MustForBody[TIn]())
+}
+
+func withBodySchema(b binder.Binder, s binder.BodySchema) binder.Binder {
+	if db, ok := b.(*binder.DefaultBinder); ok && s != nil {
+		return db.WithBodySchema(s)
+	}
+	return b
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"net/http"
+
+	"github.com/aatuh/pureapi-framework/middleware/maintenance"
+	"github.com/aatuh/pureapi-framework/settings"
+)
+
+// WithRuntimeSettings snapshots store at the start of every request so
+// middlewares, hooks, and handlers read consistent values through
+// settings.FromContext while the store is reloaded underneath them.
+func WithRuntimeSettings(store *settings.Store) EngineOption {
+	return func(e *Engine) {
+		e.runtimeSettings = store
+	}
+}
+
+// RuntimeSettings returns the store configured with WithRuntimeSettings, or nil.
+func (e *Engine) RuntimeSettings() *settings.Store {
+	return e.runtimeSettings
+}
+
+// WithMaintenance configures maintenance mode. While it is active, endpoints
+// outside cfg's exempt paths and allow-list render the "unavailable" catalog
+// entry with status 503 and a Retry-After header. Engines with runtime
+// settings enter maintenance mode whenever the snapshot enables it, even
+// without this option.
+func WithMaintenance(cfg maintenance.Config) EngineOption {
+	return func(e *Engine) {
+		e.maintenance = &cfg
+	}
+}
+
+func (e *Engine) checkMaintenance(r *http.Request) error {
+	cfg := maintenance.Config{}
+	if e.maintenance != nil {
+		cfg = *e.maintenance
+	} else if e.runtimeSettings == nil {
+		return nil
+	}
+	if cfg.Settings == nil {
+		cfg.Settings = e.runtimeSettings
+	}
+	return cfg.Check(r)
+}
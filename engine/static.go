@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
+)
+
+// StaticConfig controls Engine.Static.
+type StaticConfig struct {
+	// Prefix is the URL path the files are served under. Defaults to "/".
+	Prefix string
+	// FS holds the files, e.g. an embed.FS or os.DirFS.
+	FS fs.FS
+	// Dir serves files from disk when FS is nil.
+	Dir string
+	// Index is served for directory requests and as the SPA fallback.
+	// Defaults to "index.html".
+	Index string
+	// SPA serves Index for paths under Prefix that match no file, so a
+	// client-side router can handle them.
+	SPA bool
+	// CacheControl is sent with files. Defaults to "public, max-age=3600".
+	CacheControl string
+	// IndexCacheControl is sent with Index, which usually references
+	// fingerprinted assets and must be revalidated. Defaults to "no-cache".
+	IndexCacheControl string
+}
+
+// Static returns a GET endpoint serving cfg's files under cfg.Prefix with
+// cache headers, Last-Modified, and range request support. API endpoints on
+// more specific paths, including those under the prefix, take precedence.
+// Unknown files render the "not_found" catalog entry unless cfg.SPA is set.
+func (e *Engine) Static(cfg StaticConfig) endpoint.EndpointSpec {
+	return &staticRoute{engine: e, cfg: cfg}
+}
+
+type staticRoute struct {
+	engine *Engine
+	cfg    StaticConfig
+}
+
+// ToEndpoint implements endpoint.EndpointSpec.
+func (s *staticRoute) ToEndpoint() endpoint.Endpoint {
+	cfg := s.cfg
+	prefix := "/" + strings.Trim(cfg.Prefix, "/")
+	if prefix != "/" {
+		prefix += "/"
+	}
+	files := cfg.FS
+	if files == nil {
+		files = os.DirFS(cfg.Dir)
+	}
+	if cfg.Index == "" {
+		cfg.Index = "index.html"
+	}
+	if cfg.CacheControl == "" {
+		cfg.CacheControl = "public, max-age=3600"
+	}
+	if cfg.IndexCacheControl == "" {
+		cfg.IndexCacheControl = "no-cache"
+	}
+	e := s.engine
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		writeError(r.Context(), w, e.renderRegistry, r, e.errorMapper, frameworkerrors.ErrNotFound)
+	}
+
+	ep := endpoint.NewEndpoint(prefix+"{path...}", http.MethodGet).WithHandler(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		if name == "" {
+			name = "."
+		}
+		name = path.Clean(name)
+		if !fs.ValidPath(name) {
+			notFound(w, r)
+			return
+		}
+		if serveFile(w, r, files, name, cfg) {
+			return
+		}
+		if cfg.SPA && serveFile(w, r, files, cfg.Index, cfg) {
+			return
+		}
+		notFound(w, r)
+	})
+	if mw := e.baseMiddlewares(); len(mw) > 0 {
+		ep = ep.WithMiddlewares(endpoint.NewMiddlewares(mw...))
+	}
+	return ep
+}
+
+// serveFile serves name, or the index of the directory name, and reports
+// whether it existed.
+func serveFile(w http.ResponseWriter, r *http.Request, files fs.FS, name string, cfg StaticConfig) bool {
+	info, err := fs.Stat(files, name)
+	if err == nil && info.IsDir() {
+		name = path.Join(name, cfg.Index)
+		info, err = fs.Stat(files, name)
+	}
+	if err != nil || info.IsDir() {
+		return false
+	}
+	f, err := files.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return false
+		}
+		content = bytes.NewReader(data)
+	}
+	if path.Base(name) == cfg.Index {
+		w.Header().Set("Cache-Control", cfg.IndexCacheControl)
+	} else {
+		w.Header().Set("Cache-Control", cfg.CacheControl)
+	}
+	http.ServeContent(w, r, name, info.ModTime(), content)
+	return true
+}
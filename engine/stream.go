@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
+	"github.com/aatuh/pureapi-framework/hooks"
+)
+
+// SSEEvent is a single server-sent event.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  any
+	Retry time.Duration
+}
+
+// EventSender writes an event to the client and flushes it immediately.
+type EventSender func(event SSEEvent) error
+
+// Streamer is implemented by handler outputs that stream server-sent events
+// instead of rendering a single payload.
+type Streamer interface {
+	Stream(ctx context.Context, send EventSender) error
+}
+
+// StreamFunc lifts a function into a Streamer.
+type StreamFunc func(ctx context.Context, send EventSender) error
+
+// Stream implements Streamer.
+func (f StreamFunc) Stream(ctx context.Context, send EventSender) error {
+	if f == nil {
+		return nil
+	}
+	return f(ctx, send)
+}
+
+// streamsOutput reports whether TOut is streamed or written directly rather
+// than rendered, so the Accept header is not negotiated up front.
+func streamsOutput[TOut any]() bool {
+	var zero TOut
+	for _, v := range []any{zero, &zero} {
+		switch v.(type) {
+		case Streamer, ArrayStreamer, FileResult, *FileResult, Result, *Result:
+			return true
+		}
+	}
+	return false
+}
+
+// streamEvents writes the streamer output as text/event-stream, running output
+// hooks against each event payload. It reports whether any bytes were written.
+func streamEvents(
+	ctx context.Context,
+	w http.ResponseWriter,
+	status int,
+	streamer Streamer,
+	outputHooks []hooks.OutputHook,
+	mapper *frameworkerrors.ErrorMapper,
+	requestID string,
+) (bool, error) {
+	flusher, _ := w.(http.Flusher)
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		header := w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		header.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(status)
+	}
+	send := func(event SSEEvent) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := processEventData(ctx, event.Data, outputHooks)
+		if err != nil {
+			return err
+		}
+		event.Data = data
+		frame, err := encodeSSEEvent(event)
+		if err != nil {
+			return err
+		}
+		start()
+		if _, err := w.Write(frame); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	err := streamer.Stream(ctx, send)
+	if err == nil {
+		start()
+		return true, nil
+	}
+	if !started || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return started, err
+	}
+	payload := frameworkerrors.RenderError(mapper.Map(err))
+	if requestID != "" {
+		payload = payload.WithOrigin(requestID)
+	}
+	if frame, encErr := encodeSSEEvent(SSEEvent{Event: "error", Data: payload}); encErr == nil {
+		_, _ = w.Write(frame)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	return true, err
+}
+
+// processEventData runs typed output hooks against a copy of the event data.
+func processEventData(ctx context.Context, data any, outputHooks []hooks.OutputHook) (any, error) {
+	if data == nil || len(outputHooks) == 0 {
+		return data, nil
+	}
+	ptr := reflect.New(reflect.TypeOf(data))
+	ptr.Elem().Set(reflect.ValueOf(data))
+	if err := executeOutputHooks(ctx, ptr.Interface(), outputHooks); err != nil {
+		return nil, err
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+func encodeSSEEvent(event SSEEvent) ([]byte, error) {
+	var buf bytes.Buffer
+	if event.ID != "" {
+		buf.WriteString("id: " + sanitizeSSELine(event.ID) + "\n")
+	}
+	if event.Event != "" {
+		buf.WriteString("event: " + sanitizeSSELine(event.Event) + "\n")
+	}
+	if event.Retry > 0 {
+		buf.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+	var text string
+	switch v := event.Data.(type) {
+	case nil:
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("encode sse data: %w", err)
+		}
+		text = string(data)
+	}
+	for _, line := range strings.Split(text, "\n") {
+		buf.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+func sanitizeSSELine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
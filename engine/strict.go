@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"github.com/aatuh/pureapi-framework/binder"
+)
+
+// WithStrictParams makes the engine's DefaultBinder reject query parameters
+// and headers that the endpoint input does not declare.
+func WithStrictParams(strict binder.StrictParams) EngineOption {
+	return func(e *Engine) {
+		e.strict = &strict
+	}
+}
+
+// WithEndpointStrictParams overrides the strict parameter checks for this
+// endpoint. It applies when the endpoint binds with a DefaultBinder.
+func WithEndpointStrictParams[TIn any, TOut any](strict binder.StrictParams) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		ep.strict = &strict
+	}
+}
+
+// withStrictParams applies the endpoint's strict settings and allows the
+// query parameters the engine itself reads for the endpoint.
+func withStrictParams(b binder.Binder, strict *binder.StrictParams, fields *fieldSelection) binder.Binder {
+	db, ok := b.(*binder.DefaultBinder)
+	if !ok || strict == nil && fields == nil {
+		return b
+	}
+	resolved := db.Strict
+	if strict != nil {
+		resolved = *strict
+	}
+	if resolved.Query && fields != nil {
+		resolved.AllowedQuery = append(append([]string{}, resolved.AllowedQuery...), fields.param)
+	}
+	return db.WithStrictParams(resolved)
+}
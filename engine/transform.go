@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"github.com/aatuh/pureapi-framework/transform"
+)
+
+// WithTransforms rewrites the output of every endpoint after output hooks and
+// before field selection, envelopes, and rendering.
+func WithTransforms(rules ...transform.Rule) EngineOption {
+	return func(e *Engine) {
+		e.transforms = append(e.transforms, rules...)
+	}
+}
+
+// WithGroupTransforms rewrites the output of every endpoint in the group, for
+// example to keep an old API version's field names on a shared handler.
+func WithGroupTransforms(rules ...transform.Rule) GroupOption {
+	return func(g *Group) {
+		g.transforms = append(g.transforms, rules...)
+	}
+}
+
+// WithEndpointTransforms rewrites this endpoint's output.
+func WithEndpointTransforms[TIn any, TOut any](rules ...transform.Rule) EndpointOption[TIn, TOut] {
+	return func(ep *DeclarativeEndpoint[TIn, TOut]) {
+		ep.transforms = append(ep.transforms, rules...)
+	}
+}
+
+// resolvedTransforms orders engine rules before group and endpoint rules.
+func (d *DeclarativeEndpoint[TIn, TOut]) resolvedTransforms() transform.Rules {
+	rules := append(transform.Rules{}, d.engine.transforms...)
+	for _, g := range d.group.chain() {
+		rules = append(rules, g.transforms...)
+	}
+	return append(rules, d.transforms...)
+}
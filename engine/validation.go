@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aatuh/pureapi-framework/binder"
+	"github.com/aatuh/pureapi-framework/hooks"
+)
+
+// withValidationErrors stores a fresh collector for one request in ctx.
+func withValidationErrors(ctx context.Context) context.Context {
+	return binder.WithValidationErrors(ctx, binder.NewValidationErrors())
+}
+
+// bindInput binds dest and runs the input hooks, collecting the field
+// failures of both into one error. Hooks still run after binding reports
+// field failures so every problem is rendered at once; a hook failing for
+// another reason then stops the hooks without masking those failures.
+func bindInput(ctx context.Context, b binder.Binder, r *http.Request, dest any, inputHooks []hooks.InputHook) error {
+	violations := binder.ValidationErrorsFromContext(ctx)
+	if b != nil {
+		if err := b.Bind(ctx, r, dest); err != nil && !violations.AddError(err) {
+			return err
+		}
+	}
+	for _, hook := range inputHooks {
+		if hook == nil {
+			continue
+		}
+		if err := hook.Process(ctx, dest); err != nil && !violations.AddError(err) {
+			if violations.Len() > 0 {
+				break
+			}
+			return err
+		}
+	}
+	return violations.Err()
+}
+
+// reportedValidation folds field failures the handler reported with
+// binder.ReportFieldError into its result.
+func reportedValidation(ctx context.Context, err error) error {
+	violations := binder.ValidationErrorsFromContext(ctx)
+	if violations.Len() == 0 {
+		return err
+	}
+	if err == nil || violations.AddError(err) {
+		return violations.Err()
+	}
+	return err
+}
@@ -0,0 +1,328 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+)
+
+// ErrUnsupportedVersion is reported when a request asks for a version that
+// does not serve the route. It maps to the "not_found" catalog entry.
+var ErrUnsupportedVersion = errors.New("unsupported API version")
+
+// VersionStrategy selects how clients ask for an API version.
+type VersionStrategy int
+
+const (
+	// VersionByPath prefixes routes with the version name, e.g. /v1/items.
+	VersionByPath VersionStrategy = iota
+	// VersionByHeader reads the version from a request header.
+	VersionByHeader
+	// VersionByMediaType reads the version from the Accept header, either as a
+	// vendor type (application/vnd.acme.v2+json) or a version parameter
+	// (application/json;version=2).
+	VersionByMediaType
+)
+
+// DefaultVersionHeader carries the version for VersionByHeader.
+const DefaultVersionHeader = "API-Version"
+
+// VersioningConfig controls a VersionSet.
+type VersioningConfig struct {
+	Strategy VersionStrategy
+	// Header carries the version for VersionByHeader. Defaults to API-Version.
+	Header string
+	// Default serves requests that name no version when versions are chosen
+	// by header or media type. Defaults to the latest declared version.
+	Default string
+}
+
+// Version describes one API version.
+type Version struct {
+	// Name identifies the version, e.g. "v1". Requests may omit the "v".
+	Name string
+	// Deprecated adds a Deprecation header to every response of the version,
+	// dated by DeprecatedAt when set.
+	Deprecated   bool
+	DeprecatedAt time.Time
+	// Sunset announces when the version stops being served.
+	Sunset time.Time
+	// Link points to migration notes and is sent with rel="deprecation".
+	Link string
+}
+
+// VersionSet declares the same routes under several API versions. Create one
+// with Engine.Versions, declare endpoints in the groups returned by Version,
+// and register the result of Endpoints.
+type VersionSet struct {
+	engine *Engine
+	cfg    VersioningConfig
+
+	mu        sync.Mutex
+	versions  []Version
+	endpoints []versionedEndpoint
+}
+
+type versionedEndpoint struct {
+	version string
+	method  string
+	path    string
+	spec    endpoint.EndpointSpec
+}
+
+// versionBinding ties a group to its version.
+type versionBinding struct {
+	set     *VersionSet
+	version Version
+}
+
+type versionContextKey struct{}
+
+// Versions creates a set of API versions on the engine.
+func (e *Engine) Versions(cfg VersioningConfig) *VersionSet {
+	if cfg.Header == "" {
+		cfg.Header = DefaultVersionHeader
+	}
+	return &VersionSet{engine: e, cfg: cfg}
+}
+
+// Version declares v and returns the group its endpoints are declared in.
+// With VersionByPath the group is rooted at "/" + v.Name. Responses of
+// deprecated versions carry Deprecation, Sunset, and Link headers, and every
+// handler can read the served version with VersionFromContext.
+func (vs *VersionSet) Version(v Version, opts ...GroupOption) *Group {
+	vs.mu.Lock()
+	vs.versions = append(vs.versions, v)
+	vs.mu.Unlock()
+	prefix := ""
+	if vs.cfg.Strategy == VersionByPath {
+		prefix = v.Name
+	}
+	opts = append([]GroupOption{WithGroupMiddlewares(versionMiddleware(v))}, opts...)
+	g := vs.engine.Group(prefix, opts...)
+	g.version = &versionBinding{set: vs, version: v}
+	return g
+}
+
+// Endpoints returns the specs to register for every versioned endpoint. With
+// VersionByPath each endpoint is returned as declared; otherwise endpoints
+// sharing a method and path are merged into one route that dispatches on the
+// requested version.
+func (vs *VersionSet) Endpoints() []endpoint.EndpointSpec {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if vs.cfg.Strategy == VersionByPath {
+		out := make([]endpoint.EndpointSpec, 0, len(vs.endpoints))
+		for _, ep := range vs.endpoints {
+			out = append(out, ep.spec)
+		}
+		return out
+	}
+	var out []endpoint.EndpointSpec
+	routes := map[string]*versionRoute{}
+	for _, ep := range vs.endpoints {
+		key := ep.method + " " + ep.path
+		route, ok := routes[key]
+		if !ok {
+			route = &versionRoute{set: vs, method: ep.method, path: ep.path, specs: map[string]endpoint.EndpointSpec{}}
+			routes[key] = route
+			out = append(out, route)
+		}
+		route.specs[normalizeVersion(ep.version)] = ep.spec
+	}
+	return out
+}
+
+func (vs *VersionSet) add(version string, spec endpoint.EndpointSpec, method, path string) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	vs.endpoints = append(vs.endpoints, versionedEndpoint{version: version, method: method, path: path, spec: spec})
+}
+
+// defaultVersion returns the configured default or the latest version.
+func (vs *VersionSet) defaultVersion() string {
+	if vs.cfg.Default != "" {
+		return vs.cfg.Default
+	}
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if len(vs.versions) == 0 {
+		return ""
+	}
+	return vs.versions[len(vs.versions)-1].Name
+}
+
+// requested extracts the version a request asks for, rewriting vendor media
+// types in Accept to their base type so content negotiation still succeeds.
+func (vs *VersionSet) requested(r *http.Request) (string, *http.Request) {
+	switch vs.cfg.Strategy {
+	case VersionByHeader:
+		return strings.TrimSpace(r.Header.Get(vs.cfg.Header)), r
+	case VersionByMediaType:
+		version, accept := mediaTypeVersion(r.Header.Get("Accept"))
+		if version != "" {
+			r = r.Clone(r.Context())
+			r.Header.Set("Accept", accept)
+		}
+		return version, r
+	}
+	return "", r
+}
+
+// versionRoute dispatches one method and path to the endpoint of the
+// requested version.
+type versionRoute struct {
+	set    *VersionSet
+	method string
+	path   string
+	specs  map[string]endpoint.EndpointSpec
+}
+
+// ToEndpoint implements endpoint.EndpointSpec.
+func (v *versionRoute) ToEndpoint() endpoint.Endpoint {
+	handlers := make(map[string]http.Handler, len(v.specs))
+	for version, spec := range v.specs {
+		ep := spec.ToEndpoint()
+		var h http.Handler = ep.Handler()
+		if mw := ep.Middlewares(); mw != nil {
+			h = mw.Chain(h)
+		}
+		handlers[version] = h
+	}
+	vary := "Accept"
+	if v.set.cfg.Strategy == VersionByHeader {
+		vary = v.set.cfg.Header
+	}
+	engine := v.set.engine
+	// The version endpoints carry their own middlewares; the error for an
+	// unknown version still needs the request ID other errors carry.
+	var unsupported http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(r.Context(), w, engine.renderRegistry, r, engine.errorMapper, ErrUnsupportedVersion)
+	})
+	if engine.requestIDMiddleware != nil {
+		unsupported = engine.requestIDMiddleware(unsupported)
+	}
+	return endpoint.NewEndpoint(v.path, v.method).WithHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", vary)
+		version, r := v.set.requested(r)
+		if version == "" {
+			version = v.set.defaultVersion()
+		}
+		h, ok := handlers[normalizeVersion(version)]
+		if !ok {
+			unsupported.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// VersionFromContext returns the API version serving the request, or "".
+func VersionFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	version, _ := ctx.Value(versionContextKey{}).(string)
+	return version
+}
+
+// AdaptHandler reuses handler for a version whose wire types differ from the
+// shared ones: in converts the version's input and out converts the result.
+func AdaptHandler[VIn any, VOut any, TIn any, TOut any](
+	handler HandlerFunc[TIn, TOut],
+	in func(ctx context.Context, input VIn) (TIn, error),
+	out func(ctx context.Context, output TOut) (VOut, error),
+) HandlerFunc[VIn, VOut] {
+	return func(ctx context.Context, input VIn) (VOut, error) {
+		var zero VOut
+		shared, err := in(ctx, input)
+		if err != nil {
+			return zero, err
+		}
+		result, err := handler(ctx, shared)
+		if err != nil {
+			return zero, err
+		}
+		return out(ctx, result)
+	}
+}
+
+// versionMiddleware records the version in the context and advertises
+// deprecation.
+func versionMiddleware(v Version) endpoint.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if v.Deprecated {
+				if v.DeprecatedAt.IsZero() {
+					w.Header().Set("Deprecation", "true")
+				} else {
+					w.Header().Set("Deprecation", "@"+strconv.FormatInt(v.DeprecatedAt.Unix(), 10))
+				}
+				if v.Link != "" {
+					w.Header().Add("Link", "<"+v.Link+`>; rel="deprecation"`)
+				}
+			}
+			if !v.Sunset.IsZero() {
+				w.Header().Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+			}
+			ctx := context.WithValue(r.Context(), versionContextKey{}, v.Name)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// vendorVersion matches the version segment of a vendor type such as
+// application/vnd.acme.v2+json.
+var vendorVersion = regexp.MustCompile(`^v?\d+$`)
+
+// mediaTypeVersion finds a version in an Accept header and returns the
+// header with versioned vendor types replaced by their base type. Vendor
+// types without a version segment, such as application/vnd.acme+json, are
+// left untouched.
+func mediaTypeVersion(accept string) (string, string) {
+	var version string
+	ranges := strings.Split(accept, ",")
+	for i, raw := range ranges {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(raw))
+		if err != nil {
+			continue
+		}
+		if v := params["version"]; v != "" {
+			if version == "" {
+				version = v
+			}
+			delete(params, "version")
+			ranges[i] = mime.FormatMediaType(mediaType, params)
+			continue
+		}
+		typ, subtype, _ := strings.Cut(mediaType, "/")
+		vendor, suffix, hasSuffix := strings.Cut(subtype, "+")
+		if !strings.HasPrefix(vendor, "vnd.") {
+			continue
+		}
+		dot := strings.LastIndexByte(vendor, '.')
+		if dot <= len("vnd") || !vendorVersion.MatchString(vendor[dot+1:]) {
+			continue
+		}
+		if version == "" {
+			version = vendor[dot+1:]
+		}
+		if !hasSuffix {
+			suffix = "json"
+		}
+		ranges[i] = mime.FormatMediaType(typ+"/"+suffix, params)
+	}
+	return version, strings.Join(ranges, ",")
+}
+
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+}
@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// validID matches dot-separated namespaced IDs such as "users.not_found".
+var validID = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+// EntryOption sets optional metadata on an entry added by CatalogBuilder.
+type EntryOption func(*CatalogEntry)
+
+// WithDocsURL links the entry to its documentation.
+func WithDocsURL(url string) EntryOption {
+	return func(e *CatalogEntry) { e.DocsURL = url }
+}
+
+// Retryable marks the entry as safe to retry.
+func Retryable() EntryOption {
+	return func(e *CatalogEntry) { e.Retryable = true }
+}
+
+// CatalogBuilder declares catalog entries fluently and reports every invalid
+// or duplicate ID when built, so a subsystem's catalog fails at startup
+// rather than when the error is first rendered.
+type CatalogBuilder struct {
+	state     *builderState
+	namespace string
+}
+
+type builderState struct {
+	entries []CatalogEntry
+	errs    []error
+}
+
+// NewCatalogBuilder returns an empty builder.
+func NewCatalogBuilder() *CatalogBuilder {
+	return &CatalogBuilder{state: &builderState{}}
+}
+
+// Namespace returns a builder sharing b's entries that prefixes IDs with ns,
+// e.g. Namespace("users").Add("not_found", ...) declares "users.not_found".
+func (b *CatalogBuilder) Namespace(ns string) *CatalogBuilder {
+	return &CatalogBuilder{state: b.state, namespace: b.qualify(ns)}
+}
+
+// Add declares an entry with the given ID relative to the builder namespace.
+func (b *CatalogBuilder) Add(id string, status int, message string, opts ...EntryOption) *CatalogBuilder {
+	entry := CatalogEntry{ID: id, Status: status, Message: message}
+	for _, opt := range opts {
+		opt(&entry)
+	}
+	return b.Entry(entry)
+}
+
+// Entry declares entry with its ID relative to the builder namespace.
+func (b *CatalogBuilder) Entry(entry CatalogEntry) *CatalogBuilder {
+	entry.ID = b.qualify(entry.ID)
+	if !validID.MatchString(entry.ID) {
+		b.state.errs = append(b.state.errs, fmt.Errorf("catalog entry id %q must be lower-case segments separated by dots", entry.ID))
+		return b
+	}
+	b.state.entries = append(b.state.entries, entry)
+	return b
+}
+
+// Include declares every entry of catalog with its ID unchanged.
+func (b *CatalogBuilder) Include(catalog *ErrorCatalog) *CatalogBuilder {
+	if catalog != nil {
+		b.state.entries = append(b.state.entries, catalog.Entries()...)
+	}
+	return b
+}
+
+// Build returns the catalog, or an error listing every invalid entry and
+// duplicate ID.
+func (b *CatalogBuilder) Build() (*ErrorCatalog, error) {
+	catalog := &ErrorCatalog{entries: make(map[string]CatalogEntry, len(b.state.entries))}
+	errs := append([]error(nil), b.state.errs...)
+	for _, entry := range b.state.entries {
+		if err := catalog.Register(entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return catalog, nil
+}
+
+// MustBuild is like Build but panics on error. It suits package-level
+// catalog variables.
+func (b *CatalogBuilder) MustBuild() *ErrorCatalog {
+	catalog, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+func (b *CatalogBuilder) qualify(id string) string {
+	if b.namespace == "" {
+		return id
+	}
+	return b.namespace + "." + id
+}
+
+// MergeCatalogs combines catalogs into a new catalog. An ID may appear in
+// several catalogs only with identical entries.
+func MergeCatalogs(catalogs ...*ErrorCatalog) (*ErrorCatalog, error) {
+	merged := &ErrorCatalog{entries: map[string]CatalogEntry{}}
+	for _, catalog := range catalogs {
+		if err := merged.Merge(catalog); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
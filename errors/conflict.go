@@ -0,0 +1,45 @@
+package errors
+
+import "errors"
+
+// ErrConflict signals that the request conflicts with the current state of
+// the resource, e.g. an update that affected no row. The engine maps it to
+// the "conflict" catalog entry.
+var ErrConflict = errors.New("resource conflict")
+
+// conflictError carries a wire message and entity while matching
+// ErrConflict.
+type conflictError struct {
+	message string
+	entity  string
+}
+
+func (e conflictError) Error() string {
+	if e.message != "" {
+		return e.message
+	}
+	return ErrConflict.Error()
+}
+
+func (e conflictError) CatalogID() string   { return "conflict" }
+func (e conflictError) WireMessage() string { return e.message }
+func (e conflictError) Unwrap() error       { return ErrConflict }
+
+// WireData reports the conflicting entity, if known.
+func (e conflictError) WireData() any {
+	if e.entity == "" {
+		return nil
+	}
+	return map[string]string{"entity": e.entity}
+}
+
+// Conflict returns an error mapped to "conflict" with a custom wire message.
+func Conflict(message string) error {
+	return conflictError{message: message}
+}
+
+// EntityConflict returns an error mapped to "conflict" naming entity in the
+// message and in the error data.
+func EntityConflict(entity string) error {
+	return conflictError{message: entity + " conflicts with its current state", entity: entity}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/aatuh/pureapi-core/apierror"
@@ -15,6 +16,10 @@ type CatalogEntry struct {
 	ID      string
 	Status  int
 	Message string
+	// DocsURL points to documentation describing the error.
+	DocsURL string
+	// Retryable reports that clients may retry the request unchanged.
+	Retryable bool
 }
 
 // ErrorCatalog keeps registered catalog entries keyed by ID.
@@ -41,6 +46,16 @@ func DefaultErrorCatalog() *ErrorCatalog {
 		CatalogEntry{ID: "invalid_request", Status: http.StatusBadRequest, Message: "Request validation failed"},
 		CatalogEntry{ID: "unauthorized", Status: http.StatusUnauthorized, Message: "Unauthorized"},
 		CatalogEntry{ID: "forbidden", Status: http.StatusForbidden, Message: "Forbidden"},
+		CatalogEntry{ID: "not_found", Status: http.StatusNotFound, Message: "Resource not found"},
+		CatalogEntry{ID: "method_not_allowed", Status: http.StatusMethodNotAllowed, Message: "Method not allowed"},
+		CatalogEntry{ID: "not_acceptable", Status: http.StatusNotAcceptable, Message: "No acceptable representation available"},
+		CatalogEntry{ID: "conflict", Status: http.StatusConflict, Message: "Resource conflict"},
+		CatalogEntry{ID: "payload_too_large", Status: http.StatusRequestEntityTooLarge, Message: "Request body too large"},
+		CatalogEntry{ID: "precondition_failed", Status: http.StatusPreconditionFailed, Message: "Precondition failed"},
+		CatalogEntry{ID: "too_many_requests", Status: http.StatusTooManyRequests, Message: "Too many requests"},
+		CatalogEntry{ID: "timeout", Status: http.StatusGatewayTimeout, Message: "Request timed out"},
+		CatalogEntry{ID: "unavailable", Status: http.StatusServiceUnavailable, Message: "Service temporarily unavailable"},
+		CatalogEntry{ID: "query_budget_exceeded", Status: http.StatusInternalServerError, Message: "Request exceeded its query budget"},
 	)
 	return catalog
 }
@@ -66,6 +81,31 @@ func (c *ErrorCatalog) Register(entry CatalogEntry) error {
 	return nil
 }
 
+// Merge registers every entry of other. Entries identical to registered ones
+// are skipped; conflicting entries with the same ID fail the whole merge.
+func (c *ErrorCatalog) Merge(other *ErrorCatalog) error {
+	if other == nil || other == c {
+		return nil
+	}
+	entries := other.Entries()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var add []CatalogEntry
+	for _, entry := range entries {
+		if existing, exists := c.entries[entry.ID]; exists {
+			if existing != entry {
+				return fmt.Errorf("catalog entry with id %s already registered with different settings", entry.ID)
+			}
+			continue
+		}
+		add = append(add, entry)
+	}
+	for _, entry := range add {
+		c.entries[entry.ID] = entry
+	}
+	return nil
+}
+
 // Lookup returns the catalog entry for id.
 func (c *ErrorCatalog) Lookup(id string) (CatalogEntry, bool) {
 	c.mu.RLock()
@@ -74,6 +114,18 @@ func (c *ErrorCatalog) Lookup(id string) (CatalogEntry, bool) {
 	return entry, ok
 }
 
+// Entries returns the registered entries ordered by ID.
+func (c *ErrorCatalog) Entries() []CatalogEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]CatalogEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		out = append(out, entry)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
 // Clone produces a shallow copy of the catalog.
 func (c *ErrorCatalog) Clone() *ErrorCatalog {
 	c.mu.RLock()
@@ -95,14 +147,36 @@ type wireData interface {
 	WireData() any
 }
 
-type typeRegistration struct {
-	typ     reflect.Type
-	entryID string
+// MappingOption configures a mapper registration.
+type MappingOption func(*registration)
+
+// WithPriority orders the registration before those with a lower priority.
+// Registrations default to priority 0; ties keep errors.Is targets before
+// predicates before types, each in registration order.
+func WithPriority(priority int) MappingOption {
+	return func(r *registration) { r.priority = priority }
 }
 
-type isRegistration struct {
-	target  error
-	entryID string
+// WithWireData extracts the payload attached to matched errors that do not
+// provide WireData themselves.
+func WithWireData(fn func(err error) any) MappingOption {
+	return func(r *registration) { r.data = fn }
+}
+
+// Registration kinds, in their default precedence.
+const (
+	kindIs = iota
+	kindFunc
+	kindType
+)
+
+type registration struct {
+	match    func(err error) bool
+	entryID  string
+	priority int
+	kind     int
+	seq      int
+	data     func(err error) any
 }
 
 // ErrorMapper maps Go errors to catalog entries.
@@ -110,9 +184,8 @@ type ErrorMapper struct {
 	catalog   *ErrorCatalog
 	defaultID string
 
-	mu       sync.RWMutex
-	typeRegs []typeRegistration
-	isRegs   []isRegistration
+	mu   sync.RWMutex
+	regs []registration
 }
 
 // NewErrorMapper creates a mapper backed by catalog. defaultID must exist.
@@ -130,34 +203,62 @@ func NewErrorMapper(catalog *ErrorCatalog, defaultID string) (*ErrorMapper, erro
 }
 
 // RegisterType registers errors matched via errors.As against a catalog entry.
-func (m *ErrorMapper) RegisterType(prototype any, entryID string) error {
+func (m *ErrorMapper) RegisterType(prototype any, entryID string, opts ...MappingOption) error {
 	if prototype == nil {
 		return fmt.Errorf("prototype must not be nil")
 	}
-	if err := m.ensureEntry(entryID); err != nil {
-		return err
-	}
 	typ := reflect.TypeOf(prototype)
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.typeRegs = append(m.typeRegs, typeRegistration{typ: typ, entryID: entryID})
-	return nil
+	return m.register(registration{match: func(err error) bool { return matchesAs(err, typ) }, entryID: entryID, kind: kindType}, opts)
 }
 
-// RegisterIs registers a sentinel error matched using errors.Is.
-func (m *ErrorMapper) RegisterIs(target error, entryID string) error {
+// RegisterIs registers a sentinel error matched using errors.Is, e.g.
+// sql.ErrNoRows, so call sites need not wrap it.
+func (m *ErrorMapper) RegisterIs(target error, entryID string, opts ...MappingOption) error {
 	if target == nil {
 		return fmt.Errorf("target must not be nil")
 	}
-	if err := m.ensureEntry(entryID); err != nil {
+	return m.register(registration{match: func(err error) bool { return errors.Is(err, target) }, entryID: entryID, kind: kindIs}, opts)
+}
+
+// RegisterFunc registers errors for which match reports true.
+func (m *ErrorMapper) RegisterFunc(match func(err error) bool, entryID string, opts ...MappingOption) error {
+	if match == nil {
+		return fmt.Errorf("match must not be nil")
+	}
+	return m.register(registration{match: match, entryID: entryID, kind: kindFunc}, opts)
+}
+
+func (m *ErrorMapper) register(reg registration, opts []MappingOption) error {
+	if err := m.ensureEntry(reg.entryID); err != nil {
 		return err
 	}
+	for _, opt := range opts {
+		opt(&reg)
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.isRegs = append(m.isRegs, isRegistration{target: target, entryID: entryID})
+	// Copy on write so Map can iterate a snapshot without holding the lock.
+	reg.seq = len(m.regs)
+	regs := append(append(make([]registration, 0, len(m.regs)+1), m.regs...), reg)
+	sort.SliceStable(regs, func(i, j int) bool {
+		a, b := regs[i], regs[j]
+		if a.priority != b.priority {
+			return a.priority > b.priority
+		}
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		return a.seq < b.seq
+	})
+	m.regs = regs
 	return nil
 }
 
+// Catalog returns the catalog backing the mapper.
+func (m *ErrorMapper) Catalog() *ErrorCatalog {
+	return m.catalog
+}
+
 // ensureEntry checks that an entry exists in catalog.
 func (m *ErrorMapper) ensureEntry(entryID string) error {
 	if entryID == "" {
@@ -189,7 +290,7 @@ func (m *ErrorMapper) Map(err error) MappedError {
 		return MappedError{Entry: entry, Message: entry.Message}
 	}
 
-	entry := m.matchEntry(err)
+	entry, reg := m.matchEntry(err)
 	msg := entry.Message
 	if wm, ok := err.(wireMessage); ok {
 		if custom := wm.WireMessage(); custom != "" {
@@ -199,59 +300,34 @@ func (m *ErrorMapper) Map(err error) MappedError {
 	var data any
 	if wd, ok := err.(wireData); ok {
 		data = wd.WireData()
+	} else if reg != nil && reg.data != nil {
+		data = reg.data(err)
 	}
 	return MappedError{Entry: entry, Message: msg, Data: data, Cause: err}
 }
 
-func (m *ErrorMapper) matchEntry(err error) CatalogEntry {
-	if err == nil {
-		entry, _ := m.catalog.Lookup(m.defaultID)
-		return entry
-	}
-
+// matchEntry returns the entry for err and the registration that matched it,
+// if any.
+func (m *ErrorMapper) matchEntry(err error) (CatalogEntry, *registration) {
 	if ce, ok := err.(CatalogError); ok {
 		if entry, ok := m.catalog.Lookup(ce.CatalogID()); ok {
-			return entry
+			return entry, nil
 		}
 	}
 
-	if entry, ok := m.matchIs(err); ok {
-		return entry
-	}
-	if entry, ok := m.matchType(err); ok {
-		return entry
-	}
-
-	entry, _ := m.catalog.Lookup(m.defaultID)
-	return entry
-}
-
-func (m *ErrorMapper) matchIs(err error) (CatalogEntry, bool) {
 	m.mu.RLock()
-	regs := append([]isRegistration(nil), m.isRegs...)
+	regs := m.regs
 	m.mu.RUnlock()
-	for _, reg := range regs {
-		if errors.Is(err, reg.target) {
-			if entry, ok := m.catalog.Lookup(reg.entryID); ok {
-				return entry, true
+	for i := range regs {
+		if regs[i].match(err) {
+			if entry, ok := m.catalog.Lookup(regs[i].entryID); ok {
+				return entry, &regs[i]
 			}
 		}
 	}
-	return CatalogEntry{}, false
-}
 
-func (m *ErrorMapper) matchType(err error) (CatalogEntry, bool) {
-	m.mu.RLock()
-	regs := append([]typeRegistration(nil), m.typeRegs...)
-	m.mu.RUnlock()
-	for _, reg := range regs {
-		if matchesAs(err, reg.typ) {
-			if entry, ok := m.catalog.Lookup(reg.entryID); ok {
-				return entry, true
-			}
-		}
-	}
-	return CatalogEntry{}, false
+	entry, _ := m.catalog.Lookup(m.defaultID)
+	return entry, nil
 }
 
 func matchesAs(err error, typ reflect.Type) bool {
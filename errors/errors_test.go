@@ -2,6 +2,8 @@ package errors_test
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
@@ -78,3 +80,158 @@ func TestRenderError(t *testing.T) {
 		t.Fatalf("unexpected payload data: %#v", payload.Data())
 	}
 }
+
+func TestDefaultCatalogMapsNotFound(t *testing.T) {
+	mapper, err := frameworkerrors.NewErrorMapper(frameworkerrors.DefaultErrorCatalog(), "internal_error")
+	if err != nil {
+		t.Fatalf("failed to build mapper: %v", err)
+	}
+	notFound := frameworkerrors.NotFound("user 7 not found")
+	if !errors.Is(notFound, frameworkerrors.ErrNotFound) {
+		t.Fatalf("expected NotFound to match ErrNotFound")
+	}
+	mapped := mapper.Map(notFound)
+	if mapped.Entry.Status != 404 || mapped.Message != "user 7 not found" {
+		t.Fatalf("unexpected mapping: %+v", mapped)
+	}
+}
+
+func TestCatalogBuilderNamespacesAndMetadata(t *testing.T) {
+	users := frameworkerrors.NewCatalogBuilder().Namespace("users")
+	users.Add("not_found", 404, "User not found", frameworkerrors.WithDocsURL("https://docs.example.com/errors/users-not-found"))
+	users.Namespace("quota").Add("exceeded", 429, "", frameworkerrors.Retryable())
+	catalog, err := users.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry, ok := catalog.Lookup("users.not_found")
+	if !ok || entry.DocsURL == "" || entry.Retryable {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	entry, ok = catalog.Lookup("users.quota.exceeded")
+	if !ok || !entry.Retryable || entry.Message != "Too Many Requests" {
+		t.Fatalf("unexpected nested entry: %+v", entry)
+	}
+}
+
+func TestCatalogBuilderReportsEveryProblem(t *testing.T) {
+	_, err := frameworkerrors.NewCatalogBuilder().
+		Add("auth.denied", 403, "Denied").
+		Add("auth.denied", 401, "Denied again").
+		Add("Auth Bad", 400, "Bad").
+		Add("auth.teapot", 200, "Not an error").
+		Build()
+	if err == nil {
+		t.Fatalf("expected build error")
+	}
+	for _, want := range []string{"auth.denied already registered", `"Auth Bad"`, "auth.teapot has invalid HTTP status"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected MustBuild to panic")
+		}
+	}()
+	frameworkerrors.NewCatalogBuilder().Add("", 500, "").MustBuild()
+}
+
+func TestMergeCatalogs(t *testing.T) {
+	auth := frameworkerrors.NewCatalogBuilder().Namespace("auth").Add("denied", 403, "Denied").
+		Include(frameworkerrors.DefaultErrorCatalog()).MustBuild()
+	crud := frameworkerrors.NewCatalogBuilder().Namespace("crud").Add("conflict", 409, "Conflict").
+		Include(frameworkerrors.DefaultErrorCatalog()).MustBuild()
+
+	merged, err := frameworkerrors.MergeCatalogs(frameworkerrors.DefaultErrorCatalog(), auth, crud)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, id := range []string{"internal_error", "auth.denied", "crud.conflict"} {
+		if _, ok := merged.Lookup(id); !ok {
+			t.Fatalf("expected %s in merged catalog", id)
+		}
+	}
+
+	conflicting := frameworkerrors.NewCatalogBuilder().Add("auth.denied", 401, "Denied").MustBuild()
+	if _, err := frameworkerrors.MergeCatalogs(auth, conflicting); err == nil {
+		t.Fatalf("expected conflicting IDs to fail")
+	}
+}
+
+type quotaError struct {
+	limit int
+}
+
+func (e quotaError) Error() string { return "quota exceeded" }
+
+func TestErrorMapper_PredicatesPriorityAndWireData(t *testing.T) {
+	catalog, _ := frameworkerrors.NewErrorCatalog(
+		frameworkerrors.CatalogEntry{ID: "internal_error", Status: 500},
+		frameworkerrors.CatalogEntry{ID: "not_found", Status: 404},
+		frameworkerrors.CatalogEntry{ID: "quota", Status: 429},
+		frameworkerrors.CatalogEntry{ID: "unavailable", Status: 503},
+	)
+	mapper, _ := frameworkerrors.NewErrorMapper(catalog, "internal_error")
+	errNoRows := errors.New("no rows in result set")
+	if err := mapper.RegisterIs(errNoRows, "not_found"); err != nil {
+		t.Fatalf("register is: %v", err)
+	}
+	if err := mapper.RegisterType(quotaError{}, "quota", frameworkerrors.WithWireData(func(err error) any {
+		var qe quotaError
+		errors.As(err, &qe)
+		return map[string]int{"limit": qe.limit}
+	})); err != nil {
+		t.Fatalf("register type: %v", err)
+	}
+	if err := mapper.RegisterFunc(func(err error) bool {
+		return strings.Contains(err.Error(), "connection refused")
+	}, "unavailable"); err != nil {
+		t.Fatalf("register func: %v", err)
+	}
+
+	if mapped := mapper.Map(fmt.Errorf("load user: %w", errNoRows)); mapped.Entry.ID != "not_found" {
+		t.Fatalf("expected wrapped sentinel mapped, got %s", mapped.Entry.ID)
+	}
+	if mapped := mapper.Map(errors.New("dial: connection refused")); mapped.Entry.ID != "unavailable" {
+		t.Fatalf("expected predicate match, got %s", mapped.Entry.ID)
+	}
+	mapped := mapper.Map(fmt.Errorf("charge: %w", quotaError{limit: 10}))
+	if data, ok := mapped.Data.(map[string]int); mapped.Entry.ID != "quota" || !ok || data["limit"] != 10 {
+		t.Fatalf("expected quota with wire data, got %+v", mapped)
+	}
+
+	// An error matching both the predicate and the type resolves by
+	// registration kind until a priority is given.
+	both := fmt.Errorf("connection refused: %w", quotaError{limit: 1})
+	if mapped := mapper.Map(both); mapped.Entry.ID != "unavailable" {
+		t.Fatalf("expected predicate before type by default, got %s", mapped.Entry.ID)
+	}
+	_ = mapper.RegisterType(quotaError{}, "quota", frameworkerrors.WithPriority(10))
+	if mapped := mapper.Map(both); mapped.Entry.ID != "quota" {
+		t.Fatalf("expected higher priority to win, got %s", mapped.Entry.ID)
+	}
+	if err := mapper.RegisterFunc(nil, "quota"); err == nil {
+		t.Fatalf("expected nil predicate rejected")
+	}
+}
+
+func TestEntityErrorsCarryEntityData(t *testing.T) {
+	mapper, _ := frameworkerrors.NewErrorMapper(frameworkerrors.DefaultErrorCatalog(), "internal_error")
+	mapped := mapper.Map(frameworkerrors.EntityNotFound("user"))
+	if data, ok := mapped.Data.(map[string]string); mapped.Entry.Status != 404 || !ok || data["entity"] != "user" {
+		t.Fatalf("unexpected not found mapping: %+v", mapped)
+	}
+	conflict := frameworkerrors.EntityConflict("order")
+	if !errors.Is(conflict, frameworkerrors.ErrConflict) {
+		t.Fatalf("expected EntityConflict to match ErrConflict")
+	}
+	mapped = mapper.Map(conflict)
+	if data, ok := mapped.Data.(map[string]string); mapped.Entry.Status != 409 || !ok || data["entity"] != "order" {
+		t.Fatalf("unexpected conflict mapping: %+v", mapped)
+	}
+	if mapped := mapper.Map(frameworkerrors.NotFound("gone")); mapped.Data != nil {
+		t.Fatalf("expected no data without entity, got %v", mapped.Data)
+	}
+}
@@ -0,0 +1,43 @@
+package errors
+
+import "errors"
+
+// ErrNotFound signals that the requested resource does not exist. The engine
+// maps it to the "not_found" catalog entry.
+var ErrNotFound = errors.New("resource not found")
+
+// notFoundError carries a wire message while matching ErrNotFound.
+type notFoundError struct {
+	message string
+	entity  string
+}
+
+func (e notFoundError) Error() string {
+	if e.message != "" {
+		return e.message
+	}
+	return ErrNotFound.Error()
+}
+
+func (e notFoundError) CatalogID() string   { return "not_found" }
+func (e notFoundError) WireMessage() string { return e.message }
+func (e notFoundError) Unwrap() error       { return ErrNotFound }
+
+// WireData reports the missing entity, if known.
+func (e notFoundError) WireData() any {
+	if e.entity == "" {
+		return nil
+	}
+	return map[string]string{"entity": e.entity}
+}
+
+// NotFound returns an error mapped to "not_found" with a custom wire message.
+func NotFound(message string) error {
+	return notFoundError{message: message}
+}
+
+// EntityNotFound returns an error mapped to "not_found" naming entity in the
+// message and in the error data, e.g. for a lookup that matched no row.
+func EntityNotFound(entity string) error {
+	return notFoundError{message: entity + " not found", entity: entity}
+}
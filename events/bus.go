@@ -0,0 +1,225 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBusClosed is returned when publishing to a closed bus.
+var ErrBusClosed = errors.New("event bus closed")
+
+// ErrQueueFull is returned when the async queue cannot accept an event.
+var ErrQueueFull = errors.New("event queue full")
+
+// Option configures a Bus.
+type Option func(*Bus)
+
+// WithBufferSize sets the async queue capacity. Defaults to 256.
+func WithBufferSize(size int) Option {
+	return func(b *Bus) {
+		if size > 0 {
+			b.bufferSize = size
+		}
+	}
+}
+
+// WithWorkers sets the number of async delivery goroutines. Defaults to 1.
+func WithWorkers(workers int) Option {
+	return func(b *Bus) {
+		if workers > 0 {
+			b.workers = workers
+		}
+	}
+}
+
+// WithRetry retries failed async deliveries up to attempts times in total,
+// doubling backoff after each failure.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(b *Bus) {
+		if attempts > 0 {
+			b.attempts = attempts
+		}
+		if backoff >= 0 {
+			b.backoff = backoff
+		}
+	}
+}
+
+// WithErrorHandler receives async deliveries that exhausted their retries.
+func WithErrorHandler(fn func(event Event, err error)) Option {
+	return func(b *Bus) {
+		b.onError = fn
+	}
+}
+
+type subscription struct {
+	id      uint64
+	kind    Kind
+	handler Handler
+	async   bool
+}
+
+type delivery struct {
+	ctx     context.Context
+	event   Event
+	handler Handler
+}
+
+// Bus delivers events to subscribers. Synchronous subscribers run inside
+// Publish; asynchronous subscribers run on a buffered worker pool.
+type Bus struct {
+	mu         sync.RWMutex
+	subs       []subscription
+	nextID     uint64
+	bufferSize int
+	workers    int
+	attempts   int
+	backoff    time.Duration
+	onError    func(Event, error)
+
+	startOnce sync.Once
+	queue     chan delivery
+	wg        sync.WaitGroup
+	closed    bool
+}
+
+// NewBus creates an event bus.
+func NewBus(opts ...Option) *Bus {
+	b := &Bus{bufferSize: 256, workers: 1, attempts: 1, backoff: 100 * time.Millisecond}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Subscribe registers a synchronous handler for kind; an empty kind matches
+// every event. It returns a function that removes the subscription.
+func (b *Bus) Subscribe(kind Kind, handler Handler) func() {
+	return b.subscribe(kind, handler, false)
+}
+
+// SubscribeAsync registers a handler delivered on the worker pool.
+func (b *Bus) SubscribeAsync(kind Kind, handler Handler) func() {
+	b.startOnce.Do(b.start)
+	return b.subscribe(kind, handler, true)
+}
+
+func (b *Bus) subscribe(kind Kind, handler Handler, async bool) func() {
+	if handler == nil {
+		return func() {}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.subs = append(b.subs, subscription{id: id, kind: kind, handler: handler, async: async})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, sub := range b.subs {
+			if sub.id == id {
+				b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish delivers event. Synchronous handler errors are joined and
+// returned; async deliveries are queued without blocking and fail with
+// ErrQueueFull when the buffer is exhausted.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBusClosed
+	}
+	subs := append([]subscription(nil), b.subs...)
+	var errs []error
+	for _, sub := range subs {
+		if !sub.async || (sub.kind != "" && sub.kind != event.Kind) {
+			continue
+		}
+		select {
+		case b.queue <- delivery{ctx: context.WithoutCancel(ctx), event: event, handler: sub.handler}:
+		default:
+			errs = append(errs, ErrQueueFull)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.async || (sub.kind != "" && sub.kind != event.Kind) {
+			continue
+		}
+		if err := sub.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s handler: %w", event.Kind, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops accepting events and waits for queued deliveries to finish or
+// ctx to expire.
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	if b.queue != nil {
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bus) start() {
+	b.mu.Lock()
+	b.queue = make(chan delivery, b.bufferSize)
+	b.mu.Unlock()
+	for i := 0; i < b.workers; i++ {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			for d := range b.queue {
+				b.deliver(d)
+			}
+		}()
+	}
+}
+
+func (b *Bus) deliver(d delivery) {
+	backoff := b.backoff
+	var err error
+	for attempt := 1; attempt <= b.attempts; attempt++ {
+		if err = d.handler(d.ctx, d.event); err == nil {
+			return
+		}
+		if attempt < b.attempts && backoff > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	if b.onError != nil {
+		b.onError(d.event, err)
+	}
+}
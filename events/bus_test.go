@@ -0,0 +1,105 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/events"
+)
+
+func TestBusDeliversSynchronouslyByKind(t *testing.T) {
+	bus := events.NewBus()
+	var created, all []events.Event
+	bus.Subscribe(events.KindCreated, func(_ context.Context, e events.Event) error {
+		created = append(created, e)
+		return nil
+	})
+	unsubscribe := bus.Subscribe("", func(_ context.Context, e events.Event) error {
+		all = append(all, e)
+		return nil
+	})
+
+	_ = bus.Publish(context.Background(), events.Created("user", 1, map[string]string{"name": "a"}))
+	_ = bus.Publish(context.Background(), events.Updated("user", 1, "a", "b"))
+	unsubscribe()
+	_ = bus.Publish(context.Background(), events.Deleted("user", 1, "b"))
+
+	if len(created) != 1 || created[0].After == nil {
+		t.Fatalf("expected one created event with snapshot, got %+v", created)
+	}
+	if len(all) != 2 || all[1].Before != "a" || all[1].After != "b" {
+		t.Fatalf("unexpected wildcard deliveries %+v", all)
+	}
+}
+
+func TestBusJoinsSyncErrors(t *testing.T) {
+	bus := events.NewBus()
+	boom := errors.New("boom")
+	bus.Subscribe("", func(context.Context, events.Event) error { return boom })
+	if err := bus.Publish(context.Background(), events.Created("user", 1, nil)); !errors.Is(err, boom) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+}
+
+func TestBusRetriesAsyncDeliveries(t *testing.T) {
+	var failed atomic.Value
+	bus := events.NewBus(
+		events.WithRetry(3, time.Millisecond),
+		events.WithErrorHandler(func(e events.Event, err error) { failed.Store(err) }),
+	)
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var got []events.Event
+	bus.SubscribeAsync(events.KindCreated, func(_ context.Context, e events.Event) error {
+		if calls.Add(1) < 3 {
+			return errors.New("transient")
+		}
+		mu.Lock()
+		got = append(got, e)
+		mu.Unlock()
+		return nil
+	})
+
+	if err := bus.Publish(context.Background(), events.Created("user", 1, nil)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if err := bus.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if calls.Load() != 3 || len(got) != 1 {
+		t.Fatalf("expected delivery on third attempt, calls=%d got=%d", calls.Load(), len(got))
+	}
+	if failed.Load() != nil {
+		t.Fatalf("unexpected exhausted delivery: %v", failed.Load())
+	}
+	if err := bus.Publish(context.Background(), events.Created("user", 2, nil)); !errors.Is(err, events.ErrBusClosed) {
+		t.Fatalf("expected ErrBusClosed, got %v", err)
+	}
+}
+
+func TestPendingPublishesAfterCommit(t *testing.T) {
+	bus := events.NewBus()
+	var count int
+	bus.Subscribe("", func(context.Context, events.Event) error {
+		count++
+		return nil
+	})
+
+	var pending events.Pending
+	pending.Add(events.Created("user", 1, nil))
+	pending.Discard()
+	pending.Add(events.Created("user", 2, nil))
+	if count != 0 {
+		t.Fatalf("events published before commit")
+	}
+	if err := pending.Publish(context.Background(), bus); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 delivery, got %d", count)
+	}
+}
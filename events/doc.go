@@ -0,0 +1,2 @@
+// Package events publishes domain events to synchronous and asynchronous subscribers.
+package events
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// Kind classifies a domain event.
+type Kind string
+
+const (
+	// KindCreated is emitted after an entity is created.
+	KindCreated Kind = "entity.created"
+	// KindUpdated is emitted after an entity is updated.
+	KindUpdated Kind = "entity.updated"
+	// KindDeleted is emitted after an entity is deleted.
+	KindDeleted Kind = "entity.deleted"
+)
+
+// Event is a domain event with optional before/after snapshots.
+type Event struct {
+	Kind     Kind
+	Entity   string
+	ID       any
+	Before   any
+	After    any
+	Time     time.Time
+	Metadata map[string]string
+}
+
+// Created builds a KindCreated event.
+func Created(entity string, id, after any) Event {
+	return Event{Kind: KindCreated, Entity: entity, ID: id, After: after, Time: time.Now()}
+}
+
+// Updated builds a KindUpdated event.
+func Updated(entity string, id, before, after any) Event {
+	return Event{Kind: KindUpdated, Entity: entity, ID: id, Before: before, After: after, Time: time.Now()}
+}
+
+// Deleted builds a KindDeleted event.
+func Deleted(entity string, id, before any) Event {
+	return Event{Kind: KindDeleted, Entity: entity, ID: id, Before: before, Time: time.Now()}
+}
+
+// Handler reacts to an event.
+type Handler func(ctx context.Context, event Event) error
@@ -0,0 +1,2 @@
+// Package outbox stores domain events alongside mutations and relays them to a publisher with at-least-once delivery.
+package outbox
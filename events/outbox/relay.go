@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/events"
+)
+
+// Publisher delivers outbox messages to a broker such as Kafka or NATS.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// PublisherFunc lifts a function into a Publisher.
+type PublisherFunc func(ctx context.Context, msg Message) error
+
+// Publish implements Publisher.
+func (f PublisherFunc) Publish(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// Record serializes events and appends them to store. Call it with the
+// transaction-bound store so events commit with the mutation.
+func Record(ctx context.Context, store Store, evts ...events.Event) error {
+	messages := make([]Message, 0, len(evts))
+	for _, event := range evts {
+		msg, err := FromEvent(event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, msg)
+	}
+	return store.Append(ctx, messages...)
+}
+
+// Config controls the Relay.
+type Config struct {
+	Store     Store
+	Publisher Publisher
+	// Interval between polls when the outbox is drained. Defaults to one second.
+	Interval time.Duration
+	// BatchSize bounds messages fetched per poll. Defaults to 100.
+	BatchSize int
+	// OnError receives store and publish failures.
+	OnError func(err error)
+}
+
+// Relay polls the outbox and publishes pending messages. Messages are marked
+// sent only after a successful publish, so delivery is at-least-once and
+// consumers must be idempotent.
+type Relay struct {
+	cfg Config
+}
+
+// NewRelay creates a Relay.
+func NewRelay(cfg Config) (*Relay, error) {
+	if cfg.Store == nil {
+		return nil, errors.New("outbox store must not be nil")
+	}
+	if cfg.Publisher == nil {
+		return nil, errors.New("outbox publisher must not be nil")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &Relay{cfg: cfg}, nil
+}
+
+// Poll publishes one batch and returns the number of messages delivered.
+func (r *Relay) Poll(ctx context.Context) (int, error) {
+	messages, err := r.cfg.Store.Pending(ctx, r.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	sent := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		if err := r.cfg.Publisher.Publish(ctx, msg); err != nil {
+			errs = append(errs, err)
+			if markErr := r.cfg.Store.MarkFailed(ctx, msg.ID, err); markErr != nil {
+				errs = append(errs, markErr)
+			}
+			continue
+		}
+		sent = append(sent, msg.ID)
+	}
+	if len(sent) > 0 {
+		if err := r.cfg.Store.MarkSent(ctx, sent...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(sent), errors.Join(errs...)
+}
+
+// Run polls until ctx is cancelled. Full batches are followed immediately by
+// another poll; otherwise the relay waits Interval.
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		n, err := r.Poll(ctx)
+		if err != nil && r.cfg.OnError != nil {
+			r.cfg.OnError(err)
+		}
+		if n >= r.cfg.BatchSize && err == nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.cfg.Interval):
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/events"
+	"github.com/aatuh/pureapi-framework/events/outbox"
+)
+
+func TestRelayDeliversAtLeastOnce(t *testing.T) {
+	store := outbox.NewMemoryStore()
+	ctx := context.Background()
+	if err := outbox.Record(ctx, store,
+		events.Created("user", 1, map[string]string{"name": "a"}),
+		events.Deleted("user", 2, nil),
+	); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	fail := true
+	var delivered []outbox.Message
+	relay, err := outbox.NewRelay(outbox.Config{
+		Store: store,
+		Publisher: outbox.PublisherFunc(func(_ context.Context, msg outbox.Message) error {
+			if msg.Kind == events.KindDeleted && fail {
+				return errors.New("broker down")
+			}
+			delivered = append(delivered, msg)
+			return nil
+		}),
+	})
+	if err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+
+	n, err := relay.Poll(ctx)
+	if n != 1 || err == nil {
+		t.Fatalf("expected one delivery and an error, got %d, %v", n, err)
+	}
+	pending, _ := store.Pending(ctx, 0)
+	if len(pending) != 1 || pending[0].Attempts != 1 || pending[0].LastError != "broker down" {
+		t.Fatalf("expected failed message to remain pending, got %+v", pending)
+	}
+
+	fail = false
+	if n, err := relay.Poll(ctx); n != 1 || err != nil {
+		t.Fatalf("expected retry to deliver, got %d, %v", n, err)
+	}
+	if pending, _ := store.Pending(ctx, 0); len(pending) != 0 {
+		t.Fatalf("expected drained outbox, got %d", len(pending))
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(delivered[0].Payload, &body); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if body["kind"] != string(events.KindCreated) || body["entity"] != "user" {
+		t.Fatalf("unexpected payload %v", body)
+	}
+}
+
+func TestNewRelayRequiresDependencies(t *testing.T) {
+	if _, err := outbox.NewRelay(outbox.Config{}); err == nil {
+		t.Fatalf("expected error for missing store")
+	}
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/events"
+)
+
+// Message is a serialized event waiting in the outbox.
+type Message struct {
+	ID        string
+	Kind      events.Kind
+	Entity    string
+	Payload   []byte
+	CreatedAt time.Time
+	Attempts  int
+	LastError string
+}
+
+// payload is the JSON shape of an event stored in the outbox.
+type payload struct {
+	Kind     events.Kind       `json:"kind"`
+	Entity   string            `json:"entity"`
+	ID       any               `json:"id,omitempty"`
+	Before   any               `json:"before,omitempty"`
+	After    any               `json:"after,omitempty"`
+	Time     time.Time         `json:"time"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// FromEvent serializes event into an outbox message. The store assigns the ID.
+func FromEvent(event events.Event) (Message, error) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	body, err := json.Marshal(payload{
+		Kind:     event.Kind,
+		Entity:   event.Entity,
+		ID:       event.ID,
+		Before:   event.Before,
+		After:    event.After,
+		Time:     event.Time,
+		Metadata: event.Metadata,
+	})
+	if err != nil {
+		return Message{}, fmt.Errorf("encode outbox event: %w", err)
+	}
+	return Message{Kind: event.Kind, Entity: event.Entity, Payload: body, CreatedAt: event.Time}, nil
+}
+
+// Store persists outbox messages. SQL implementations should make Append
+// run on the same transaction as the mutation it records.
+type Store interface {
+	Append(ctx context.Context, messages ...Message) error
+	Pending(ctx context.Context, limit int) ([]Message, error)
+	MarkSent(ctx context.Context, ids ...string) error
+	MarkFailed(ctx context.Context, id string, cause error) error
+}
+
+// MemoryStore is an in-process Store for tests and single-node setups.
+type MemoryStore struct {
+	mu       sync.Mutex
+	seq      uint64
+	messages map[string]Message
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{messages: make(map[string]Message)}
+}
+
+// Append stores messages, assigning IDs to those without one.
+func (s *MemoryStore) Append(_ context.Context, messages ...Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, msg := range messages {
+		s.seq++
+		if msg.ID == "" {
+			msg.ID = strconv.FormatUint(s.seq, 10)
+		}
+		if msg.CreatedAt.IsZero() {
+			msg.CreatedAt = time.Now()
+		}
+		s.messages[msg.ID] = msg
+	}
+	return nil
+}
+
+// Pending returns up to limit unsent messages, oldest first.
+func (s *MemoryStore) Pending(_ context.Context, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Message, 0, len(s.messages))
+	for _, msg := range s.messages {
+		out = append(out, msg)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// MarkSent removes delivered messages.
+func (s *MemoryStore) MarkSent(_ context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.messages, id)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt.
+func (s *MemoryStore) MarkFailed(_ context.Context, id string, cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, ok := s.messages[id]
+	if !ok {
+		return nil
+	}
+	msg.Attempts++
+	if cause != nil {
+		msg.LastError = cause.Error()
+	}
+	s.messages[id] = msg
+	return nil
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Pending collects events raised inside a transaction so they are published
+// only after it commits.
+type Pending struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// Add queues event for publication.
+func (p *Pending) Add(event Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+// Publish delivers the queued events in order and clears the queue. Call it
+// after the transaction commits.
+func (p *Pending) Publish(ctx context.Context, bus *Bus) error {
+	p.mu.Lock()
+	queued := p.events
+	p.events = nil
+	p.mu.Unlock()
+	var errs []error
+	for _, event := range queued {
+		if err := bus.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Discard drops the queued events, e.g. after a rollback.
+func (p *Pending) Discard() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = nil
+}
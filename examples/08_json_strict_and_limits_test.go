@@ -29,7 +29,7 @@ func Test_JSONStrict_And_BodyLimit(t *testing.T) {
 	// Custom binder with strict JSON and small size limit
 	b := framework.NewDefaultBinder()
 	b.BodyDecoder = framework.JSONBodyDecoder{DisallowUnknown: true}
-	b.MaxBodyBytes = 8 // small limit to trigger too large
+	b.MaxBodyBytes = 24 // small limit to trigger too large
 	b.ReadTimeout = 100 * time.Millisecond
 
 	engine := framework.NewEngine(framework.WithBinder(b))
@@ -57,13 +57,13 @@ func Test_JSONStrict_And_BodyLimit(t *testing.T) {
 		t.Fatalf("expected 400 for unknown fields, got %d", rec1.Code)
 	}
 
-	// Body too large triggers 400 from binder mapping
+	// Body too large triggers 413 from the payload_too_large catalog entry
 	large := bytes.Repeat([]byte("x"), 32)
 	req2 := httptest.NewRequest(http.MethodPost, "/strict", bytes.NewReader(large))
 	req2.Header.Set("Content-Type", "application/json")
 	rec2 := httptest.NewRecorder()
 	h.ServeHTTP(rec2, req2)
-	if rec2.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400 for too large body, got %d", rec2.Code)
+	if rec2.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for too large body, got %d", rec2.Code)
 	}
 }
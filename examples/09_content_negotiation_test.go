@@ -68,3 +68,37 @@ func Test_ContentNegotiation(t *testing.T) {
 		t.Fatalf("unexpected JSON payload: %s", recJSON.Body.String())
 	}
 }
+
+// Unsupported Accept headers yield 406 with a JSON catalog error.
+func Test_ContentNegotiation_NotAcceptable(t *testing.T) {
+	engine := framework.NewEngine()
+
+	calls := 0
+	endpoint := framework.Endpoint[struct{}, negotiationOutput](
+		engine,
+		http.MethodGet,
+		"/hello",
+		func(ctx context.Context, _ struct{}) (negotiationOutput, error) {
+			calls++
+			return negotiationOutput{Message: "hello"}, nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, endpoint)
+
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotAcceptable {
+		t.Fatalf("expected 406, got %d", rec.Code)
+	}
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected error rendered with default renderer")
+	}
+	if calls != 0 {
+		t.Fatalf("expected handler not to run")
+	}
+}
@@ -0,0 +1,2 @@
+// Package featureflags evaluates feature flags per request and gates endpoints behind them.
+package featureflags
@@ -0,0 +1,189 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/aatuh/pureapi-framework/hooks"
+)
+
+// Provider evaluates flags for a request. Values are bool, string, or
+// float64; ok is false for unknown flags.
+type Provider interface {
+	Evaluate(ctx context.Context, name string) (value any, ok bool, err error)
+}
+
+// Lister is implemented by providers that can enumerate their flags, letting
+// Enricher snapshot every flag without an explicit list.
+type Lister interface {
+	Names(ctx context.Context) []string
+}
+
+// ProviderFunc lifts a function into a Provider.
+type ProviderFunc func(ctx context.Context, name string) (any, bool, error)
+
+// Evaluate implements Provider.
+func (f ProviderFunc) Evaluate(ctx context.Context, name string) (any, bool, error) {
+	return f(ctx, name)
+}
+
+// Flags are the flags evaluated for one request.
+type Flags map[string]any
+
+// Bool reports a bool flag; strings such as "true" and non-zero numbers count.
+func (f Flags) Bool(name string) bool {
+	switch v := f[name].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	case float64:
+		return v != 0
+	}
+	return false
+}
+
+// String returns a flag rendered as a string, or "" when unset.
+func (f Flags) String(name string) string {
+	switch v := f[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Number returns a numeric flag, parsing strings, or 0 when unset.
+func (f Flags) Number(name string) float64 {
+	switch v := f[name].(type) {
+	case float64:
+		return v
+	case string:
+		n, _ := strconv.ParseFloat(v, 64)
+		return n
+	}
+	return 0
+}
+
+type contextKey struct{}
+
+// WithFlags stores flags in ctx.
+func WithFlags(ctx context.Context, flags Flags) context.Context {
+	return context.WithValue(ctx, contextKey{}, flags)
+}
+
+// FromContext returns the flags snapshotted by Enricher, or nil.
+func FromContext(ctx context.Context) Flags {
+	if ctx == nil {
+		return nil
+	}
+	flags, _ := ctx.Value(contextKey{}).(Flags)
+	return flags
+}
+
+// Bool reports a bool flag from the request snapshot.
+func Bool(ctx context.Context, name string) bool { return FromContext(ctx).Bool(name) }
+
+// String returns a string flag from the request snapshot.
+func String(ctx context.Context, name string) string { return FromContext(ctx).String(name) }
+
+// Number returns a numeric flag from the request snapshot.
+func Number(ctx context.Context, name string) float64 { return FromContext(ctx).Number(name) }
+
+// Config controls Enricher.
+type Config struct {
+	// Provider evaluates flags. Required.
+	Provider Provider
+	// Names lists the flags to snapshot. Defaults to every flag of a Lister
+	// provider.
+	Names []string
+}
+
+// Enricher evaluates the configured flags once per request and stores them
+// in the context, so handlers and hooks see a consistent view via
+// FromContext. Evaluation errors fail the request.
+func Enricher(cfg Config) hooks.ContextEnricher {
+	return hooks.ContextEnricherFunc(func(ctx context.Context, r *http.Request) (context.Context, error) {
+		if cfg.Provider == nil {
+			return ctx, nil
+		}
+		names := cfg.Names
+		if names == nil {
+			if lister, ok := cfg.Provider.(Lister); ok {
+				names = lister.Names(ctx)
+			}
+		}
+		flags := make(Flags, len(names))
+		for name, value := range FromContext(ctx) {
+			flags[name] = value
+		}
+		for _, name := range names {
+			value, ok, err := cfg.Provider.Evaluate(ctx, name)
+			if err != nil {
+				return ctx, fmt.Errorf("evaluate flag %s: %w", name, err)
+			}
+			if ok {
+				flags[name] = value
+			}
+		}
+		return WithFlags(ctx, flags), nil
+	})
+}
+
+// ErrFlagDisabled is matched by errors returned from Gate when the flag is off.
+var ErrFlagDisabled = errors.New("feature disabled")
+
+// GateConfig controls Gate.
+type GateConfig struct {
+	// Flag is the bool flag that enables the endpoint.
+	Flag string
+	// Provider evaluates the flag when the request carries no snapshot for it.
+	Provider Provider
+	// Forbidden renders "forbidden" instead of "not_found" while the flag is
+	// off, for endpoints whose existence is not secret.
+	Forbidden bool
+}
+
+// Gate rejects requests while cfg.Flag is off. It runs as a context enricher
+// so disabled endpoints fail before binding, rendering "not_found" (or
+// "forbidden" when cfg.Forbidden is set).
+func Gate(cfg GateConfig) hooks.ContextEnricher {
+	return hooks.ContextEnricherFunc(func(ctx context.Context, r *http.Request) (context.Context, error) {
+		flags := FromContext(ctx)
+		if _, ok := flags[cfg.Flag]; !ok && cfg.Provider != nil {
+			value, found, err := cfg.Provider.Evaluate(ctx, cfg.Flag)
+			if err != nil {
+				return ctx, fmt.Errorf("evaluate flag %s: %w", cfg.Flag, err)
+			}
+			if found {
+				flags = Flags{cfg.Flag: value}
+			}
+		}
+		if flags.Bool(cfg.Flag) {
+			return ctx, nil
+		}
+		if cfg.Forbidden {
+			return ctx, disabledError{catalogID: "forbidden", message: "feature " + cfg.Flag + " is disabled"}
+		}
+		return ctx, disabledError{catalogID: "not_found"}
+	})
+}
+
+// disabledError maps to a catalog entry while matching ErrFlagDisabled.
+type disabledError struct {
+	catalogID string
+	message   string
+}
+
+func (e disabledError) Error() string        { return ErrFlagDisabled.Error() }
+func (e disabledError) CatalogID() string    { return e.catalogID }
+func (e disabledError) WireMessage() string  { return e.message }
+func (e disabledError) Is(target error) bool { return target == ErrFlagDisabled }
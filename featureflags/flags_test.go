@@ -0,0 +1,101 @@
+package featureflags_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/featureflags"
+	"github.com/aatuh/pureapi-framework/settings"
+)
+
+func TestEnricher_SnapshotsFlags(t *testing.T) {
+	env := map[string]string{"FLAG_NEW_CHECKOUT": "true", "FLAG_THEME": "dark", "FLAG_LIMIT": "2.5"}
+	provider := &featureflags.EnvProvider{Prefix: "FLAG_", Lookup: func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}}
+	enricher := featureflags.Enricher(featureflags.Config{
+		Provider: provider,
+		Names:    []string{"new-checkout", "theme", "limit", "missing"},
+	})
+	ctx, err := enricher.Enrich(context.Background(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("enrich: %v", err)
+	}
+	env["FLAG_NEW_CHECKOUT"] = "false"
+	if !featureflags.Bool(ctx, "new-checkout") || featureflags.String(ctx, "theme") != "dark" || featureflags.Number(ctx, "limit") != 2.5 {
+		t.Fatalf("unexpected snapshot: %v", featureflags.FromContext(ctx))
+	}
+	if _, ok := featureflags.FromContext(ctx)["missing"]; ok {
+		t.Fatalf("unknown flags must not be recorded")
+	}
+
+	failing := featureflags.Enricher(featureflags.Config{
+		Provider: featureflags.ProviderFunc(func(context.Context, string) (any, bool, error) {
+			return nil, false, errors.New("backend down")
+		}),
+		Names: []string{"x"},
+	})
+	if _, err := failing.Enrich(context.Background(), httptest.NewRequest(http.MethodGet, "/", nil)); err == nil {
+		t.Fatalf("expected provider error")
+	}
+}
+
+func TestProviders_FileAndSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	if err := os.WriteFile(path, []byte(`{"beta":true,"rollout":25}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	static, err := featureflags.LoadFile(path, nil)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if names := static.Names(context.Background()); !reflect.DeepEqual(names, []string{"beta", "rollout"}) {
+		t.Fatalf("unexpected names: %v", names)
+	}
+
+	store, _ := settings.NewStore(settings.Settings{Flags: map[string]any{"beta": false}})
+	enricher := featureflags.Enricher(featureflags.Config{Provider: featureflags.SettingsProvider(store)})
+	_ = store.Update(func(s *settings.Settings) { s.Flags = map[string]any{"beta": true} })
+	ctx, _ := enricher.Enrich(context.Background(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if !featureflags.Bool(ctx, "beta") {
+		t.Fatalf("expected reloaded settings flag, got %v", featureflags.FromContext(ctx))
+	}
+}
+
+func TestGate_RejectsWhenOff(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	flags := featureflags.Static{"on": true, "off": false}
+
+	if _, err := featureflags.Gate(featureflags.GateConfig{Flag: "on", Provider: flags}).Enrich(context.Background(), req); err != nil {
+		t.Fatalf("expected gate to pass, got %v", err)
+	}
+	_, err := featureflags.Gate(featureflags.GateConfig{Flag: "off", Provider: flags}).Enrich(context.Background(), req)
+	if !errors.Is(err, featureflags.ErrFlagDisabled) || catalogID(err) != "not_found" {
+		t.Fatalf("expected not_found, got %v", err)
+	}
+	_, err = featureflags.Gate(featureflags.GateConfig{Flag: "unknown", Forbidden: true}).Enrich(context.Background(), req)
+	if catalogID(err) != "forbidden" {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+
+	// A request snapshot wins over the provider.
+	ctx := featureflags.WithFlags(context.Background(), featureflags.Flags{"off": true})
+	if _, err := featureflags.Gate(featureflags.GateConfig{Flag: "off", Provider: flags}).Enrich(ctx, req); err != nil {
+		t.Fatalf("expected snapshot to enable the gate, got %v", err)
+	}
+}
+
+func catalogID(err error) string {
+	var ce interface{ CatalogID() string }
+	if errors.As(err, &ce) {
+		return ce.CatalogID()
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aatuh/pureapi-framework/settings"
+)
+
+// Static serves a fixed set of flags.
+type Static map[string]any
+
+// Evaluate implements Provider.
+func (s Static) Evaluate(_ context.Context, name string) (any, bool, error) {
+	value, ok := s[name]
+	return value, ok, nil
+}
+
+// Names implements Lister.
+func (s Static) Names(context.Context) []string {
+	return sortedKeys(s)
+}
+
+// LoadFile reads a flat name → value object from path with unmarshal (JSON
+// when nil). Use a settings.Store and SettingsProvider for flags that change
+// without a restart.
+func LoadFile(path string, unmarshal func(data []byte, v any) error) (Static, error) {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw := map[string]any{}
+	if err := unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("featureflags: %s: %w", path, err)
+	}
+	flags := make(Static, len(raw))
+	for name, value := range raw {
+		normalized, ok := normalize(value)
+		if !ok {
+			return nil, fmt.Errorf("featureflags: %s: flag %s has unsupported type %T", path, name, value)
+		}
+		flags[name] = normalized
+	}
+	return flags, nil
+}
+
+// EnvProvider reads flags from environment variables named prefix + the
+// upper-cased flag name, with dashes and dots replaced by underscores
+// (FLAG_NEW_CHECKOUT for "new-checkout"). Booleans and numbers are parsed;
+// anything else is returned as a string.
+type EnvProvider struct {
+	Prefix string
+	// Lookup defaults to os.LookupEnv.
+	Lookup func(key string) (string, bool)
+}
+
+// NewEnvProvider returns an EnvProvider for prefix, e.g. "FLAG_".
+func NewEnvProvider(prefix string) *EnvProvider {
+	return &EnvProvider{Prefix: prefix}
+}
+
+// Evaluate implements Provider.
+func (p *EnvProvider) Evaluate(_ context.Context, name string) (any, bool, error) {
+	lookup := p.Lookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	raw, ok := lookup(p.Prefix + envName(name))
+	if !ok {
+		return nil, false, nil
+	}
+	return parseValue(raw), true, nil
+}
+
+// Names implements Lister for the process environment. Names are reported in
+// lower case with underscores replaced by dashes.
+func (p *EnvProvider) Names(context.Context) []string {
+	if p.Lookup != nil {
+		return nil
+	}
+	var names []string
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if rest, ok := strings.CutPrefix(key, p.Prefix); ok && rest != "" {
+			names = append(names, strings.ReplaceAll(strings.ToLower(rest), "_", "-"))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SettingsProvider serves the Flags of the runtime settings, preferring the
+// snapshot pinned to the request context.
+func SettingsProvider(store *settings.Store) Provider {
+	return settingsProvider{store: store}
+}
+
+type settingsProvider struct {
+	store *settings.Store
+}
+
+func (p settingsProvider) snapshot(ctx context.Context) settings.Settings {
+	if s, ok := settings.FromContext(ctx); ok {
+		return s
+	}
+	return p.store.Snapshot()
+}
+
+func (p settingsProvider) Evaluate(ctx context.Context, name string) (any, bool, error) {
+	value, ok := p.snapshot(ctx).Flags[name]
+	return value, ok, nil
+}
+
+func (p settingsProvider) Names(ctx context.Context) []string {
+	return sortedKeys(p.snapshot(ctx).Flags)
+}
+
+func envName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+func parseValue(raw string) any {
+	if b, err := strconv.ParseBool(raw); err == nil {
+		return b
+	}
+	if n, err := strconv.ParseFloat(raw, 64); err == nil {
+		return n
+	}
+	return raw
+}
+
+func normalize(value any) (any, bool) {
+	switch v := value.(type) {
+	case bool, string, float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return nil, false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
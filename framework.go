@@ -2,21 +2,47 @@ package framework
 
 import (
 	"context"
+	"iter"
 	"net/http"
+	"time"
 
 	coreendpoint "github.com/aatuh/pureapi-core/endpoint"
 	coreevent "github.com/aatuh/pureapi-core/event"
 	coreserver "github.com/aatuh/pureapi-core/server"
 
 	"github.com/aatuh/pureapi-framework/binder"
+	"github.com/aatuh/pureapi-framework/binder/schema"
+	"github.com/aatuh/pureapi-framework/clientkit"
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
 	"github.com/aatuh/pureapi-framework/engine"
 	"github.com/aatuh/pureapi-framework/errors"
+	"github.com/aatuh/pureapi-framework/events"
+	"github.com/aatuh/pureapi-framework/events/outbox"
+	"github.com/aatuh/pureapi-framework/featureflags"
 	"github.com/aatuh/pureapi-framework/hooks"
+	"github.com/aatuh/pureapi-framework/jobs"
+	"github.com/aatuh/pureapi-framework/middleware/cache"
+	"github.com/aatuh/pureapi-framework/middleware/compress"
+	"github.com/aatuh/pureapi-framework/middleware/forwarded"
+	"github.com/aatuh/pureapi-framework/middleware/loadshed"
+	"github.com/aatuh/pureapi-framework/middleware/maintenance"
+	"github.com/aatuh/pureapi-framework/middleware/querybudget"
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
 	"github.com/aatuh/pureapi-framework/obs/accesslog"
+	"github.com/aatuh/pureapi-framework/obs/audit"
+	obslog "github.com/aatuh/pureapi-framework/obs/log"
+	csvrenderer "github.com/aatuh/pureapi-framework/renderer/csv"
+	htmlrenderer "github.com/aatuh/pureapi-framework/renderer/html"
 	codecjson "github.com/aatuh/pureapi-framework/renderer/json"
 	"github.com/aatuh/pureapi-framework/renderer/registry"
+	"github.com/aatuh/pureapi-framework/security/apikey"
+	"github.com/aatuh/pureapi-framework/security/cookies"
 	"github.com/aatuh/pureapi-framework/security/cors"
 	securityheaders "github.com/aatuh/pureapi-framework/security/headers"
+	"github.com/aatuh/pureapi-framework/serverutil"
+	"github.com/aatuh/pureapi-framework/settings"
+	"github.com/aatuh/pureapi-framework/tenancy"
+	"github.com/aatuh/pureapi-framework/transform"
 )
 
 // Facade type aliases keep consumers on the root package.
@@ -86,14 +112,45 @@ type (
 	FieldError = binder.FieldError
 	// BindError aggregates binding failures.
 	BindError = binder.BindError
+	// ValidationErrors collects field failures from every request layer.
+	ValidationErrors = binder.ValidationErrors
+	// Validator validates bound inputs after decoding.
+	Validator = binder.Validator
+	// ValidatorFunc lifts a function into a Validator.
+	ValidatorFunc = binder.ValidatorFunc
+	// TagValidator validates inputs using `validate` struct tags.
+	TagValidator = binder.TagValidator
+	// ValidationRule checks a single value for a TagValidator rule.
+	ValidationRule = binder.ValidationRule
+	// StructValidator is the github.com/aatuh/validate/v3 validator
+	// accepted by NewStructValidator.
+	StructValidator = binder.StructValidator
+	// Modifier rewrites bound strings for `mod` struct tags.
+	Modifier = binder.Modifier
 
 	// RenderFunc renders payloads as bytes and content type.
 	RenderFunc = registry.RenderFunc
+	// BufferPool recycles render buffers.
+	BufferPool = registry.BufferPool
 	// JSONRenderer renders JSON payloads.
 	JSONRenderer = codecjson.Renderer
+	// HTMLRenderer renders TemplateResult payloads with html/template.
+	HTMLRenderer = htmlrenderer.Renderer
+	// HTMLConfig controls an HTMLRenderer.
+	HTMLConfig = htmlrenderer.Config
+	// TemplateResult is returned by handlers to render an HTML page.
+	TemplateResult = htmlrenderer.TemplateResult
+	// CSVExportConfig controls CSV exports of list endpoints.
+	CSVExportConfig = csvrenderer.Config
 
 	// ErrorCatalog keeps registered catalog entries keyed by ID.
 	ErrorCatalog = errors.ErrorCatalog
+	// CatalogBuilder declares namespaced catalog entries.
+	CatalogBuilder = errors.CatalogBuilder
+	// CatalogEntryOption sets optional catalog entry metadata.
+	CatalogEntryOption = errors.EntryOption
+	// ErrorMappingOption configures an ErrorMapper registration.
+	ErrorMappingOption = errors.MappingOption
 	// ErrorMapper maps Go errors to catalog entries.
 	ErrorMapper = errors.ErrorMapper
 	// CatalogEntry describes a wire error returned by the framework.
@@ -111,8 +168,112 @@ type (
 	AuthorizationPolicyFunc = hooks.AuthorizationPolicyFunc
 	// AccessLogger receives structured access log entries.
 	AccessLogger = accesslog.AccessLogger
+	// Logger writes structured application logs.
+	Logger = obslog.Logger
+	// LoggerConfig controls NewLoggerEnricher.
+	LoggerConfig = obslog.Config
 	// AccessLogEntry holds structured access log data.
 	AccessLogEntry = accesslog.Entry
+	// CacheConfig controls the response cache middleware.
+	CacheConfig = cache.Config
+	// CacheStore persists cached responses.
+	CacheStore = cache.Store
+	// CompressionConfig controls response compression.
+	CompressionConfig = compress.Config
+	// CompressionEncoder creates a compressing writer for an extra encoding.
+	CompressionEncoder = compress.EncoderFunc
+	// APIKey describes the identity bound to an API key.
+	APIKey = apikey.Key
+	// APIKeyConfig controls API key extraction and lookup.
+	APIKeyConfig = apikey.Config
+	// APIKeyStore resolves presented API keys.
+	APIKeyStore = apikey.KeyStore
+	// APIKeyStoreFunc lifts a function into an APIKeyStore.
+	APIKeyStoreFunc = apikey.KeyStoreFunc
+	// CookieJar reads and writes signed or encrypted cookies.
+	CookieJar = cookies.Jar
+	// CookieConfig controls a CookieJar.
+	CookieConfig = cookies.Config
+	// ServerConfig controls Run.
+	ServerConfig = serverutil.Config
+	// ShutdownHook runs once graceful shutdown begins.
+	ShutdownHook = serverutil.ShutdownHook
+	// FeatureFlagProvider evaluates feature flags for a request.
+	FeatureFlagProvider = featureflags.Provider
+	// StaticFeatureFlags serves a fixed set of flags.
+	StaticFeatureFlags = featureflags.Static
+	// FeatureFlags are the flags evaluated for one request.
+	FeatureFlags = featureflags.Flags
+	// FeatureFlagConfig controls the feature flag enricher.
+	FeatureFlagConfig = featureflags.Config
+	// FeatureGateConfig controls gating an endpoint behind a flag.
+	FeatureGateConfig = featureflags.GateConfig
+	// ForwardedConfig lists the proxies trusted to report the client.
+	ForwardedConfig = forwarded.Config
+	// LoadSheddingConfig caps in-flight requests and queueing.
+	LoadSheddingConfig = loadshed.Config
+	// LoadShedReason tells why a request was shed.
+	LoadShedReason = loadshed.Reason
+	// QueryBudgetConfig limits the database queries of a request.
+	QueryBudgetConfig = querybudget.Config
+	// QueryBudgetError reports that a request exceeded its query budget.
+	QueryBudgetError = querybudget.Error
+	// MaintenanceConfig controls maintenance mode.
+	MaintenanceConfig = maintenance.Config
+	// StaticConfig controls static file and SPA serving.
+	StaticConfig = engine.StaticConfig
+	// RequestIDConfig controls request ID assignment and propagation.
+	RequestIDConfig = requestid.Config
+	// RequestIDGenerator produces new request IDs.
+	RequestIDGenerator = requestid.Generator
+	// Tenant identifies the tenant of a request.
+	Tenant = tenancy.Tenant
+	// TenantConfig controls tenant resolution.
+	TenantConfig = tenancy.Config
+	// TenantResolver extracts a tenant ID from a request.
+	TenantResolver = tenancy.Resolver
+	// AuditConfig controls request/response capture for audit logging.
+	AuditConfig = audit.Config
+	// AuditRecord is one audited request.
+	AuditRecord = audit.Record
+	// AuditSink persists audit records.
+	AuditSink = audit.Sink
+	// AuditSinkFunc lifts a function into an AuditSink.
+	AuditSinkFunc = audit.SinkFunc
+	// DomainEvent is an entity lifecycle event with before/after snapshots.
+	DomainEvent = events.Event
+	// DomainEventBus delivers domain events to sync and async subscribers.
+	DomainEventBus = events.Bus
+	// DomainEventHandler reacts to a domain event.
+	DomainEventHandler = events.Handler
+	// PendingDomainEvents defers publication until a transaction commits.
+	PendingDomainEvents = events.Pending
+	// OutboxMessage is a serialized domain event awaiting delivery.
+	OutboxMessage = outbox.Message
+	// OutboxStore persists outbox messages.
+	OutboxStore = outbox.Store
+	// OutboxPublisher delivers outbox messages to a broker.
+	OutboxPublisher = outbox.Publisher
+	// OutboxPublisherFunc lifts a function into an OutboxPublisher.
+	OutboxPublisherFunc = outbox.PublisherFunc
+	// OutboxConfig controls the outbox relay.
+	OutboxConfig = outbox.Config
+	// Job is a queued background task.
+	Job = jobs.Task
+	// JobHandler executes a background task.
+	JobHandler = jobs.Handler
+	// JobQueue stores background tasks until workers claim them.
+	JobQueue = jobs.Queue
+	// JobPool runs background tasks with bounded concurrency.
+	JobPool = jobs.Pool
+	// JobPoolConfig controls a JobPool.
+	JobPoolConfig = jobs.Config
+	// JobSchedule yields recurring job activation times.
+	JobSchedule = jobs.Schedule
+	// OutboundClientConfig controls clients built with NewOutboundClient.
+	OutboundClientConfig = clientkit.Config
+	// OutboundCall describes one outbound call for metrics.
+	OutboundCall = clientkit.Call
 	// CORSConfig controls the provided CORS middleware.
 	CORSConfig = cors.Config
 	// SecurityHeadersConfig controls the security header middleware.
@@ -130,6 +291,90 @@ type (
 	EndpointOption[TIn any, TOut any] = engine.EndpointOption[TIn, TOut]
 	// EndpointMeta carries optional documentation metadata.
 	EndpointMeta = engine.EndpointMeta
+	// SSEEvent is a single server-sent event.
+	SSEEvent = engine.SSEEvent
+	// EventSender writes an event to the client and flushes it immediately.
+	EventSender = engine.EventSender
+	// Streamer is implemented by outputs that stream server-sent events.
+	Streamer = engine.Streamer
+	// StreamFunc lifts a function into a Streamer.
+	StreamFunc = engine.StreamFunc
+	// ArrayStreamer is implemented by outputs streamed as a JSON array.
+	ArrayStreamer = engine.ArrayStreamer
+	// ArrayStreamFunc lifts a function into an ArrayStreamer.
+	ArrayStreamFunc = engine.ArrayStreamFunc
+	// FileResult is returned by handlers to send a file download.
+	FileResult = engine.FileResult
+	// Result is returned by handlers answering with a redirect or 204.
+	Result = engine.Result
+	// ResponseController sets headers, cookies, and the status of a response.
+	ResponseController = engine.ResponseController
+	// ItemYielder writes one element of a streamed JSON array.
+	ItemYielder = engine.ItemYielder
+	// Response wraps handler data with optional pagination, links, and warnings.
+	Response[T any] = engine.Response[T]
+	// Pagination describes the window of a paginated collection.
+	Pagination = engine.Pagination
+	// CountStrategy describes how the total of a paginated collection was counted.
+	CountStrategy = engine.CountStrategy
+	// Links carries navigation links for a response.
+	Links = engine.Links
+	// Group declares endpoints under a shared path prefix with shared options.
+	Group = engine.Group
+	// GroupOption configures a Group.
+	GroupOption = engine.GroupOption
+	// MergePatch is an RFC 7386 JSON Merge Patch body.
+	MergePatch = binder.MergePatch
+	// BinderConverter parses a raw request string into a custom type.
+	BinderConverter = binder.Converter
+	// Date binds and renders calendar dates as YYYY-MM-DD.
+	Date = binder.Date
+	// BodySchema validates raw request bodies before decoding.
+	BodySchema = binder.BodySchema
+	// StrictParams rejects undeclared query parameters and headers.
+	StrictParams = binder.StrictParams
+	// JSONSchema is a compiled JSON Schema document.
+	JSONSchema = schema.Schema
+	// SchemaViolation locates a schema failure by JSON Pointer.
+	SchemaViolation = schema.Violation
+	// MaskRule masks one output field.
+	MaskRule = hooks.MaskRule
+	// MaskPolicy is an ordered list of masking rules.
+	MaskPolicy = hooks.MaskPolicy
+	// HookInfo describes a hook's name and priority.
+	HookInfo = hooks.HookInfo
+	// EndpointDescription exposes an endpoint's method, path, and types.
+	EndpointDescription = engine.EndpointDescription
+	// HookChain lists an endpoint's effective hooks in execution order.
+	HookChain = engine.HookChain
+	// RouteInfo summarizes a declared endpoint for diagnostics.
+	RouteInfo = engine.RouteInfo
+	// RoutesEndpointConfig controls the guarded route table endpoint.
+	RoutesEndpointConfig = engine.RoutesEndpointConfig
+	// ETagger is implemented by outputs that supply their own entity tag.
+	ETagger = engine.ETagger
+	// APIVersion describes one API version and its deprecation schedule.
+	APIVersion = engine.Version
+	// VersionSet declares the same routes under several API versions.
+	VersionSet = engine.VersionSet
+	// VersioningConfig controls how a VersionSet routes requests.
+	VersioningConfig = engine.VersioningConfig
+	// VersionStrategy selects how clients ask for an API version.
+	VersionStrategy = engine.VersionStrategy
+	// PathParam describes a path parameter and its constraint.
+	PathParam = engine.PathParam
+	// AutoMethods selects the methods answered automatically by Engine.Register.
+	AutoMethods = engine.AutoMethods
+	// TransformRule rewrites one location of an output payload.
+	TransformRule = transform.Rule
+	// TransformRules are applied in order before rendering.
+	TransformRules = transform.Rules
+	// RuntimeSettings are the knobs that can change without a restart.
+	RuntimeSettings = settings.Settings
+	// SettingsStore publishes runtime settings behind an atomic snapshot.
+	SettingsStore = settings.Store
+	// SettingsWatchConfig controls polling a settings source.
+	SettingsWatchConfig = settings.WatchConfig
 )
 
 // Re-export functions from subpackages
@@ -138,14 +383,49 @@ var (
 	NewDefaultBinder             = binder.NewDefaultBinder
 	NewFieldError                = binder.NewFieldError
 	NewBindError                 = binder.NewBindError
+	ReportFieldError             = binder.ReportFieldError
+	ValidationErrorsFromContext  = binder.ValidationErrorsFromContext
+	NewTagValidator              = binder.NewTagValidator
+	NewStructValidator           = binder.NewStructValidator
+	MergePatchContentType        = binder.MergePatchContentType
+	NDJSONContentType            = binder.NDJSONContentType
+	CompileJSONSchema            = schema.Compile
+	GenerateJSONSchema           = schema.Generate
 	NewRendererRegistry          = registry.New
+	ErrNotAcceptable             = registry.ErrNotAcceptable
+	ErrUnsupportedPayload        = registry.ErrUnsupportedPayload
+	NewHTMLRenderer              = htmlrenderer.New
 	DefaultSecurityHeadersConfig = securityheaders.DefaultConfig
 
 	// Error functions
 	NewErrorCatalog     = errors.NewErrorCatalog
 	DefaultErrorCatalog = errors.DefaultErrorCatalog
+	NewCatalogBuilder   = errors.NewCatalogBuilder
+	MergeCatalogs       = errors.MergeCatalogs
+	WithDocsURL         = errors.WithDocsURL
+	RetryableError      = errors.Retryable
+	WithMappingPriority = errors.WithPriority
+	WithMappingWireData = errors.WithWireData
 	NewErrorMapper      = errors.NewErrorMapper
 	RenderError         = errors.RenderError
+	ErrNotFound         = errors.ErrNotFound
+	NotFound            = errors.NotFound
+	EntityNotFound      = errors.EntityNotFound
+	ErrConflict         = errors.ErrConflict
+	Conflict            = errors.Conflict
+	EntityConflict      = errors.EntityConflict
+
+	// Hook ordering helpers
+	NamedInputHook            = hooks.NamedInputHook
+	NamedOutputHook           = hooks.NamedOutputHook
+	RenderedError             = hooks.Rendered
+	IsRenderedError           = hooks.IsRendered
+	ResponseWriterFromContext = frameworkcontext.ResponseWriterFromContext
+
+	// Output masking helpers
+	NewMaskingHook = hooks.NewMaskingHook
+	MaskHide       = hooks.MaskHide
+	MaskRedact     = hooks.MaskRedact
 
 	// Authorization helpers
 	NewAuthorizationError = hooks.NewAuthorizationError
@@ -153,7 +433,125 @@ var (
 	ErrForbidden          = hooks.ErrForbidden
 
 	// Access log helpers
-	NewStdAccessLogger = accesslog.NewStdLogger
+	NewStdAccessLogger         = accesslog.NewStdLogger
+	AddAccessLogField          = accesslog.AddField
+	AccessLogFieldsFromContext = accesslog.FieldsFromContext
+
+	// Forwarded header helpers
+	NewForwardedMiddleware = forwarded.Middleware
+	ClientIP               = forwarded.ClientIP
+	RequestScheme          = forwarded.Scheme
+	RequestHost            = forwarded.Host
+
+	// Request ID helpers
+	NewRequestIDMiddleware = requestid.Middleware
+	RequestIDFromContext   = requestid.FromContext
+	UUIDv7RequestID        = requestid.UUIDv7
+	ULIDRequestID          = requestid.ULID
+
+	// Tenancy helpers
+	NewTenantEnricher   = tenancy.Enricher
+	TenantFromHeader    = tenancy.FromHeader
+	TenantFromSubdomain = tenancy.FromSubdomain
+	TenantFromClaim     = tenancy.FromClaim
+	TenantFromContext   = tenancy.FromContext
+	TenantIDFromContext = tenancy.IDFromContext
+
+	// Logging helpers
+	NewSlogLogger     = obslog.NewSlog
+	NewLoggerEnricher = obslog.Enricher
+	LoggerFromContext = obslog.FromContext
+	ContextWithLogger = obslog.WithLogger
+
+	// Server helpers
+	Run = serverutil.Run
+
+	// API key helpers
+	NewAPIKeyEnricher    = apikey.Enricher
+	NewAPIKeyMemoryStore = apikey.NewMemoryStore
+	NewAPIKeyEnvStore    = apikey.NewEnvStore
+	APIKeyFromContext    = apikey.FromContext
+	RequireAPIKeyScopes  = apikey.RequireScopes
+	ErrAPIKeyNotFound    = apikey.ErrKeyNotFound
+
+	// Cookie helpers
+	NewCookieJar     = cookies.New
+	CookiePlain      = cookies.Plain
+	CookieSigned     = cookies.Signed
+	CookieEncrypted  = cookies.Encrypted
+	ErrCookieInvalid = cookies.ErrInvalid
+	ErrCookieExpired = cookies.ErrExpired
+
+	// Audit helpers
+	SetAuditActor  = audit.SetActor
+	SetAuditChange = audit.SetChange
+
+	// Cache helpers
+	NewLRUCacheStore = cache.NewLRU
+
+	// Domain event helpers
+	NewDomainEventBus   = events.NewBus
+	EntityCreated       = events.Created
+	EntityUpdated       = events.Updated
+	EntityDeleted       = events.Deleted
+	EventBufferSize     = events.WithBufferSize
+	EventWorkers        = events.WithWorkers
+	EventRetry          = events.WithRetry
+	EventErrorHandler   = events.WithErrorHandler
+	ErrDomainEventQueue = events.ErrQueueFull
+
+	// Outbox helpers
+	NewOutboxMemoryStore = outbox.NewMemoryStore
+	NewOutboxRelay       = outbox.NewRelay
+	RecordOutbox         = outbox.Record
+
+	// Background job helpers
+	NewJobPool        = jobs.NewPool
+	NewMemoryJobQueue = jobs.NewMemoryQueue
+	JobAt             = jobs.At
+	JobAfter          = jobs.After
+	JobEvery          = jobs.Every
+	JobCron           = jobs.Cron
+	ErrUnknownJob     = jobs.ErrUnknownJob
+
+	// Outbound client helpers
+	NewOutboundClient    = clientkit.New
+	NewOutboundTransport = clientkit.NewTransport
+
+	// Runtime settings helpers
+	NewSettingsStore    = settings.NewStore
+	SettingsFromContext = settings.FromContext
+	SettingsFileSource  = settings.FileSource
+
+	// Maintenance mode helpers
+	NewMaintenanceMiddleware = maintenance.Middleware
+	ErrMaintenance           = maintenance.ErrUnavailable
+
+	// Load shedding helpers
+	NewLoadShedder            = loadshed.NewLimiter
+	NewLoadSheddingMiddleware = loadshed.Middleware
+	ErrOverloaded             = loadshed.ErrOverloaded
+
+	// Query budget helpers
+	RecordQuery            = querybudget.Record
+	ErrQueryBudgetExceeded = querybudget.ErrExceeded
+	QueryBudgetLog         = querybudget.ModeLog
+	QueryBudgetReject      = querybudget.ModeReject
+
+	// Feature flag helpers
+	NewFeatureFlagEnricher  = featureflags.Enricher
+	NewEnvFeatureFlags      = featureflags.NewEnvProvider
+	LoadFeatureFlags        = featureflags.LoadFile
+	SettingsFeatureFlags    = featureflags.SettingsProvider
+	FeatureFlagsFromContext = featureflags.FromContext
+	FeatureEnabled          = featureflags.Bool
+	ErrFeatureDisabled      = featureflags.ErrFlagDisabled
+
+	// Output transform helpers
+	RenameField        = transform.Rename
+	DropField          = transform.Drop
+	WrapField          = transform.Wrap
+	TransformForHeader = transform.ForHeader
 
 	// Hook functions - generic functions cannot be re-exported directly
 	// Use hooks.NewInputHook[T] and hooks.NewOutputHook[T] directly
@@ -169,6 +567,14 @@ const (
 	SourceHeader = binder.SourceHeader
 	SourceCookie = binder.SourceCookie
 	SourceBody   = binder.SourceBody
+
+	VersionByPath      = engine.VersionByPath
+	VersionByHeader    = engine.VersionByHeader
+	VersionByMediaType = engine.VersionByMediaType
+
+	CountExact     = engine.CountExact
+	CountEstimated = engine.CountEstimated
+	CountCapped    = engine.CountCapped
 )
 
 // Wrapper functions for generic types that can be re-exported
@@ -180,14 +586,60 @@ func NewEngine(opts ...EngineOption) *Engine {
 
 // Non-generic engine options
 var (
-	WithBinder                = engine.WithBinder
-	WithErrorMapper           = engine.WithErrorMapper
-	WithGlobalMiddlewares     = engine.WithGlobalMiddlewares
-	WithContextEnrichers      = engine.WithContextEnrichers
-	WithAuthorizationPolicies = engine.WithAuthorizationPolicies
-	WithAccessLoggers         = engine.WithAccessLoggers
-	WithInputHooks            = engine.WithInputHooks
-	WithOutputHooks           = engine.WithOutputHooks
+	WithBinder                   = engine.WithBinder
+	WithValidator                = engine.WithValidator
+	WithValidators               = engine.WithValidators
+	WithModifier                 = engine.WithModifier
+	WithStrictParams             = engine.WithStrictParams
+	WithRuntimeSettings          = engine.WithRuntimeSettings
+	WithMaintenance              = engine.WithMaintenance
+	WithLoadShedding             = engine.WithLoadShedding
+	WithQueryBudget              = engine.WithQueryBudget
+	WithErrorCatalogs            = engine.WithErrorCatalogs
+	Redirect                     = engine.Redirect
+	NoContent                    = engine.NoContent
+	ResponseMeta                 = engine.ResponseMeta
+	WithTransforms               = engine.WithTransforms
+	WithAutoMethods              = engine.WithAutoMethods
+	WithRouteDump                = engine.WithRouteDump
+	AllowLoopback                = engine.AllowLoopback
+	ErrMethodNotAllowed          = engine.ErrMethodNotAllowed
+	WithPathConstraintBadRequest = engine.WithPathConstraintBadRequest
+	VersionFromContext           = engine.VersionFromContext
+	ErrUnsupportedVersion        = engine.ErrUnsupportedVersion
+	WithDefaultContentType       = engine.WithDefaultContentType
+	WithEnvelope                 = engine.WithEnvelope
+	WithTimeout                  = engine.WithTimeout
+	WithCompression              = engine.WithCompression
+	WithBufferPool               = engine.WithBufferPool
+	NewBufferPool                = registry.NewBufferPool
+	WithRequestID                = engine.WithRequestID
+	ErrHandlerTimeout            = engine.ErrHandlerTimeout
+	ErrPreconditionFailed        = engine.ErrPreconditionFailed
+	ComputeETag                  = engine.ComputeETag
+	CheckPreconditions           = engine.CheckPreconditions
+	SelectedFields               = engine.SelectedFields
+	Paginate                     = engine.Paginate
+	EncodeCursor                 = engine.EncodeCursor
+	DecodeCursor                 = engine.DecodeCursor
+	ErrInvalidCursor             = engine.ErrInvalidCursor
+	RequestFromContext           = frameworkcontext.RequestFromContext
+	WithErrorMapper              = engine.WithErrorMapper
+	WithGlobalMiddlewares        = engine.WithGlobalMiddlewares
+	WithContextEnrichers         = engine.WithContextEnrichers
+	WithAuthorizationPolicies    = engine.WithAuthorizationPolicies
+	WithAccessLoggers            = engine.WithAccessLoggers
+	WithInputHooks               = engine.WithInputHooks
+	WithOutputHooks              = engine.WithOutputHooks
+)
+
+// Group options
+var (
+	WithGroupMiddlewares           = engine.WithGroupMiddlewares
+	WithGroupContextEnrichers      = engine.WithGroupContextEnrichers
+	WithGroupAuthorizationPolicies = engine.WithGroupAuthorizationPolicies
+	WithGroupErrorMapper           = engine.WithGroupErrorMapper
+	WithGroupTransforms            = engine.WithGroupTransforms
 )
 
 func NewInputHook[T any](fn func(ctx context.Context, value *T) error) InputHook {
@@ -202,6 +654,26 @@ func NewOutputHook[T any](fn func(ctx context.Context, value *T) error) OutputHo
 	return hooks.NewOutputHook(fn)
 }
 
+func NewCacheMiddleware(cfg CacheConfig) Middleware {
+	return cache.Middleware(cfg)
+}
+
+func NewAuditMiddleware(cfg AuditConfig) Middleware {
+	return audit.Middleware(cfg)
+}
+
+func WithEndpointAudit[TIn any, TOut any](cfg AuditConfig) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointAudit[TIn, TOut](cfg)
+}
+
+func NewCompressionMiddleware(cfg CompressionConfig) Middleware {
+	return compress.Middleware(cfg)
+}
+
+func WithEndpointCache[TIn any, TOut any](cfg CacheConfig) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointCache[TIn, TOut](cfg)
+}
+
 func NewCORSMiddleware(cfg CORSConfig) Middleware {
 	return cors.Middleware(cfg)
 }
@@ -218,6 +690,74 @@ func Endpoint[TIn any, TOut any](eng *Engine, method, path string, handler Handl
 	return engine.Endpoint(eng, method, path, handler, opts...)
 }
 
+func GroupEndpoint[TIn any, TOut any](group *Group, method, path string, handler HandlerFunc[TIn, TOut], opts ...EndpointOption[TIn, TOut]) *DeclarativeEndpoint[TIn, TOut] {
+	return engine.GroupEndpoint(group, method, path, handler, opts...)
+}
+
+func NewResponse[T any](data T) Response[T] {
+	return engine.NewResponse(data)
+}
+
+func WithEndpointEnvelope[TIn any, TOut any](enabled bool) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointEnvelope[TIn, TOut](enabled)
+}
+
+func WithEndpointTimeout[TIn any, TOut any](timeout time.Duration) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointTimeout[TIn, TOut](timeout)
+}
+
+func WithEndpointFieldSelection[TIn any, TOut any](allowed ...string) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointFieldSelection[TIn, TOut](allowed...)
+}
+
+func WithEndpointETag[TIn any, TOut any]() EndpointOption[TIn, TOut] {
+	return engine.WithEndpointETag[TIn, TOut]()
+}
+
+func WithEndpointBodySchema[TIn any, TOut any](s BodySchema) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointBodySchema[TIn, TOut](s)
+}
+
+func WithEndpointGeneratedBodySchema[TIn any, TOut any]() EndpointOption[TIn, TOut] {
+	return engine.WithEndpointGeneratedBodySchema[TIn, TOut]()
+}
+
+func WithEndpointStrictParams[TIn any, TOut any](strict StrictParams) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointStrictParams[TIn, TOut](strict)
+}
+
+func WithEndpointMaxBodyBytes[TIn any, TOut any](n int64) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointMaxBodyBytes[TIn, TOut](n)
+}
+
+func WithEndpointLoadShedding[TIn any, TOut any](cfg LoadSheddingConfig) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointLoadShedding[TIn, TOut](cfg)
+}
+
+func WithEndpointQueryBudget[TIn any, TOut any](cfg QueryBudgetConfig) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointQueryBudget[TIn, TOut](cfg)
+}
+
+func WithEndpointCSVExport[TIn any, TOut any](cfg CSVExportConfig) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointCSVExport[TIn, TOut](cfg)
+}
+
+func WithEndpointFeatureFlag[TIn any, TOut any](cfg FeatureGateConfig) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointFeatureFlag[TIn, TOut](cfg)
+}
+
+func WithEndpointTransforms[TIn any, TOut any](rules ...TransformRule) EndpointOption[TIn, TOut] {
+	return engine.WithEndpointTransforms[TIn, TOut](rules...)
+}
+
+func AdaptHandler[VIn any, VOut any, TIn any, TOut any](
+	handler HandlerFunc[TIn, TOut],
+	in func(ctx context.Context, input VIn) (TIn, error),
+	out func(ctx context.Context, output TOut) (VOut, error),
+) HandlerFunc[VIn, VOut] {
+	return engine.AdaptHandler(handler, in, out)
+}
+
 func WithRenderer(contentType string, fn RenderFunc) EngineOption {
 	return engine.WithRenderer(contentType, fn)
 }
@@ -254,6 +794,10 @@ func WithEndpointAccessLoggers[TIn any, TOut any](loggers ...AccessLogger) Endpo
 	return engine.WithEndpointAccessLoggers[TIn, TOut](loggers...)
 }
 
+func WithConverter[T any](fn func(string) (T, error)) EngineOption {
+	return engine.WithConverter(fn)
+}
+
 func WithEndpointErrorMapper[TIn any, TOut any](mapper *ErrorMapper) EndpointOption[TIn, TOut] {
 	return engine.WithEndpointErrorMapper[TIn, TOut](mapper)
 }
@@ -265,3 +809,19 @@ func WithEndpointInputHooks[TIn any, TOut any](hooks ...InputHook) EndpointOptio
 func WithEndpointOutputHooks[TIn any, TOut any](hooks ...OutputHook) EndpointOption[TIn, TOut] {
 	return engine.WithEndpointOutputHooks[TIn, TOut](hooks...)
 }
+
+func DeriveAPIToDBFields[Entity any]() (map[string]string, error) {
+	return binder.DeriveAPIToDBFields[Entity]()
+}
+
+func MustDeriveAPIToDBFields[Entity any]() map[string]string {
+	return binder.MustDeriveAPIToDBFields[Entity]()
+}
+
+func StreamChannel[T any](ch <-chan T) ArrayStreamFunc {
+	return engine.StreamChannel(ch)
+}
+
+func StreamSeq[T any](seq iter.Seq2[T, error]) ArrayStreamFunc {
+	return engine.StreamSeq(seq)
+}
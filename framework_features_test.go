@@ -1,14 +1,28 @@
 package framework_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	framework "github.com/aatuh/pureapi-framework"
+	"github.com/aatuh/pureapi-framework/binder"
+	"github.com/aatuh/pureapi-framework/hooks"
 )
 
 type ctxKey string
@@ -266,3 +280,2308 @@ type recordingAccessLogger struct {
 func (l *recordingAccessLogger) Log(_ context.Context, entry framework.AccessLogEntry) {
 	l.entries = append(l.entries, entry)
 }
+
+func TestGroupPrefixesPathsAndSharesOptions(t *testing.T) {
+	engine := framework.NewEngine()
+
+	var order []string
+	tag := func(name string) framework.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	api := engine.Group("/api", framework.WithGroupMiddlewares(tag("api")))
+	admin := api.Group("admin",
+		framework.WithGroupMiddlewares(tag("admin")),
+		framework.WithGroupAuthorizationPolicies(
+			framework.AuthorizationPolicyFunc(func(ctx context.Context, _ any) error {
+				return framework.ErrForbidden("admins only")
+			}),
+		),
+	)
+
+	type in struct {
+		ID string `path:"id"`
+	}
+	type out struct {
+		ID string `json:"id"`
+	}
+
+	users := framework.GroupEndpoint[in, out](
+		api,
+		http.MethodGet,
+		"/users/{id}",
+		func(ctx context.Context, input in) (out, error) {
+			return out{ID: input.ID}, nil
+		},
+		framework.WithEndpointMiddlewares[in, out](tag("endpoint")),
+	)
+	settings := framework.GroupEndpoint[in, out](
+		admin,
+		http.MethodGet,
+		"/settings",
+		func(ctx context.Context, input in) (out, error) {
+			return out{}, nil
+		},
+	)
+
+	if users.Path != "/api/users/{id}" {
+		t.Fatalf("unexpected group path: %s", users.Path)
+	}
+	if settings.Path != "/api/admin/settings" {
+		t.Fatalf("unexpected nested group path: %s", settings.Path)
+	}
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, users, settings)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/7", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if len(order) != 2 || order[0] != "api" || order[1] != "endpoint" {
+		t.Fatalf("unexpected middleware order: %v", order)
+	}
+
+	order = nil
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/admin/settings", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 from group policy, got %d", rec.Code)
+	}
+	if len(order) != 2 || order[0] != "api" || order[1] != "admin" {
+		t.Fatalf("unexpected nested middleware order: %v", order)
+	}
+}
+
+func TestStreamingOutputWritesServerSentEvents(t *testing.T) {
+	type tick struct {
+		N int `json:"n"`
+	}
+
+	engine := framework.NewEngine(
+		framework.WithOutputHooks(framework.NewOutputHook(func(ctx context.Context, value *tick) error {
+			value.N *= 10
+			return nil
+		})),
+	)
+
+	type in struct{}
+
+	endpoint := framework.Endpoint[in, framework.StreamFunc](
+		engine,
+		http.MethodGet,
+		"/ticks",
+		func(ctx context.Context, _ in) (framework.StreamFunc, error) {
+			return func(ctx context.Context, send framework.EventSender) error {
+				for i := 1; i <= 2; i++ {
+					if err := send(framework.SSEEvent{ID: fmt.Sprint(i), Event: "tick", Data: tick{N: i}}); err != nil {
+						return err
+					}
+				}
+				return nil
+			}, nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, endpoint)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ticks", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+	want := "id: 1\nevent: tick\ndata: {\"n\":10}\n\nid: 2\nevent: tick\ndata: {\"n\":20}\n\n"
+	if body := rec.Body.String(); body != want {
+		t.Fatalf("unexpected stream body:\n%q", body)
+	}
+	if !rec.Flushed {
+		t.Fatalf("expected stream to be flushed")
+	}
+}
+
+func TestArrayStreamingWritesJSONArray(t *testing.T) {
+	type item struct {
+		N int `json:"n"`
+	}
+
+	engine := framework.NewEngine(
+		framework.WithOutputHooks(framework.NewOutputHook(func(ctx context.Context, value *item) error {
+			value.N++
+			return nil
+		})),
+	)
+
+	type in struct{}
+
+	endpoint := framework.Endpoint[in, framework.ArrayStreamFunc](
+		engine,
+		http.MethodGet,
+		"/items",
+		func(ctx context.Context, _ in) (framework.ArrayStreamFunc, error) {
+			ch := make(chan item, 3)
+			for i := 0; i < 3; i++ {
+				ch <- item{N: i}
+			}
+			close(ch)
+			return framework.StreamChannel(ch), nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, endpoint)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected application/json, got %q", ct)
+	}
+	if body := rec.Body.String(); body != `[{"n":1},{"n":2},{"n":3}]` {
+		t.Fatalf("unexpected array body: %s", body)
+	}
+
+	empty := framework.Endpoint[in, framework.ArrayStreamFunc](
+		engine,
+		http.MethodGet,
+		"/empty",
+		func(ctx context.Context, _ in) (framework.ArrayStreamFunc, error) {
+			return framework.StreamSeq(func(yield func(item, error) bool) {}), nil
+		},
+	)
+	framework.RegisterEndpoints(h, empty)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/empty", nil))
+	if body := rec.Body.String(); body != "[]" {
+		t.Fatalf("expected empty array, got %s", body)
+	}
+}
+
+func TestEnvelopeWrapsOutputsAndPaginates(t *testing.T) {
+	engine := framework.NewEngine(framework.WithEnvelope())
+
+	type item struct {
+		ID int `json:"id"`
+	}
+	type in struct {
+		Offset int `query:"offset"`
+		Limit  int `query:"limit"`
+	}
+
+	list := framework.Endpoint[in, framework.Response[[]item]](
+		engine,
+		http.MethodGet,
+		"/items",
+		func(ctx context.Context, input in) (framework.Response[[]item], error) {
+			return framework.NewResponse([]item{{ID: 1}}).WithPagination(framework.RequestFromContext(ctx), 25, input.Offset, input.Limit), nil
+		},
+	)
+	capped := framework.Endpoint[in, framework.Response[[]item]](
+		engine,
+		http.MethodGet,
+		"/capped",
+		func(ctx context.Context, input in) (framework.Response[[]item], error) {
+			return framework.NewResponse([]item{{ID: 1}}).WithCountedPagination(framework.RequestFromContext(ctx), 25, framework.CountCapped, input.Offset, input.Limit), nil
+		},
+	)
+	single := framework.Endpoint[in, item](
+		engine,
+		http.MethodGet,
+		"/item",
+		func(ctx context.Context, _ in) (item, error) {
+			return item{ID: 7}, nil
+		},
+	)
+	raw := framework.Endpoint[in, item](
+		engine,
+		http.MethodGet,
+		"/raw",
+		func(ctx context.Context, _ in) (item, error) {
+			return item{ID: 9}, nil
+		},
+		framework.WithEndpointEnvelope[in, item](false),
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, list, capped, single, raw)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?offset=10&limit=10", nil))
+	var page struct {
+		Data       []item               `json:"data"`
+		Pagination framework.Pagination `json:"pagination"`
+		Links      framework.Links      `json:"links"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode page: %v", err)
+	}
+	if len(page.Data) != 1 || page.Pagination.Total != 25 || page.Pagination.Offset != 10 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	if page.Links.Next != "/items?limit=10&offset=20" || page.Links.Prev != "/items?limit=10&offset=0" {
+		t.Fatalf("unexpected links: %+v", page.Links)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/capped?offset=20&limit=10", nil))
+	page.Links = framework.Links{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode capped page: %v", err)
+	}
+	if page.Pagination.Total != 25 || page.Pagination.Count != framework.CountCapped || page.Links.Next != "/capped?limit=10&offset=30" {
+		t.Fatalf("expected capped count with a next link, got %+v %+v", page.Pagination, page.Links)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/item", nil))
+	if body := rec.Body.String(); body != `{"data":{"id":7}}` {
+		t.Fatalf("unexpected enveloped body: %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/raw", nil))
+	if body := rec.Body.String(); body != `{"id":9}` {
+		t.Fatalf("unexpected raw body: %s", body)
+	}
+}
+
+func TestTimeoutRendersGatewayTimeout(t *testing.T) {
+	logger := &recordingAccessLogger{}
+	engine := framework.NewEngine(
+		framework.WithTimeout(time.Second),
+		framework.WithAccessLoggers(logger),
+	)
+
+	type in struct{}
+	type out struct{}
+
+	release := make(chan struct{})
+	defer close(release)
+	slow := framework.Endpoint[in, out](
+		engine,
+		http.MethodGet,
+		"/slow",
+		func(ctx context.Context, _ in) (out, error) {
+			<-release // ignores ctx on purpose
+			return out{}, nil
+		},
+		framework.WithEndpointTimeout[in, out](20*time.Millisecond),
+	)
+	fast := framework.Endpoint[in, out](
+		engine,
+		http.MethodGet,
+		"/fast",
+		func(ctx context.Context, _ in) (out, error) {
+			return out{}, nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, slow, fast)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d", rec.Code)
+	}
+	var payload struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil || payload.ID != "timeout" {
+		t.Fatalf("expected timeout error body, got %s", rec.Body.String())
+	}
+	if len(logger.entries) != 1 || !logger.entries[0].TimedOut || !errors.Is(logger.entries[0].Err, framework.ErrHandlerTimeout) {
+		t.Fatalf("expected timed out access log entry, got %+v", logger.entries)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestTimedOutHandlerCannotWriteLate(t *testing.T) {
+	engine := framework.NewEngine()
+	type in struct{}
+	type out struct{}
+
+	release := make(chan struct{})
+	wrote := make(chan error, 1)
+	slow := framework.Endpoint[in, out](engine, http.MethodGet, "/slow",
+		func(ctx context.Context, _ in) (out, error) {
+			<-release // ignores ctx on purpose
+			w := framework.ResponseWriterFromContext(ctx)
+			w.Header().Set("X-Late", "1")
+			_, err := io.WriteString(w, "late")
+			wrote <- err
+			return out{}, nil
+		},
+		framework.WithEndpointTimeout[in, out](20*time.Millisecond),
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, slow)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	close(release)
+	if err := <-wrote; err == nil {
+		t.Fatalf("expected late write to fail")
+	}
+	if rec.Code != http.StatusGatewayTimeout || strings.Contains(rec.Body.String(), "late") || rec.Header().Get("X-Late") != "" {
+		t.Fatalf("expected untouched timeout response, got %d %v %q", rec.Code, rec.Header(), rec.Body.String())
+	}
+}
+
+func TestCompressionNegotiatesAndLogsCompressedBytes(t *testing.T) {
+	logger := &recordingAccessLogger{}
+	engine := framework.NewEngine(
+		framework.WithCompression(framework.CompressionConfig{MinSize: 64}),
+		framework.WithAccessLoggers(logger),
+	)
+
+	type in struct{}
+	type out struct {
+		Text string `json:"text"`
+	}
+
+	text := strings.Repeat("compress me ", 100)
+	ep := framework.Endpoint[in, out](
+		engine,
+		http.MethodGet,
+		"/text",
+		func(ctx context.Context, _ in) (out, error) {
+			return out{Text: text}, nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+
+	req := httptest.NewRequest(http.MethodGet, "/text", nil)
+	req.Header.Set("Accept-Encoding", "deflate;q=0.5, gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	var decoded out
+	if err := json.NewDecoder(zr).Decode(&decoded); err != nil || decoded.Text != text {
+		t.Fatalf("unexpected decoded body: %v", err)
+	}
+	if len(logger.entries) != 1 {
+		t.Fatalf("expected one access log entry, got %d", len(logger.entries))
+	}
+	if size := logger.entries[0].ResponseSize; size == 0 || size >= len(text) {
+		t.Fatalf("expected compressed response size, got %d", size)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/text", nil))
+	if rec.Header().Get("Content-Encoding") != "" || !strings.Contains(rec.Body.String(), "compress me") {
+		t.Fatalf("expected identity response without Accept-Encoding")
+	}
+}
+
+func TestAPIKeyEnricherRendersUnauthorized(t *testing.T) {
+	store := framework.NewAPIKeyMemoryStore(map[string]framework.APIKey{
+		"secret": {Owner: "alice", Scopes: []string{"read"}},
+	})
+	engine := framework.NewEngine(
+		framework.WithContextEnrichers(framework.NewAPIKeyEnricher(framework.APIKeyConfig{Store: store})),
+	)
+
+	type in struct{}
+	type out struct {
+		Owner string `json:"owner"`
+	}
+
+	ep := framework.Endpoint[in, out](
+		engine,
+		http.MethodGet,
+		"/me",
+		func(ctx context.Context, _ in) (out, error) {
+			key, _ := framework.APIKeyFromContext(ctx)
+			return out{Owner: key.Owner}, nil
+		},
+		framework.WithEndpointAuthorizationPolicies[in, out](framework.RequireAPIKeyScopes("read")),
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("X-API-Key", "secret")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "alice") {
+		t.Fatalf("expected authenticated response, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAccessLogEntryIncludesCustomFields(t *testing.T) {
+	logger := &recordingAccessLogger{}
+	engine := framework.NewEngine(
+		framework.WithAccessLoggers(logger),
+		framework.WithContextEnrichers(framework.NewContextEnricher(func(ctx context.Context, r *http.Request) (context.Context, error) {
+			framework.AddAccessLogField(ctx, "tenant", "acme")
+			return ctx, nil
+		})),
+	)
+
+	type in struct{}
+	type out struct{}
+
+	ep := framework.Endpoint[in, out](
+		engine,
+		http.MethodGet,
+		"/fields",
+		func(ctx context.Context, _ in) (out, error) {
+			framework.AddAccessLogField(ctx, "user", 42)
+			return out{}, nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fields", nil))
+
+	if len(logger.entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(logger.entries))
+	}
+	fields := logger.entries[0].Fields
+	if fields["tenant"] != "acme" || fields["user"] != 42 {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+}
+
+func TestRequestIDStrategyIsConfigurable(t *testing.T) {
+	engine := framework.NewEngine(framework.WithRequestID(framework.RequestIDConfig{
+		ResponseHeader: "X-Trace-ID",
+		Generator:      func() string { return "generated" },
+	}))
+
+	type in struct{}
+	type out struct {
+		ID string `json:"id"`
+	}
+
+	ep := framework.Endpoint[in, out](
+		engine,
+		http.MethodGet,
+		"/id",
+		func(ctx context.Context, _ in) (out, error) {
+			return out{ID: framework.RequestIDFromContext(ctx)}, nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+
+	req := httptest.NewRequest(http.MethodGet, "/id", nil)
+	req.Header.Set("X-Request-ID", "client-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get("X-Trace-ID") != "client-42" || !strings.Contains(rec.Body.String(), "client-42") {
+		t.Fatalf("expected incoming id to propagate, got %q %s", rec.Header().Get("X-Trace-ID"), rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/id", nil)
+	req.Header.Set("X-Request-ID", "<script>")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get("X-Trace-ID") != "generated" {
+		t.Fatalf("expected invalid id to be regenerated, got %q", rec.Header().Get("X-Trace-ID"))
+	}
+}
+
+type versionedDoc struct {
+	Version int `json:"version"`
+}
+
+func (d versionedDoc) ETag() string { return fmt.Sprintf("v%d", d.Version) }
+
+func TestConditionalRequestsUseETags(t *testing.T) {
+	engine := framework.NewEngine()
+
+	type in struct{}
+	current := versionedDoc{Version: 3}
+
+	get := framework.Endpoint[in, versionedDoc](
+		engine,
+		http.MethodGet,
+		"/doc",
+		func(ctx context.Context, _ in) (versionedDoc, error) {
+			return current, nil
+		},
+	)
+	update := framework.Endpoint[in, versionedDoc](
+		engine,
+		http.MethodPut,
+		"/doc",
+		func(ctx context.Context, _ in) (versionedDoc, error) {
+			if err := framework.CheckPreconditions(ctx, current.ETag()); err != nil {
+				return versionedDoc{}, err
+			}
+			current.Version++
+			return current, nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, get, update)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/doc", nil))
+	etag := rec.Header().Get("ETag")
+	if etag != `"v3"` {
+		t.Fatalf("expected ETag header, got %q", etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/doc", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/doc", nil)
+	req.Header.Set("If-Match", `"v1"`)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/doc", nil)
+	req.Header.Set("If-Match", "v3")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected unquoted If-Match to fail strong comparison, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/doc", nil)
+	req.Header.Set("If-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"v4"` {
+		t.Fatalf("expected update to succeed with new ETag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestCursorPaginationRoundTrip(t *testing.T) {
+	engine := framework.NewEngine()
+
+	type in struct {
+		Cursor string `query:"cursor"`
+	}
+	type key struct {
+		ID int `json:"id"`
+	}
+
+	ep := framework.Endpoint[in, framework.Response[[]int]](
+		engine,
+		http.MethodGet,
+		"/ids",
+		func(ctx context.Context, input in) (framework.Response[[]int], error) {
+			after := key{}
+			if input.Cursor != "" {
+				if err := framework.DecodeCursor(input.Cursor, &after); err != nil {
+					return framework.Response[[]int]{}, err
+				}
+			}
+			ids := []int{after.ID + 1, after.ID + 2}
+			next, err := framework.EncodeCursor(key{ID: ids[len(ids)-1]})
+			if err != nil {
+				return framework.Response[[]int]{}, err
+			}
+			return framework.NewResponse(ids).WithCursor(framework.RequestFromContext(ctx), next, 2), nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ids", nil))
+	var page struct {
+		Data       []int                `json:"data"`
+		Pagination framework.Pagination `json:"pagination"`
+		Links      framework.Links      `json:"links"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode page: %v", err)
+	}
+	if page.Pagination.NextCursor == "" || page.Links.Next == "" {
+		t.Fatalf("expected next cursor and link, got %+v %+v", page.Pagination, page.Links)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, page.Links.Next, nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode page: %v", err)
+	}
+	if len(page.Data) != 2 || page.Data[0] != 3 {
+		t.Fatalf("expected second page to resume after cursor, got %v", page.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ids?cursor=%21%21", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid cursor, got %d", rec.Code)
+	}
+}
+
+func TestFieldSelectionTrimsOutput(t *testing.T) {
+	engine := framework.NewEngine(framework.WithEnvelope())
+
+	type in struct{}
+	type user struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+
+	var seen []string
+	ep := framework.Endpoint[in, []user](
+		engine,
+		http.MethodGet,
+		"/users",
+		func(ctx context.Context, _ in) ([]user, error) {
+			seen = framework.SelectedFields(ctx)
+			return []user{{ID: 1, Name: "Ada", Email: "ada@example.com"}}, nil
+		},
+		framework.WithEndpointFieldSelection[in, []user]("id", "name"),
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users?fields=name", nil))
+	if body := rec.Body.String(); body != `{"data":[{"name":"Ada"}]}` {
+		t.Fatalf("unexpected projected body: %s", body)
+	}
+	if len(seen) != 1 || seen[0] != "name" {
+		t.Fatalf("expected selected fields in context, got %v", seen)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users?fields=email", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for disallowed field, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+	if !strings.Contains(rec.Body.String(), "ada@example.com") {
+		t.Fatalf("expected full representation without selection, got %s", rec.Body.String())
+	}
+}
+
+func TestFieldSelectionProjectsRowsOfListOutputs(t *testing.T) {
+	type in struct{}
+	type user struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	type list struct {
+		Entities []user `json:"entities"`
+		Count    int    `json:"count"`
+	}
+	type profile struct {
+		ID   int      `json:"id"`
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+
+	for _, envelope := range []bool{false, true} {
+		var opts []framework.EngineOption
+		if envelope {
+			opts = append(opts, framework.WithEnvelope())
+		}
+		engine := framework.NewEngine(opts...)
+		users := framework.Endpoint[in, list](engine, http.MethodGet, "/users",
+			func(ctx context.Context, _ in) (list, error) {
+				return list{Entities: []user{{ID: 1, Name: "Ada", Email: "ada@example.com"}}, Count: 1}, nil
+			},
+			framework.WithEndpointFieldSelection[in, list](),
+		)
+		me := framework.Endpoint[in, profile](engine, http.MethodGet, "/me",
+			func(ctx context.Context, _ in) (profile, error) {
+				return profile{ID: 1, Name: "Ada", Tags: []string{"admin"}}, nil
+			},
+			framework.WithEndpointFieldSelection[in, profile](),
+		)
+		h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+		framework.RegisterEndpoints(h, users, me)
+
+		want := map[string]string{
+			"/users?fields=id":    `{"count":1,"entities":[{"id":1}]}`,
+			"/users?fields=count": `{"count":1}`,
+			"/me?fields=id":       `{"id":1}`,
+		}
+		for target, body := range want {
+			if envelope {
+				body = `{"data":` + body + `}`
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+			if got := strings.TrimSpace(rec.Body.String()); got != body {
+				t.Fatalf("envelope %v, %s: expected %s, got %s", envelope, target, body, got)
+			}
+		}
+	}
+}
+
+func TestGetByIDRendersNotFound(t *testing.T) {
+	engine := framework.NewEngine()
+
+	type in struct {
+		ID int `path:"id"`
+	}
+	type out struct {
+		ID int `json:"id"`
+	}
+
+	ep := framework.Endpoint[in, out](
+		engine,
+		http.MethodGet,
+		"/users/{id}",
+		func(ctx context.Context, input in) (out, error) {
+			if input.ID != 1 {
+				return out{}, framework.NotFound(fmt.Sprintf("user %d not found", input.ID))
+			}
+			return out{ID: 1}, nil
+		},
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/2", nil))
+	var payload struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if rec.Code != http.StatusNotFound || payload.ID != "not_found" || payload.Message != "user 2 not found" {
+		t.Fatalf("unexpected not found response: %d %+v", rec.Code, payload)
+	}
+}
+
+func TestHookChainsAreOrderedAndRenderedErrorsSkipMapping(t *testing.T) {
+	var order []string
+	record := func(name string) framework.InputHook {
+		return hooks.NewInputHook(func(ctx context.Context, _ *struct{}) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	engine := framework.NewEngine(
+		framework.WithInputHooks(framework.NamedInputHook("late", 10, record("late"))),
+	)
+
+	type out struct{}
+	ep := framework.Endpoint[struct{}, out](
+		engine,
+		http.MethodGet,
+		"/hooks",
+		func(ctx context.Context, _ struct{}) (out, error) { return out{}, nil },
+		framework.WithEndpointInputHooks[struct{}, out](
+			framework.NamedInputHook("early", -1, record("early")),
+			hooks.NewInputHook(func(ctx context.Context, _ *struct{}) error {
+				order = append(order, "teapot")
+				framework.ResponseWriterFromContext(ctx).WriteHeader(http.StatusTeapot)
+				return framework.RenderedError(errors.New("short-circuit"))
+			}),
+		),
+	)
+
+	chains := engine.HookChains()
+	if len(chains) != 1 || len(chains[0].Input) != 3 {
+		t.Fatalf("unexpected hook chains: %+v", chains)
+	}
+	if chains[0].Input[0].Name != "early" || chains[0].Input[2].Name != "late" {
+		t.Fatalf("unexpected chain order: %+v", chains[0].Input)
+	}
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hooks", nil))
+
+	if rec.Code != http.StatusTeapot || rec.Body.Len() != 0 {
+		t.Fatalf("expected hook-rendered response, got %d %s", rec.Code, rec.Body.String())
+	}
+	if strings.Join(order, ",") != "early,teapot" {
+		t.Fatalf("unexpected execution order: %v", order)
+	}
+}
+
+func TestBodySchemaRejectsInvalidBodies(t *testing.T) {
+	type payload struct {
+		Name  string `json:"name" required:"true"`
+		Count int    `json:"count"`
+	}
+	type in struct {
+		Body payload `body:""`
+	}
+
+	engine := framework.NewEngine()
+	generated := framework.Endpoint[in, payload](engine, http.MethodPost, "/generated",
+		func(ctx context.Context, input in) (payload, error) { return input.Body, nil },
+		framework.WithEndpointGeneratedBodySchema[in, payload](),
+	)
+	compiled, err := framework.CompileJSONSchema([]byte(`{"type":"object","properties":{"count":{"type":"integer","maximum":10}}}`))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	explicit := framework.Endpoint[in, payload](engine, http.MethodPost, "/explicit",
+		func(ctx context.Context, input in) (payload, error) { return input.Body, nil },
+		framework.WithEndpointBodySchema[in, payload](compiled),
+	)
+
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, generated, explicit)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post("/generated", `{"count":"many"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var wire struct {
+		ID   string `json:"id"`
+		Data struct {
+			Fields []framework.FieldError `json:"fields"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &wire); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if wire.ID != "invalid_request" || len(wire.Data.Fields) != 2 ||
+		wire.Data.Fields[0].Field != "/name" || wire.Data.Fields[1].Field != "/count" {
+		t.Fatalf("unexpected schema errors: %+v", wire)
+	}
+
+	if rec := post("/generated", `{"name":"ok","count":2}`); rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for valid body, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := post("/explicit", `{"count":11}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 from compiled schema, got %d", rec.Code)
+	}
+}
+
+func TestStrictParamsRejectUndeclaredQuery(t *testing.T) {
+	type in struct {
+		Sort string `query:"sort"`
+	}
+	type out struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	engine := framework.NewEngine(framework.WithStrictParams(framework.StrictParams{Query: true}))
+	endpoint := framework.Endpoint[in, out](engine, http.MethodGet, "/items",
+		func(ctx context.Context, _ in) (out, error) { return out{ID: 1, Name: "a"}, nil },
+		framework.WithEndpointFieldSelection[in, out]("id", "name"),
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, endpoint)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?sort=id&fields=id", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected declared and engine params to pass, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?srot=id", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"field":"srot"`) {
+		t.Fatalf("expected 400 listing srot, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRuntimeSettingsReachHandlers(t *testing.T) {
+	type out struct {
+		Beta bool `json:"beta"`
+	}
+
+	store, err := framework.NewSettingsStore(framework.RuntimeSettings{})
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	engine := framework.NewEngine(framework.WithRuntimeSettings(store))
+	endpoint := framework.Endpoint[struct{}, out](engine, http.MethodGet, "/beta",
+		func(ctx context.Context, _ struct{}) (out, error) {
+			s, _ := framework.SettingsFromContext(ctx)
+			beta, _ := s.Flags["beta"].(bool)
+			return out{Beta: beta}, nil
+		},
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, endpoint)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/beta", nil))
+	if !strings.Contains(rec.Body.String(), `"beta":false`) {
+		t.Fatalf("expected beta off, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if err := store.Update(func(s *framework.RuntimeSettings) { s.Flags = map[string]any{"beta": true} }); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/beta", nil))
+	if !strings.Contains(rec.Body.String(), `"beta":true`) {
+		t.Fatalf("expected reloaded flag, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMaintenanceModeRendersUnavailable(t *testing.T) {
+	type out struct {
+		OK bool `json:"ok"`
+	}
+
+	engine := framework.NewEngine(framework.WithMaintenance(framework.MaintenanceConfig{
+		Enabled:     true,
+		RetryAfter:  time.Minute,
+		ExemptPaths: []string{"/status/"},
+	}))
+	handler := func(ctx context.Context, _ struct{}) (out, error) { return out{OK: true}, nil }
+	items := framework.Endpoint[struct{}, out](engine, http.MethodGet, "/items", handler)
+	status := framework.Endpoint[struct{}, out](engine, http.MethodGet, "/status/live", handler)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, items, status)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "60" || !strings.Contains(rec.Body.String(), `"unavailable"`) {
+		t.Fatalf("expected 503 unavailable, got %d %q: %s", rec.Code, rec.Header().Get("Retry-After"), rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status/live", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected exempt path to pass, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestFeatureFlagGatesEndpoint(t *testing.T) {
+	type out struct {
+		OK bool `json:"ok"`
+	}
+
+	flags := framework.StaticFeatureFlags{"beta": false}
+	engine := framework.NewEngine(framework.WithContextEnrichers(framework.NewFeatureFlagEnricher(framework.FeatureFlagConfig{
+		Provider: flags,
+	})))
+	endpoint := framework.Endpoint[struct{}, out](engine, http.MethodGet, "/beta",
+		func(ctx context.Context, _ struct{}) (out, error) { return out{OK: true}, nil },
+		framework.WithEndpointFeatureFlag[struct{}, out](framework.FeatureGateConfig{Flag: "beta"}),
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, endpoint)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/beta", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 while flag is off, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	flags["beta"] = true
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/beta", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 once flag is on, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestVersionedEndpointsDispatchAndDeprecate(t *testing.T) {
+	type itemV2 struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	type itemV1 struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	getItem := func(ctx context.Context, _ struct{}) (itemV2, error) {
+		return itemV2{ID: 1, Title: framework.VersionFromContext(ctx)}, nil
+	}
+	toV1 := framework.AdaptHandler(getItem,
+		func(_ context.Context, in struct{}) (struct{}, error) { return in, nil },
+		func(_ context.Context, out itemV2) (itemV1, error) { return itemV1{ID: out.ID, Name: out.Title}, nil },
+	)
+	sunset := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		strategy framework.VersionStrategy
+		v1, v2   func(*http.Request)
+		path     string
+	}{
+		{framework.VersionByPath, func(r *http.Request) { r.URL.Path = "/v1/items" }, func(r *http.Request) { r.URL.Path = "/v2/items" }, "/items"},
+		{framework.VersionByHeader, func(r *http.Request) { r.Header.Set("API-Version", "1") }, func(r *http.Request) {}, "/items"},
+		{framework.VersionByMediaType, func(r *http.Request) { r.Header.Set("Accept", "application/vnd.acme.v1+json") }, func(r *http.Request) { r.Header.Set("Accept", "application/json;version=2") }, "/items"},
+	} {
+		engine := framework.NewEngine()
+		versions := engine.Versions(framework.VersioningConfig{Strategy: tc.strategy})
+		v1 := versions.Version(framework.APIVersion{Name: "v1", Deprecated: true, Sunset: sunset, Link: "https://example.com/migrate"})
+		v2 := versions.Version(framework.APIVersion{Name: "v2"})
+		framework.GroupEndpoint[struct{}, itemV1](v1, http.MethodGet, tc.path, toV1)
+		framework.GroupEndpoint[struct{}, itemV2](v2, http.MethodGet, tc.path, getItem)
+		h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+		framework.RegisterEndpoints(h, versions.Endpoints()...)
+
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		tc.v1(req)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"v1"`) {
+			t.Fatalf("strategy %d: expected v1 body, got %d: %s", tc.strategy, rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Deprecation") != "true" || rec.Header().Get("Sunset") != "Tue, 01 Jan 2030 00:00:00 GMT" || !strings.Contains(rec.Header().Get("Link"), `rel="deprecation"`) {
+			t.Fatalf("strategy %d: missing deprecation headers: %v", tc.strategy, rec.Header())
+		}
+
+		req = httptest.NewRequest(http.MethodGet, tc.path, nil)
+		tc.v2(req)
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"title":"v2"`) || rec.Header().Get("Deprecation") != "" {
+			t.Fatalf("strategy %d: expected v2 body, got %d: %s %v", tc.strategy, rec.Code, rec.Body.String(), rec.Header())
+		}
+	}
+
+	engine := framework.NewEngine()
+	versions := engine.Versions(framework.VersioningConfig{Strategy: framework.VersionByHeader})
+	framework.GroupEndpoint[struct{}, itemV2](versions.Version(framework.APIVersion{Name: "v2"}), http.MethodGet, "/items", getItem)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, versions.Endpoints()...)
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("API-Version", "v9")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown version, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if desc := engine.Endpoints()[0]; desc.Meta.Version != "v2" {
+		t.Fatalf("expected version in endpoint meta, got %+v", desc.Meta)
+	}
+}
+
+func TestMediaTypeVersioningIgnoresUnversionedVendorTypes(t *testing.T) {
+	type item struct {
+		Version string `json:"version"`
+	}
+	engine := framework.NewEngine()
+	versions := engine.Versions(framework.VersioningConfig{Strategy: framework.VersionByMediaType})
+	framework.GroupEndpoint[struct{}, item](versions.Version(framework.APIVersion{Name: "v2"}), http.MethodGet, "/items",
+		func(ctx context.Context, _ struct{}) (item, error) {
+			return item{Version: framework.VersionFromContext(ctx)}, nil
+		})
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, versions.Endpoints()...)
+	get := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("application/vnd.acme+json, application/json;q=0.5"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"version":"v2"`) {
+		t.Fatalf("expected default version for unversioned vendor type, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := get("application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"); rec.Code != http.StatusNotAcceptable {
+		t.Fatalf("expected vendor type left for negotiation, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec := get("application/vnd.acme.v9+json")
+	var payload struct {
+		ID     string `json:"id"`
+		Origin string `json:"origin"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil || rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown version, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if reqID := rec.Header().Get("X-Request-ID"); reqID == "" || payload.Origin != reqID {
+		t.Fatalf("expected request ID on unsupported version error, got %q and %+v", reqID, payload)
+	}
+}
+
+func TestTransformsShimOldVersions(t *testing.T) {
+	type item struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+
+	engine := framework.NewEngine()
+	versions := engine.Versions(framework.VersioningConfig{Strategy: framework.VersionByPath})
+	v1 := versions.Version(framework.APIVersion{Name: "v1"}, framework.WithGroupTransforms(
+		framework.RenameField("title", "name"),
+		framework.WrapField("", "items"),
+	))
+	v2 := versions.Version(framework.APIVersion{Name: "v2"})
+	list := func(ctx context.Context, _ struct{}) ([]item, error) { return []item{{ID: 1, Title: "a"}}, nil }
+	framework.GroupEndpoint[struct{}, []item](v1, http.MethodGet, "/items", list,
+		framework.WithEndpointFieldSelection[struct{}, []item](),
+	)
+	framework.GroupEndpoint[struct{}, []item](v2, http.MethodGet, "/items", list)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, versions.Endpoints()...)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/items", nil))
+	if strings.TrimSpace(rec.Body.String()) != `{"items":[{"id":1,"name":"a"}]}` {
+		t.Fatalf("unexpected v1 body: %d %s", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/items", nil))
+	if strings.TrimSpace(rec.Body.String()) != `[{"id":1,"title":"a"}]` {
+		t.Fatalf("unexpected v2 body: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRegisterAnswersHeadAndOptions(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	engine := framework.NewEngine()
+	get := framework.Endpoint[struct{}, item](engine, http.MethodGet, "/items/{id}",
+		func(ctx context.Context, _ struct{}) (item, error) { return item{ID: 1}, nil },
+		framework.WithEndpointETag[struct{}, item](),
+	)
+	del := framework.Endpoint[struct{}, struct{}](engine, http.MethodDelete, "/items/{name}",
+		func(ctx context.Context, _ struct{}) (struct{}, error) { return struct{}{}, nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	engine.Register(h, get, del)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	getLength, getETag := rec.Body.Len(), rec.Header().Get("ETag")
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/items/1", nil))
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Fatalf("expected bodiless 200 for HEAD, got %d: %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Length") != strconv.Itoa(getLength) || rec.Header().Get("ETag") != getETag {
+		t.Fatalf("expected GET headers on HEAD, got %v (GET length %d)", rec.Header(), getLength)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/items/1", nil))
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Allow") != "DELETE, GET, HEAD, OPTIONS" {
+		t.Fatalf("unexpected OPTIONS response: %d %q", rec.Code, rec.Header().Get("Allow"))
+	}
+
+	disabled := framework.NewEngine(framework.WithAutoMethods(framework.AutoMethods{Head: true}))
+	h = framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	disabled.Register(h, framework.Endpoint[struct{}, item](disabled, http.MethodGet, "/items",
+		func(ctx context.Context, _ struct{}) (item, error) { return item{}, nil },
+	))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/items", nil))
+	if rec.Code == http.StatusNoContent {
+		t.Fatalf("expected OPTIONS to stay unhandled when disabled")
+	}
+}
+
+func TestAutoMethodsRunGlobalMiddlewares(t *testing.T) {
+	engine := framework.NewEngine(framework.WithGlobalMiddlewares(framework.NewCORSMiddleware(framework.CORSConfig{
+		AllowOrigins: []string{"https://app.example"},
+		AllowMethods: []string{http.MethodGet, http.MethodPost},
+	})))
+	get := framework.Endpoint[struct{}, struct{}](engine, http.MethodGet, "/items",
+		func(ctx context.Context, _ struct{}) (struct{}, error) { return struct{}{}, nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	engine.Register(h, get)
+
+	for _, method := range []string{http.MethodOptions, http.MethodHead, http.MethodDelete} {
+		req := httptest.NewRequest(method, "/items", nil)
+		req.Header.Set("Origin", "https://app.example")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example" {
+			t.Fatalf("expected CORS headers on automatic %s, got %d %v", method, rec.Code, rec.Header())
+		}
+	}
+}
+
+func TestRegisterRendersMethodNotAllowed(t *testing.T) {
+	engine := framework.NewEngine()
+	get := framework.Endpoint[struct{}, struct{}](engine, http.MethodGet, "/items/{id}",
+		func(ctx context.Context, _ struct{}) (struct{}, error) { return struct{}{}, nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	engine.Register(h, get)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items/1", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD, OPTIONS" {
+		t.Fatalf("expected 405 with Allow, got %d %q", rec.Code, rec.Header().Get("Allow"))
+	}
+	if !strings.Contains(rec.Header().Get("Content-Type"), "json") || !strings.Contains(rec.Body.String(), `"method_not_allowed"`) {
+		t.Fatalf("expected catalog JSON body, got %q: %s", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
+
+func TestPathConstraintsRejectMismatches(t *testing.T) {
+	type in struct {
+		ID   int    `path:"id"`
+		Slug string `path:"slug"`
+	}
+	type out struct {
+		ID   int    `json:"id"`
+		Slug string `json:"slug"`
+	}
+
+	for _, badRequest := range []bool{false, true} {
+		var opts []framework.EngineOption
+		if badRequest {
+			opts = append(opts, framework.WithPathConstraintBadRequest())
+		}
+		engine := framework.NewEngine(opts...)
+		ep := framework.Endpoint[in, out](engine, http.MethodGet, "/users/{id:int}/posts/{slug:regexp([a-z]{2,}(-[a-z]+)*)}",
+			func(ctx context.Context, input in) (out, error) { return out(input), nil },
+		)
+		if ep.Path != "/users/{id}/posts/{slug}" {
+			t.Fatalf("unexpected route path %q", ep.Path)
+		}
+		params := engine.Endpoints()[0].PathParams
+		if len(params) != 2 || params[0].Type != "integer" || params[1].Pattern == "" {
+			t.Fatalf("unexpected path params: %+v", params)
+		}
+		h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+		framework.RegisterEndpoints(h, ep)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/7/posts/hello-world", nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"slug":"hello-world"`) {
+			t.Fatalf("expected match, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		want := http.StatusNotFound
+		if badRequest {
+			want = http.StatusBadRequest
+		}
+		for _, target := range []string{"/users/abc/posts/hello", "/users/7/posts/X1"} {
+			rec = httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+			if rec.Code != want {
+				t.Fatalf("%s: expected %d, got %d: %s", target, want, rec.Code, rec.Body.String())
+			}
+		}
+	}
+}
+
+func TestEndpointMaxBodyBytesRenders413(t *testing.T) {
+	type body struct {
+		Name string `json:"name"`
+	}
+	type in struct {
+		Body body `body:""`
+	}
+	type out struct {
+		Name string `json:"name"`
+	}
+
+	engine := framework.NewEngine()
+	ep := framework.Endpoint[in, out](engine, http.MethodPost, "/items",
+		func(ctx context.Context, input in) (out, error) { return out(input.Body), nil },
+		framework.WithEndpointMaxBodyBytes[in, out](16),
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+
+	post := func(body string, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if chunked {
+			req.ContentLength = -1
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(`{"name":"a"}`, false); rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 within limit, got %d: %s", rec.Code, rec.Body.String())
+	}
+	// Declared and undeclared (chunked) lengths are both rejected.
+	for _, chunked := range []bool{false, true} {
+		rec := post(`{"name":"`+strings.Repeat("x", 64)+`"}`, chunked)
+		if rec.Code != http.StatusRequestEntityTooLarge || !strings.Contains(rec.Body.String(), `"payload_too_large"`) {
+			t.Fatalf("chunked=%v: expected 413, got %d: %s", chunked, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestLoadSheddingRenders503WithRetryAfter(t *testing.T) {
+	type in struct{}
+	type out struct {
+		OK bool `json:"ok"`
+	}
+
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	var rejected atomic.Int32
+	engine := framework.NewEngine(framework.WithLoadShedding(framework.LoadSheddingConfig{
+		MaxInFlight: 1,
+		RetryAfter:  5 * time.Second,
+		OnReject: func(r *http.Request, name string, reason framework.LoadShedReason) {
+			rejected.Add(1)
+		},
+	}))
+	slow := framework.Endpoint[in, out](engine, http.MethodGet, "/slow",
+		func(ctx context.Context, input in) (out, error) {
+			close(entered)
+			<-unblock
+			return out{OK: true}, nil
+		},
+	)
+	fast := framework.Endpoint[in, out](engine, http.MethodGet, "/fast",
+		func(ctx context.Context, input in) (out, error) { return out{OK: true}, nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, slow, fast)
+
+	done := make(chan int)
+	go func() {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+		done <- rec.Code
+	}()
+	<-entered
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "5" {
+		t.Fatalf("expected 503 with Retry-After, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(rec.Body.String(), `"unavailable"`) || rejected.Load() != 1 {
+		t.Fatalf("unexpected shed response %s (rejected %d)", rec.Body.String(), rejected.Load())
+	}
+
+	close(unblock)
+	if code := <-done; code != http.StatusOK {
+		t.Fatalf("expected slow request to complete, got %d", code)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected capacity after release, got %d", rec.Code)
+	}
+}
+
+func TestEndpointLoadSheddingDoesNotStarveOtherEndpoints(t *testing.T) {
+	type in struct{}
+	type out struct {
+		OK bool `json:"ok"`
+	}
+
+	entered := make(chan struct{}, 3)
+	unblock := make(chan struct{})
+	engine := framework.NewEngine(framework.WithLoadShedding(framework.LoadSheddingConfig{MaxInFlight: 2}))
+	slow := framework.Endpoint[in, out](engine, http.MethodGet, "/slow",
+		func(ctx context.Context, input in) (out, error) {
+			entered <- struct{}{}
+			<-unblock
+			return out{OK: true}, nil
+		},
+		framework.WithEndpointLoadShedding[in, out](framework.LoadSheddingConfig{MaxInFlight: 1, MaxQueue: 5, QueueTimeout: 5 * time.Second}),
+	)
+	fast := framework.Endpoint[in, out](engine, http.MethodGet, "/fast",
+		func(ctx context.Context, input in) (out, error) { return out{OK: true}, nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, slow, fast)
+
+	var wg sync.WaitGroup
+	serveSlow := func() {
+		defer wg.Done()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	}
+	wg.Add(3)
+	go serveSlow()
+	<-entered
+	// The other two queue for the endpoint slot.
+	go serveSlow()
+	go serveSlow()
+	time.Sleep(50 * time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected other endpoint served while /slow queues, got %d %s", rec.Code, rec.Body.String())
+	}
+	close(unblock)
+	wg.Wait()
+}
+
+func TestStaticServesFilesWithSPAFallback(t *testing.T) {
+	type in struct{}
+	type out struct {
+		OK bool `json:"ok"`
+	}
+
+	assets := fstest.MapFS{
+		"index.html":     {Data: []byte("<html>app</html>")},
+		"assets/app.js":  {Data: []byte("console.log('app')")},
+		"assets/big.txt": {Data: []byte("0123456789")},
+	}
+	engine := framework.NewEngine()
+	api := framework.Endpoint[in, out](engine, http.MethodGet, "/api/status",
+		func(ctx context.Context, input in) (out, error) { return out{OK: true}, nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	engine.Register(h, engine.Static(framework.StaticConfig{FS: assets, SPA: true}), api)
+
+	get := func(target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/assets/app.js", nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "public, max-age=3600" || !strings.Contains(rec.Header().Get("Content-Type"), "javascript") {
+		t.Fatalf("unexpected asset response %d %v", rec.Code, rec.Header())
+	}
+	rec = get("/assets/big.txt", http.Header{"Range": {"bytes=2-4"}})
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "234" {
+		t.Fatalf("expected partial content, got %d %q", rec.Code, rec.Body.String())
+	}
+	for _, target := range []string{"/", "/users/42/settings"} {
+		rec = get(target, nil)
+		if rec.Code != http.StatusOK || rec.Body.String() != "<html>app</html>" || rec.Header().Get("Cache-Control") != "no-cache" {
+			t.Fatalf("%s: expected index fallback, got %d %q", target, rec.Code, rec.Body.String())
+		}
+	}
+	rec = get("/api/status", nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"ok":true`) {
+		t.Fatalf("expected API route to take precedence, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	// Without SPA mode, unknown files render the catalog 404.
+	plain := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	engine.Register(plain, engine.Static(framework.StaticConfig{Prefix: "/static", FS: assets}))
+	rec = httptest.NewRecorder()
+	plain.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static/missing.js", nil))
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), `"not_found"`) {
+		t.Fatalf("expected catalog 404, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHTMLPagesAndJSONShareEngine(t *testing.T) {
+	type in struct {
+		Name string `query:"name"`
+	}
+	type out struct {
+		Name string `json:"name"`
+	}
+
+	pages, err := framework.NewHTMLRenderer(framework.HTMLConfig{
+		FS: fstest.MapFS{"hello.html": {Data: []byte(`<h1>Hello {{.}}</h1>`)}},
+	})
+	if err != nil {
+		t.Fatalf("html renderer: %v", err)
+	}
+	engine := framework.NewEngine(framework.WithRenderer("text/html", pages.RenderFunc()))
+	page := framework.Endpoint[in, framework.TemplateResult](engine, http.MethodGet, "/hello",
+		func(ctx context.Context, input in) (framework.TemplateResult, error) {
+			return framework.TemplateResult{Name: "hello.html", Data: input.Name}, nil
+		},
+	)
+	api := framework.Endpoint[in, out](engine, http.MethodGet, "/api/hello",
+		func(ctx context.Context, input in) (out, error) { return out(input), nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, page, api)
+
+	browser := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	rec := browser("/hello?name=<ann>")
+	if rec.Code != http.StatusOK || rec.Body.String() != "<h1>Hello &lt;ann&gt;</h1>" || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("unexpected page %d %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	rec = browser("/api/hello?name=ann")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" || !strings.Contains(rec.Body.String(), `"name":"ann"`) {
+		t.Fatalf("expected JSON for API endpoint, got %q: %s", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
+
+func TestCSVExportStreamsListEndpoints(t *testing.T) {
+	type in struct{}
+	type row struct {
+		ID   int    `json:"id"`
+		Name string `csv:"Name"`
+	}
+	type page struct {
+		Items []row `json:"items"`
+		Total int   `json:"total"`
+	}
+
+	engine := framework.NewEngine()
+	list := framework.Endpoint[in, page](engine, http.MethodGet, "/users",
+		func(ctx context.Context, input in) (page, error) {
+			return page{Items: []row{{1, "Ann"}, {2, "Bob"}}, Total: 2}, nil
+		},
+		framework.WithEndpointCSVExport[in, page](framework.CSVExportConfig{Filename: "users.csv"}),
+	)
+	stream := framework.Endpoint[in, framework.ArrayStreamFunc](engine, http.MethodGet, "/users/stream",
+		func(ctx context.Context, input in) (framework.ArrayStreamFunc, error) {
+			return framework.StreamChannel(func() <-chan row {
+				ch := make(chan row, 2)
+				ch <- row{3, "Cy"}
+				close(ch)
+				return ch
+			}()), nil
+		},
+		framework.WithEndpointCSVExport[in, framework.ArrayStreamFunc](framework.CSVExportConfig{}),
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, list, stream)
+
+	get := func(target, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/users", "text/csv")
+	if rec.Code != http.StatusOK || rec.Body.String() != "id,Name\n1,Ann\n2,Bob\n" {
+		t.Fatalf("unexpected export %d: %q", rec.Code, rec.Body.String())
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") || rec.Header().Get("Content-Disposition") != "attachment; filename=users.csv" {
+		t.Fatalf("unexpected export headers: %v", rec.Header())
+	}
+	if rec := get("/users", "application/json"); !strings.Contains(rec.Body.String(), `"total":2`) {
+		t.Fatalf("expected JSON by default, got %s", rec.Body.String())
+	}
+	if rec := get("/users/stream", "text/csv"); rec.Body.String() != "id,Name\n3,Cy\n" {
+		t.Fatalf("unexpected streamed export: %q", rec.Body.String())
+	}
+}
+
+func TestNDJSONBulkIngestAndOutput(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type ingestIn struct {
+		Items binder.NDJSON[item] `body:""`
+	}
+	type ingestOut struct {
+		Total int `json:"total"`
+	}
+	type listIn struct{}
+
+	engine := framework.NewEngine()
+	ingest := framework.Endpoint[ingestIn, ingestOut](engine, http.MethodPost, "/items/bulk",
+		func(ctx context.Context, input ingestIn) (ingestOut, error) {
+			var total int
+			for it, err := range input.Items.All() {
+				if err != nil {
+					return ingestOut{}, err
+				}
+				total += it.Qty
+			}
+			return ingestOut{Total: total}, nil
+		},
+	)
+	list := framework.Endpoint[listIn, []item](engine, http.MethodGet, "/items",
+		func(ctx context.Context, input listIn) ([]item, error) {
+			return []item{{"a", 1}, {"b", 2}}, nil
+		},
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ingest, list)
+
+	req := httptest.NewRequest(http.MethodPost, "/items/bulk", strings.NewReader("{\"sku\":\"a\",\"qty\":2}\n{\"sku\":\"b\",\"qty\":3}\n"))
+	req.Header.Set("Content-Type", framework.NDJSONContentType)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"total":5`) {
+		t.Fatalf("unexpected ingest response %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/items/bulk", strings.NewReader("{\"sku\":\"a\"}\nnot json\n"))
+	req.Header.Set("Content-Type", framework.NDJSONContentType)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "line 2") {
+		t.Fatalf("expected 400 naming the bad line, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept", framework.NDJSONContentType)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Type") != framework.NDJSONContentType || rec.Body.String() != "{\"sku\":\"a\",\"qty\":1}\n{\"sku\":\"b\",\"qty\":2}\n" {
+		t.Fatalf("unexpected NDJSON output %q: %q", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
+
+func TestFileResultDownloads(t *testing.T) {
+	type in struct {
+		Seekable bool `query:"seekable"`
+	}
+
+	engine := framework.NewEngine()
+	download := framework.Endpoint[in, framework.FileResult](engine, http.MethodGet, "/reports/latest",
+		func(ctx context.Context, input in) (framework.FileResult, error) {
+			if input.Seekable {
+				return framework.FileResult{Reader: strings.NewReader("0123456789"), Filename: "report.csv"}, nil
+			}
+			return framework.FileResult{Reader: io.MultiReader(strings.NewReader("abc")), Size: 3, Filename: "raw.bin", Inline: true}, nil
+		},
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, download)
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/latest?seekable=true", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Range", "bytes=3-5")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "345" || rec.Header().Get("Content-Length") != "3" {
+		t.Fatalf("expected ranged download, got %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+	if rec.Header().Get("Content-Disposition") != "attachment; filename=report.csv" || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("unexpected download headers: %v", rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/reports/latest", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "abc" || rec.Header().Get("Content-Length") != "3" {
+		t.Fatalf("expected streamed download, got %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+	if rec.Header().Get("Content-Disposition") != "inline; filename=raw.bin" || rec.Header().Get("Content-Type") != "application/octet-stream" {
+		t.Fatalf("unexpected inline headers: %v", rec.Header())
+	}
+}
+
+func TestRedirectAndNoContentResults(t *testing.T) {
+	type in struct {
+		ID string `path:"id"`
+	}
+
+	engine := framework.NewEngine()
+	create := framework.Endpoint[in, framework.Result](engine, http.MethodPost, "/orders/{id}/submit",
+		func(ctx context.Context, input in) (framework.Result, error) {
+			return framework.Redirect(http.StatusSeeOther, "/orders/"+input.ID), nil
+		},
+	)
+	remove := framework.Endpoint[in, framework.Result](engine, http.MethodDelete, "/orders/{id}",
+		func(ctx context.Context, input in) (framework.Result, error) { return framework.NoContent(), nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, create, remove)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/42/submit", nil)
+	req.Header.Set("Accept", "text/html")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/orders/42" || rec.Body.Len() != 0 {
+		t.Fatalf("expected 303 redirect, got %d %q %q", rec.Code, rec.Header().Get("Location"), rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/orders/42", nil))
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Fatalf("expected empty 204, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestResponseMetaFromHandlersAndHooks(t *testing.T) {
+	type in struct{}
+	type out struct {
+		Status string `json:"status"`
+	}
+
+	engine := framework.NewEngine(
+		framework.WithOutputHooks(framework.NewOutputHook(func(ctx context.Context, value *out) error {
+			meta := framework.ResponseMeta(ctx)
+			if meta.Header().Get("X-Job") == "" {
+				return errors.New("handler header not visible to hook")
+			}
+			meta.SetHeader("X-Hooked", "true")
+			return nil
+		})),
+	)
+	accept := framework.Endpoint[in, out](engine, http.MethodPost, "/jobs",
+		func(ctx context.Context, input in) (out, error) {
+			meta := framework.ResponseMeta(ctx)
+			meta.SetHeader("X-Job", "j-1")
+			meta.AddCookie(&http.Cookie{Name: "job", Value: "j-1", Path: "/"})
+			meta.SetStatus(http.StatusAccepted)
+			return out{Status: "queued"}, nil
+		},
+	)
+	fail := framework.Endpoint[in, out](engine, http.MethodGet, "/jobs/failing",
+		func(ctx context.Context, input in) (out, error) {
+			framework.ResponseMeta(ctx).SetHeader("X-Job", "j-2")
+			return out{}, errors.New("boom")
+		},
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, accept, fail)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs", nil))
+	if rec.Code != http.StatusAccepted || !strings.Contains(rec.Body.String(), "queued") {
+		t.Fatalf("expected 202 with body, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("X-Job") != "j-1" || rec.Header().Get("X-Hooked") != "true" {
+		t.Fatalf("expected handler and hook headers, got %v", rec.Header())
+	}
+	if rec.Header().Get("Set-Cookie") != "job=j-1; Path=/" {
+		t.Fatalf("expected cookie, got %q", rec.Header().Get("Set-Cookie"))
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/failing", nil))
+	if rec.Code != http.StatusInternalServerError || rec.Header().Get("X-Job") != "j-2" {
+		t.Fatalf("expected error with header, got %d %v", rec.Code, rec.Header())
+	}
+
+	if framework.ResponseMeta(context.Background()).Status() != 0 {
+		t.Fatalf("expected detached controller")
+	}
+}
+
+func TestCookieJarThroughResponseMeta(t *testing.T) {
+	type in struct{}
+	type out struct {
+		User string `json:"user"`
+	}
+
+	jar, err := framework.NewCookieJar(framework.CookieConfig{
+		Mode: framework.CookieSigned,
+		Keys: [][]byte{[]byte("cookie-signing-key")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine := framework.NewEngine()
+	login := framework.Endpoint[in, out](engine, http.MethodPost, "/login",
+		func(ctx context.Context, input in) (out, error) {
+			return out{User: "alice"}, jar.Set(ctx, "user", []byte("alice"))
+		},
+	)
+	me := framework.Endpoint[in, out](engine, http.MethodGet, "/me",
+		func(ctx context.Context, input in) (out, error) {
+			user, err := jar.Get(ctx, "user")
+			if err != nil {
+				return out{}, framework.ErrForbidden("invalid session")
+			}
+			return out{User: string(user)}, nil
+		},
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, login, me)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/login", nil))
+	cookies := rec.Result().Cookies()
+	if rec.Code != http.StatusCreated || len(cookies) != 1 || !cookies[0].HttpOnly || !cookies[0].Secure {
+		t.Fatalf("expected protected cookie, got %d %v", rec.Code, rec.Header())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "alice") {
+		t.Fatalf("expected signed cookie accepted, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(&http.Cookie{Name: "user", Value: "YWRtaW4.forged"})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected forged cookie rejected, got %d", rec.Code)
+	}
+}
+
+type outOfStockError struct{}
+
+func (outOfStockError) Error() string     { return "out of stock" }
+func (outOfStockError) CatalogID() string { return "orders.out_of_stock" }
+
+func TestWithErrorCatalogsComposesSubsystemCatalogs(t *testing.T) {
+	type in struct{}
+	type out struct{}
+
+	orders := framework.NewCatalogBuilder().Namespace("orders").
+		Add("out_of_stock", http.StatusConflict, "Item out of stock", framework.RetryableError()).
+		MustBuild()
+	engine := framework.NewEngine(framework.WithErrorCatalogs(orders))
+	if entry, ok := engine.ErrorCatalog().Lookup("orders.out_of_stock"); !ok || !entry.Retryable {
+		t.Fatalf("expected merged entry, got %+v", entry)
+	}
+	place := framework.Endpoint[in, out](engine, http.MethodPost, "/orders",
+		func(ctx context.Context, input in) (out, error) { return out{}, outOfStockError{} },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, place)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", nil))
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "orders.out_of_stock") {
+		t.Fatalf("expected namespaced error, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSQLNoRowsMapsToNotFound(t *testing.T) {
+	type in struct {
+		ID string `path:"id"`
+	}
+	type out struct{}
+
+	engine := framework.NewEngine()
+	get := framework.Endpoint[in, out](engine, http.MethodGet, "/users/{id}",
+		func(ctx context.Context, input in) (out, error) {
+			return out{}, fmt.Errorf("load user %s: %w", input.ID, sql.ErrNoRows)
+		},
+	)
+	update := framework.Endpoint[in, out](engine, http.MethodPut, "/users/{id}",
+		func(ctx context.Context, input in) (out, error) { return out{}, framework.EntityConflict("user") },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, get, update)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "not_found") {
+		t.Fatalf("expected 404, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/users/7", nil))
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), `"entity":"user"`) {
+		t.Fatalf("expected 409 with entity, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestValidationErrorsAggregateAcrossLayers(t *testing.T) {
+	type signup struct {
+		Email    string `json:"email" validate:"required"`
+		Password string `json:"password"`
+		Age      int    `json:"age"`
+	}
+	type in struct {
+		Invite int    `query:"invite"`
+		Body   signup `body:""`
+	}
+	type out struct{}
+
+	engine := framework.NewEngine(
+		framework.WithValidator(framework.NewTagValidator()),
+		framework.WithInputHooks(framework.NewInputHook(func(ctx context.Context, value *in) error {
+			if len(value.Body.Password) < 8 {
+				framework.ReportFieldError(ctx, "Body.Password", framework.SourceBody, "must be at least 8 characters")
+			}
+			if value.Body.Age < 18 {
+				return framework.NewBindError("", []framework.FieldError{
+					framework.NewFieldError("Body.Age", framework.SourceBody, "must be an adult"),
+				})
+			}
+			return nil
+		})),
+	)
+	register := framework.Endpoint[in, out](engine, http.MethodPost, "/signup",
+		func(ctx context.Context, input in) (out, error) {
+			framework.ReportFieldError(ctx, "Body.Email", framework.SourceBody, "already registered")
+			return out{}, nil
+		},
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, register)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/signup?invite=abc", strings.NewReader(`{"email":"","password":"short","age":12}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d %q", rec.Code, rec.Body.String())
+	}
+	for _, field := range []string{"Invite", "Body.Email", "Body.Password", "Body.Age"} {
+		if !strings.Contains(rec.Body.String(), `"field":"`+field+`"`) {
+			t.Fatalf("expected %s in one response, got %q", field, rec.Body.String())
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(`{"email":"a@example.com","password":"long enough","age":30}`)))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "already registered") {
+		t.Fatalf("expected handler-reported failure, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWithValidatorsRegistersEngineWideRules(t *testing.T) {
+	type in struct {
+		Phone string `query:"phone" validate:"required;phone"`
+	}
+	type out struct{}
+
+	phone := func(value reflect.Value, _ string) string {
+		if !strings.HasPrefix(value.String(), "+") {
+			return "must be in international format"
+		}
+		return ""
+	}
+	engine := framework.NewEngine(framework.WithValidators(map[string]framework.ValidationRule{"phone": phone}))
+	call := framework.Endpoint[in, out](engine, http.MethodGet, "/call",
+		func(ctx context.Context, input in) (out, error) { return out{}, nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, call)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/call?phone=0401234567", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"code":"phone"`) {
+		t.Fatalf("expected phone rule failure, got %d %q", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/call?phone=%2B358401234567", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected valid phone accepted, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	tv, ok := engine.Validator().(*framework.TagValidator)
+	if !ok {
+		t.Fatalf("expected tag validator, got %T", engine.Validator())
+	}
+	if code, _, _ := tv.ValidateValue("040", "phone"); code != "phone" {
+		t.Fatalf("expected custom rule through ValidateValue, got %q", code)
+	}
+	if _, _, err := framework.NewTagValidator().ValidateValue("040", "phone"); err == nil {
+		t.Fatalf("expected rules scoped to the engine")
+	}
+}
+
+func TestQueryBudgetRejectsOverrun(t *testing.T) {
+	type in struct {
+		Queries int `query:"queries"`
+	}
+	type out struct{}
+
+	engine := framework.NewEngine(framework.WithQueryBudget(framework.QueryBudgetConfig{MaxQueries: 2, Mode: framework.QueryBudgetReject}))
+	handler := func(ctx context.Context, input in) (out, error) {
+		for i := 0; i < input.Queries; i++ {
+			if err := framework.RecordQuery(ctx, time.Millisecond); err != nil {
+				return out{}, err
+			}
+		}
+		return out{}, nil
+	}
+	list := framework.Endpoint[in, out](engine, http.MethodGet, "/list", handler)
+	report := framework.Endpoint[in, out](engine, http.MethodGet, "/report", handler,
+		framework.WithEndpointQueryBudget[in, out](framework.QueryBudgetConfig{MaxQueries: 10, Mode: framework.QueryBudgetReject}))
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, list, report)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/list?queries=2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected queries within budget to pass, got %d %q", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/list?queries=3", nil))
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "query_budget_exceeded") {
+		t.Fatalf("expected query_budget_exceeded, got %d %q", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report?queries=5", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected endpoint budget to replace the engine budget, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRoutesDescribeDeclaredEndpoints(t *testing.T) {
+	type in struct{}
+	type out struct{}
+
+	var dump bytes.Buffer
+	engine := framework.NewEngine(framework.WithRouteDump(&dump))
+	get := framework.Endpoint[in, out](engine, http.MethodGet, "/items",
+		func(ctx context.Context, input in) (out, error) { return out{}, nil },
+		framework.WithMeta[in, out](framework.EndpointMeta{Summary: "Lists items.", OperationID: "list_items"}),
+		framework.WithEndpointQueryBudget[in, out](framework.QueryBudgetConfig{MaxQueries: 5}),
+	)
+	create := framework.Endpoint[in, out](engine, http.MethodPost, "/items",
+		func(ctx context.Context, input in) (out, error) { return out{}, nil },
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	admitted := false
+	engine.Register(h, get, create, engine.RoutesEndpoint(framework.RoutesEndpointConfig{
+		Allow: func(r *http.Request) bool { return admitted },
+	}))
+
+	routes := engine.Routes()
+	if len(routes) != 2 || routes[0].Summary != "Lists items." || routes[1].SuccessStatus != http.StatusCreated {
+		t.Fatalf("unexpected routes: %+v", routes)
+	}
+	if mw := routes[0].Middlewares; len(mw) == 0 || mw[len(mw)-1] != "querybudget.Middleware" {
+		t.Fatalf("unexpected middleware names: %v", routes[0].Middlewares)
+	}
+	if out := dump.String(); !strings.Contains(out, "METHOD") || !strings.Contains(out, "Lists items.") || !strings.Contains(out, "POST") {
+		t.Fatalf("unexpected route dump:\n%s", out)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/routes", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected guarded route table, got %d %q", rec.Code, rec.Body.String())
+	}
+	admitted = true
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/routes", nil))
+	var table struct {
+		Routes []framework.RouteInfo `json:"routes"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &table); err != nil || rec.Code != http.StatusOK || len(table.Routes) != 2 {
+		t.Fatalf("unexpected route table: %d %q %v", rec.Code, rec.Body.String(), err)
+	}
+	if table.Routes[0].OperationID != "list_items" || table.Routes[0].Path != "/items" {
+		t.Fatalf("unexpected route entry: %+v", table.Routes[0])
+	}
+}
+
+func TestRoutesEndpointFailsClosed(t *testing.T) {
+	engine := framework.NewEngine()
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	engine.Register(h,
+		engine.RoutesEndpoint(framework.RoutesEndpointConfig{}),
+		engine.RoutesEndpoint(framework.RoutesEndpointConfig{Path: "/debug/local", Allow: framework.AllowLoopback}),
+	)
+	get := func(path, remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := get("/debug/routes", "127.0.0.1:1234", ""); code != http.StatusNotFound {
+		t.Fatalf("expected nil Allow to refuse loopback, got %d", code)
+	}
+	if code := get("/debug/local", "127.0.0.1:1234", ""); code != http.StatusOK {
+		t.Fatalf("expected loopback admitted, got %d", code)
+	}
+	if code := get("/debug/local", "127.0.0.1:1234", "203.0.113.7"); code != http.StatusNotFound {
+		t.Fatalf("expected proxied request refused, got %d", code)
+	}
+	if code := get("/debug/local", "192.0.2.1:1234", ""); code != http.StatusNotFound {
+		t.Fatalf("expected remote peer refused, got %d", code)
+	}
+}
+
+func TestAuditCapturesBodyBeforeCompression(t *testing.T) {
+	type in struct{}
+	type out struct {
+		Text string `json:"text"`
+	}
+
+	var records []framework.AuditRecord
+	sink := framework.AuditSinkFunc(func(_ context.Context, record framework.AuditRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	engine := framework.NewEngine(framework.WithCompression(framework.CompressionConfig{MinSize: 16}))
+	text := strings.Repeat("audit me ", 20)
+	ep := framework.Endpoint[in, out](engine, http.MethodPost, "/notes",
+		func(ctx context.Context, _ in) (out, error) { return out{Text: text}, nil },
+		framework.WithEndpointAudit[in, out](framework.AuditConfig{Sink: sink}),
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+
+	req := httptest.NewRequest(http.MethodPost, "/notes", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "gzip" || len(records) != 1 {
+		t.Fatalf("expected a compressed, audited response, got %q and %d records", rec.Header().Get("Content-Encoding"), len(records))
+	}
+	var body out
+	if err := json.Unmarshal(records[0].ResponseBody, &body); err != nil || body.Text != text {
+		t.Fatalf("expected the unencoded body in the audit record, got %q %v", records[0].ResponseBody, err)
+	}
+}
+
+func TestFieldSelectionKeepsPrecisionAndTagsEachSelection(t *testing.T) {
+	type out struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+
+	engine := framework.NewEngine()
+	ep := framework.Endpoint[struct{}, out](engine, http.MethodGet, "/big",
+		func(ctx context.Context, _ struct{}) (out, error) { return out{ID: 9007199254740993, Name: "a"}, nil },
+		framework.WithEndpointFieldSelection[struct{}, out](),
+		framework.WithEndpointETag[struct{}, out](),
+	)
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, ep)
+
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	ids := get("/big?fields=id", "")
+	if body := strings.TrimSpace(ids.Body.String()); body != `{"id":9007199254740993}` {
+		t.Fatalf("expected exact int64 after projection, got %s", body)
+	}
+	names := get("/big?fields=name", "")
+	if ids.Header().Get("ETag") == "" || ids.Header().Get("ETag") == names.Header().Get("ETag") {
+		t.Fatalf("expected distinct ETags per selection, got %q and %q", ids.Header().Get("ETag"), names.Header().Get("ETag"))
+	}
+	if rec := get("/big?fields=name", ids.Header().Get("ETag")); rec.Code != http.StatusOK {
+		t.Fatalf("expected another selection's ETag not to confirm, got %d", rec.Code)
+	}
+	if rec := get("/big?fields=id", ids.Header().Get("ETag")); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for the same selection, got %d", rec.Code)
+	}
+}
+
+func TestEndpointRejectsUnknownValidationRulesAtDeclaration(t *testing.T) {
+	type in struct {
+		Email string `query:"email" validate:"required;emial"`
+	}
+	engine := framework.NewEngine(framework.WithValidator(framework.NewTagValidator()))
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), `"emial"`) {
+			t.Fatalf("expected declaration to panic on unknown rule, got %v", r)
+		}
+	}()
+	framework.Endpoint[in, struct{}](engine, http.MethodGet, "/invite",
+		func(context.Context, in) (struct{}, error) { return struct{}{}, nil })
+}
+
+func TestArrayStreamFlushesWhileProducerBlocks(t *testing.T) {
+	engine := framework.NewEngine()
+	release := make(chan struct{})
+	endpoint := framework.Endpoint[struct{}, framework.ArrayStreamFunc](engine, http.MethodGet, "/slow",
+		func(context.Context, struct{}) (framework.ArrayStreamFunc, error) {
+			return func(ctx context.Context, yield framework.ItemYielder) error {
+				if err := yield(1); err != nil {
+					return err
+				}
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return yield(2)
+			}, nil
+		})
+	h := framework.NewHTTPHandler(framework.NewNoopEventEmitter())
+	framework.RegisterEndpoints(h, endpoint)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	// Headers and the first item arrive only once flushed, so read them off
+	// the test goroutine while the producer still blocks.
+	type chunk struct {
+		body io.ReadCloser
+		data string
+		err  error
+	}
+	first := make(chan chunk, 1)
+	go func() {
+		resp, err := http.Get(srv.URL + "/slow")
+		if err != nil {
+			first <- chunk{err: err}
+			return
+		}
+		buf := make([]byte, 2)
+		n, err := io.ReadFull(resp.Body, buf)
+		first <- chunk{body: resp.Body, data: string(buf[:n]), err: err}
+	}()
+	var body io.ReadCloser
+	select {
+	case got := <-first:
+		if got.err != nil || got.data != "[1" {
+			close(release)
+			t.Fatalf("unexpected first chunk %q: %v", got.data, got.err)
+		}
+		body = got.body
+	case <-time.After(2 * time.Second):
+		close(release)
+		t.Fatalf("expected the first item flushed while the producer blocks")
+	}
+	defer body.Close()
+	close(release)
+	rest, _ := io.ReadAll(body)
+	if string(rest) != ",2]" {
+		t.Fatalf("unexpected remainder %q", rest)
+	}
+}
@@ -0,0 +1,222 @@
+package hooks
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue replaces redacted string fields.
+const RedactedValue = "[REDACTED]"
+
+// MaskAction selects how a masked field is rewritten.
+type MaskAction int
+
+const (
+	// MaskHide zeroes the field so `omitempty` fields disappear from output.
+	MaskHide MaskAction = iota
+	// MaskRedact replaces strings with RedactedValue and zeroes other kinds.
+	MaskRedact
+)
+
+// MaskRule masks one output field. Field is a dotted path of the names
+// encoding/json emits (the Go field name for fields without a json tag name)
+// from the output root; slices, maps, and pointers along the path are
+// traversed transparently.
+type MaskRule struct {
+	Field  string
+	Action MaskAction
+	// Unless skips the rule when it returns true, e.g. for admin callers.
+	Unless func(ctx context.Context) bool
+}
+
+// MaskPolicy is an ordered list of masking rules.
+type MaskPolicy []MaskRule
+
+// NewMaskingHook returns an output hook applying policy to any output type.
+// Register it globally with WithOutputHooks or per endpoint. The hook masks
+// through the output pointer it is given; slices, maps, and pointees on a
+// masked path are copied first, so data the handler shares with a cache or
+// other requests is left untouched.
+func NewMaskingHook(policy MaskPolicy) OutputHook {
+	rules := make([]MaskRule, 0, len(policy))
+	for _, rule := range policy {
+		if rule.Field != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return valueHookFunc(func(ctx context.Context, value any) error {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return nil
+		}
+		root := rv.Elem()
+		for _, rule := range rules {
+			if rule.Unless != nil && rule.Unless(ctx) {
+				continue
+			}
+			if masked, ok := maskPath(root, strings.Split(rule.Field, "."), rule.Action); ok && root.CanSet() {
+				root.Set(masked)
+			}
+		}
+		return nil
+	})
+}
+
+// maskPath returns a masked copy of v and true, or false when path matches
+// nothing. It never writes into v or anything v references.
+func maskPath(v reflect.Value, path []string, action MaskAction) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v, false
+		}
+		elem, ok := maskPath(v.Elem(), path, action)
+		if !ok {
+			return v, false
+		}
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().Set(elem)
+		return ptr, true
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, false
+		}
+		elem, ok := maskPath(v.Elem(), path, action)
+		if !ok {
+			return v, false
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(elem)
+		return out, true
+	case reflect.Slice, reflect.Array:
+		var out reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			elem, ok := maskPath(v.Index(i), path, action)
+			if !ok {
+				continue
+			}
+			if !out.IsValid() {
+				out = copyList(v)
+			}
+			out.Index(i).Set(elem)
+		}
+		return out, out.IsValid()
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return v, false
+		}
+		key := reflect.ValueOf(path[0]).Convert(v.Type().Key())
+		elem := v.MapIndex(key)
+		if !elem.IsValid() {
+			return v, false
+		}
+		if len(path) == 1 {
+			out := copyMap(v)
+			if action == MaskHide {
+				out.SetMapIndex(key, reflect.Value{})
+			} else {
+				out.SetMapIndex(key, maskedValue(elem, action))
+			}
+			return out, true
+		}
+		masked, ok := maskPath(elem, path[1:], action)
+		if !ok {
+			return v, false
+		}
+		out := copyMap(v)
+		out.SetMapIndex(key, masked)
+		return out, true
+	case reflect.Struct:
+		index, ok := structFieldByName(v.Type(), path[0])
+		if !ok {
+			return v, false
+		}
+		field := v.FieldByIndex(index)
+		if len(path) > 1 {
+			if field, ok = maskPath(field, path[1:], action); !ok {
+				return v, false
+			}
+		} else {
+			field = maskedValue(field, action)
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		target := out.FieldByIndex(index)
+		if !target.CanSet() {
+			return v, false
+		}
+		target.Set(field)
+		return out, true
+	}
+	return v, false
+}
+
+// copyList returns an addressable shallow copy of a slice or array.
+func copyList(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Array {
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		return out
+	}
+	out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(out, v)
+	return out
+}
+
+// copyMap returns a shallow copy of a map.
+func copyMap(v reflect.Value) reflect.Value {
+	out := reflect.MakeMapWithSize(v.Type(), v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		out.SetMapIndex(iter.Key(), iter.Value())
+	}
+	return out
+}
+
+// structFieldByName returns the index of the field encoding/json would emit
+// as name: its json tag name, or its Go name when the tag sets none. Fields
+// tagged `json:"-"` never match; untagged embedded structs are searched as
+// if their fields were promoted.
+func structFieldByName(rt reflect.Type, name string) ([]int, bool) {
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && jsonName == "" && sf.Type.Kind() == reflect.Struct {
+			if index, ok := structFieldByName(sf.Type, name); ok {
+				return append([]int{i}, index...), true
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if jsonName == name || (jsonName == "" && sf.Name == name) {
+			return []int{i}, true
+		}
+	}
+	return nil, false
+}
+
+func maskedValue(v reflect.Value, action MaskAction) reflect.Value {
+	typ := v.Type()
+	if action != MaskRedact {
+		return reflect.Zero(typ)
+	}
+	redacted := reflect.ValueOf(RedactedValue)
+	switch {
+	case typ.Kind() == reflect.String:
+		return redacted.Convert(typ)
+	case typ.Kind() == reflect.Interface && redacted.Type().Implements(typ):
+		return redacted
+	case typ.Kind() == reflect.Pointer && typ.Elem().Kind() == reflect.String && !v.IsNil():
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(redacted.Convert(typ.Elem()))
+		return ptr
+	}
+	return reflect.Zero(typ)
+}
@@ -0,0 +1,115 @@
+package hooks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/hooks"
+)
+
+type maskedProfile struct {
+	Email   string         `json:"email,omitempty"`
+	SSN     string         `json:"ssn"`
+	Phone   *string        `json:"phone"`
+	Extra   map[string]any `json:"extra"`
+	Friends []maskedFriend `json:"friends"`
+}
+
+type maskedFriend struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type adminKey struct{}
+
+func TestMaskingHookAppliesPolicy(t *testing.T) {
+	hook := hooks.NewMaskingHook(hooks.MaskPolicy{
+		{Field: "email", Action: hooks.MaskHide, Unless: func(ctx context.Context) bool {
+			return ctx.Value(adminKey{}) != nil
+		}},
+		{Field: "ssn", Action: hooks.MaskRedact},
+		{Field: "phone", Action: hooks.MaskRedact},
+		{Field: "extra.token", Action: hooks.MaskRedact},
+		{Field: "friends.email", Action: hooks.MaskHide},
+	})
+
+	newProfile := func() maskedProfile {
+		phone := "555-0100"
+		return maskedProfile{
+			Email:   "ada@example.com",
+			SSN:     "123-45-6789",
+			Phone:   &phone,
+			Extra:   map[string]any{"token": "abc", "plan": "pro"},
+			Friends: []maskedFriend{{Name: "Bob", Email: "bob@example.com"}},
+		}
+	}
+
+	profile := newProfile()
+	if err := hook.Process(context.Background(), &profile); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if profile.Email != "" || profile.SSN != hooks.RedactedValue || *profile.Phone != hooks.RedactedValue {
+		t.Fatalf("unexpected masked profile: %+v", profile)
+	}
+	if profile.Extra["token"] != hooks.RedactedValue || profile.Extra["plan"] != "pro" {
+		t.Fatalf("unexpected masked map: %v", profile.Extra)
+	}
+	if profile.Friends[0].Email != "" || profile.Friends[0].Name != "Bob" {
+		t.Fatalf("unexpected masked slice: %+v", profile.Friends)
+	}
+
+	admin := newProfile()
+	if err := hook.Process(context.WithValue(context.Background(), adminKey{}, true), &admin); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if admin.Email != "ada@example.com" || admin.SSN != hooks.RedactedValue {
+		t.Fatalf("expected admin to see email but not ssn: %+v", admin)
+	}
+}
+
+func TestMaskingHookCopiesSharedDataAndFollowsJSONNames(t *testing.T) {
+	type tagged struct {
+		Secret string `json:"public_name"`
+		Hidden string `json:"-"`
+		Plain  string
+	}
+	type output struct {
+		Friends []maskedFriend     `json:"friends"`
+		Extra   map[string]any     `json:"extra"`
+		Owner   *maskedFriend      `json:"owner"`
+		Tagged  tagged             `json:"tagged"`
+		Nested  map[string]*tagged `json:"nested"`
+	}
+	shared := output{
+		Friends: []maskedFriend{{Name: "Bob", Email: "bob@example.com"}},
+		Extra:   map[string]any{"token": "abc"},
+		Owner:   &maskedFriend{Email: "ada@example.com"},
+		Tagged:  tagged{Secret: "s", Hidden: "h", Plain: "p"},
+		Nested:  map[string]*tagged{"a": {Plain: "p"}},
+	}
+	hook := hooks.NewMaskingHook(hooks.MaskPolicy{
+		{Field: "friends.email", Action: hooks.MaskRedact},
+		{Field: "extra.token", Action: hooks.MaskRedact},
+		{Field: "owner.email", Action: hooks.MaskRedact},
+		{Field: "tagged.Secret", Action: hooks.MaskRedact},
+		{Field: "tagged.Hidden", Action: hooks.MaskRedact},
+		{Field: "tagged.Plain", Action: hooks.MaskRedact},
+		{Field: "nested.a.Plain", Action: hooks.MaskRedact},
+	})
+
+	out := shared
+	if err := hook.Process(context.Background(), &out); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if out.Friends[0].Email != hooks.RedactedValue || out.Extra["token"] != hooks.RedactedValue ||
+		out.Owner.Email != hooks.RedactedValue || out.Nested["a"].Plain != hooks.RedactedValue {
+		t.Fatalf("expected output masked: %+v", out)
+	}
+	if shared.Friends[0].Email != "bob@example.com" || shared.Extra["token"] != "abc" ||
+		shared.Owner.Email != "ada@example.com" || shared.Nested["a"].Plain != "p" {
+		t.Fatalf("expected shared data untouched: %+v", shared)
+	}
+	if out.Tagged.Secret != "s" || out.Tagged.Hidden != "h" || out.Tagged.Plain != hooks.RedactedValue {
+		t.Fatalf("expected only JSON names to match: %+v", out.Tagged)
+	}
+}
@@ -0,0 +1,80 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// HookInfo describes a hook for ordering and introspection. Hooks with lower
+// Priority run first; equal priorities keep registration order.
+type HookInfo struct {
+	Name     string
+	Priority int
+}
+
+type describedHook struct {
+	valueHook
+	info HookInfo
+}
+
+// HookInfo implements the introspection contract used by Info.
+func (h describedHook) HookInfo() HookInfo {
+	return h.info
+}
+
+// NamedInputHook attaches a name and priority to hook.
+func NamedInputHook(name string, priority int, hook InputHook) InputHook {
+	if hook == nil {
+		return nil
+	}
+	return describedHook{valueHook: hook, info: HookInfo{Name: name, Priority: priority}}
+}
+
+// NamedOutputHook attaches a name and priority to hook.
+func NamedOutputHook(name string, priority int, hook OutputHook) OutputHook {
+	if hook == nil {
+		return nil
+	}
+	return describedHook{valueHook: hook, info: HookInfo{Name: name, Priority: priority}}
+}
+
+// Info returns the name and priority of hook. Unnamed hooks report their
+// dynamic type and priority zero.
+func Info(hook any) HookInfo {
+	if described, ok := hook.(interface{ HookInfo() HookInfo }); ok {
+		return described.HookInfo()
+	}
+	return HookInfo{Name: fmt.Sprintf("%T", hook)}
+}
+
+// SortByPriority orders hooks by ascending priority, keeping registration
+// order for equal priorities.
+func SortByPriority[H any](hooks []H) {
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return Info(hooks[i]).Priority < Info(hooks[j]).Priority
+	})
+}
+
+type renderedError struct {
+	err error
+}
+
+func (e renderedError) Error() string { return e.err.Error() }
+func (e renderedError) Unwrap() error { return e.err }
+
+// Rendered marks err as already written to the response by the hook, so the
+// engine records it without mapping or rendering an error body. Hooks reach
+// the writer through context.ResponseWriterFromContext.
+func Rendered(err error) error {
+	if err == nil {
+		return nil
+	}
+	return renderedError{err: err}
+}
+
+// IsRendered reports whether err was marked with Rendered.
+func IsRendered(err error) bool {
+	var rendered renderedError
+	return errors.As(err, &rendered)
+}
@@ -0,0 +1,41 @@
+package hooks_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/hooks"
+)
+
+func TestSortByPriorityIsStable(t *testing.T) {
+	noop := func(context.Context, *int) error { return nil }
+	chain := []hooks.InputHook{
+		hooks.NamedInputHook("audit", 10, hooks.NewInputHook(noop)),
+		hooks.NamedInputHook("first", 0, hooks.NewInputHook(noop)),
+		hooks.NamedInputHook("auth", -5, hooks.NewInputHook(noop)),
+		hooks.NamedInputHook("second", 0, hooks.NewInputHook(noop)),
+	}
+	hooks.SortByPriority(chain)
+
+	want := []string{"auth", "first", "second", "audit"}
+	for i, hook := range chain {
+		if got := hooks.Info(hook).Name; got != want[i] {
+			t.Fatalf("position %d: expected %s, got %s", i, want[i], got)
+		}
+	}
+	if info := hooks.Info(hooks.NewInputHook(noop)); info.Name == "" || info.Priority != 0 {
+		t.Fatalf("unexpected info for unnamed hook: %+v", info)
+	}
+}
+
+func TestRenderedMarksErrors(t *testing.T) {
+	base := errors.New("boom")
+	marked := hooks.Rendered(base)
+	if !hooks.IsRendered(marked) || !errors.Is(marked, base) {
+		t.Fatalf("expected marked error to unwrap to base")
+	}
+	if hooks.IsRendered(base) || hooks.Rendered(nil) != nil {
+		t.Fatalf("unexpected rendered marker behaviour")
+	}
+}
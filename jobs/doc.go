@@ -0,0 +1,2 @@
+// Package jobs runs background jobs on a bounded worker pool with retries and schedules.
+package jobs
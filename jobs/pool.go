@@ -0,0 +1,331 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
+	"github.com/aatuh/pureapi-framework/tenancy"
+)
+
+// ErrUnknownJob is recorded when no handler is registered for a task name.
+var ErrUnknownJob = errors.New("unknown job")
+
+// Handler executes a task. Returning an error schedules a retry until the
+// pool's MaxAttempts is reached.
+type Handler func(ctx context.Context, task Task) error
+
+// Decode unmarshals the task payload into v.
+func (t Task) Decode(v any) error {
+	return json.Unmarshal(t.Payload, v)
+}
+
+// Config controls a Pool.
+type Config struct {
+	// Queue stores tasks. Defaults to a MemoryQueue.
+	Queue Queue
+	// Concurrency bounds simultaneously running tasks. Defaults to 4.
+	Concurrency int
+	// PollInterval is the wait between claims when the queue is empty.
+	// Defaults to one second.
+	PollInterval time.Duration
+	// MaxAttempts bounds executions per task. Defaults to 3.
+	MaxAttempts int
+	// Backoff returns the delay before retry attempt n (1-based). Defaults
+	// to exponential backoff starting at one second.
+	Backoff func(attempt int) time.Duration
+	// OnError receives task failures and queue errors.
+	OnError func(task Task, err error)
+}
+
+// EnqueueOption adjusts a task before it is queued.
+type EnqueueOption func(*Task)
+
+// At schedules the task to run no earlier than t.
+func At(t time.Time) EnqueueOption {
+	return func(task *Task) {
+		task.RunAt = t
+	}
+}
+
+// After delays the task by d.
+func After(d time.Duration) EnqueueOption {
+	return func(task *Task) {
+		task.RunAt = time.Now().Add(d)
+	}
+}
+
+// Pool claims tasks from a queue and runs them with bounded concurrency.
+type Pool struct {
+	cfg Config
+
+	mu        sync.RWMutex
+	handlers  map[string]Handler
+	schedules []scheduled
+
+	wake    chan struct{}
+	stop    chan struct{}
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+	loops   sync.WaitGroup
+	started bool
+}
+
+type scheduled struct {
+	schedule Schedule
+	name     string
+	payload  any
+}
+
+// NewPool creates a Pool.
+func NewPool(cfg Config) *Pool {
+	if cfg.Queue == nil {
+		cfg.Queue = NewMemoryQueue()
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 4
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.Backoff == nil {
+		cfg.Backoff = func(attempt int) time.Duration {
+			return time.Second << uint(attempt-1)
+		}
+	}
+	return &Pool{
+		cfg:      cfg,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Register binds name to handler.
+func (p *Pool) Register(name string, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[name] = handler
+}
+
+// Enqueue queues a named task. Payload is JSON encoded unless it is already
+// a []byte. The request ID and tenant on ctx travel with the task.
+func (p *Pool) Enqueue(ctx context.Context, name string, payload any, opts ...EnqueueOption) (string, error) {
+	body, err := encodePayload(payload)
+	if err != nil {
+		return "", err
+	}
+	task := Task{
+		Name:      name,
+		Payload:   body,
+		RunAt:     time.Now(),
+		RequestID: requestid.FromContext(ctx),
+		TenantID:  tenancy.IDFromContext(ctx),
+	}
+	for _, opt := range opts {
+		opt(&task)
+	}
+	id, err := p.cfg.Queue.Enqueue(ctx, task)
+	if err != nil {
+		return "", err
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return id, nil
+}
+
+// Schedule enqueues name with payload at every activation of schedule while
+// the pool runs. Register schedules before calling Start.
+func (p *Pool) Schedule(schedule Schedule, name string, payload any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.schedules = append(p.schedules, scheduled{schedule: schedule, name: name, payload: payload})
+}
+
+// Start launches the dispatcher and schedulers. Running tasks inherit ctx
+// values but are only cancelled when Shutdown times out.
+func (p *Pool) Start(ctx context.Context) {
+	p.mu.Lock()
+	if p.started {
+		p.mu.Unlock()
+		return
+	}
+	p.started = true
+	schedules := append([]scheduled(nil), p.schedules...)
+	p.mu.Unlock()
+
+	taskCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	p.cancel = cancel
+	p.loops.Add(1 + len(schedules))
+	go p.dispatch(taskCtx)
+	for _, s := range schedules {
+		go p.runSchedule(taskCtx, s)
+	}
+}
+
+// Shutdown stops claiming new tasks and waits for running tasks to finish.
+// When ctx expires first, running tasks are cancelled and ctx.Err returned.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.started {
+		p.mu.Unlock()
+		return nil
+	}
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.loops.Wait()
+		p.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) dispatch(ctx context.Context) {
+	defer p.loops.Done()
+	slots := make(chan struct{}, p.cfg.Concurrency)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-p.stop:
+			return
+		}
+		task, ok, err := p.cfg.Queue.Claim(ctx, time.Now())
+		if err != nil {
+			p.report(Task{}, err)
+		}
+		if !ok {
+			<-slots
+			select {
+			case <-p.stop:
+				return
+			case <-p.wake:
+			case <-time.After(p.cfg.PollInterval):
+			}
+			continue
+		}
+		p.running.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				p.running.Done()
+			}()
+			p.execute(ctx, task)
+		}()
+	}
+}
+
+func (p *Pool) execute(ctx context.Context, task Task) {
+	p.mu.RLock()
+	handler := p.handlers[task.Name]
+	p.mu.RUnlock()
+
+	var err error
+	if handler == nil {
+		err = fmt.Errorf("%w: %s", ErrUnknownJob, task.Name)
+	} else {
+		err = runHandler(taskContext(ctx, task), handler, task)
+	}
+	if err == nil {
+		if cerr := p.cfg.Queue.Complete(ctx, task.ID); cerr != nil {
+			p.report(task, cerr)
+		}
+		return
+	}
+	p.report(task, err)
+	if handler == nil || task.Attempts >= p.cfg.MaxAttempts {
+		if ferr := p.cfg.Queue.Fail(ctx, task.ID, err); ferr != nil {
+			p.report(task, ferr)
+		}
+		return
+	}
+	retryAt := time.Now().Add(p.cfg.Backoff(task.Attempts))
+	if rerr := p.cfg.Queue.Retry(ctx, task.ID, retryAt, err); rerr != nil {
+		p.report(task, rerr)
+	}
+}
+
+func (p *Pool) runSchedule(ctx context.Context, s scheduled) {
+	defer p.loops.Done()
+	for {
+		next := s.schedule.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-p.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if _, err := p.Enqueue(ctx, s.name, s.payload, At(next)); err != nil {
+			p.report(Task{Name: s.name}, err)
+		}
+	}
+}
+
+func (p *Pool) report(task Task, err error) {
+	if p.cfg.OnError != nil {
+		p.cfg.OnError(task, err)
+	}
+}
+
+func runHandler(ctx context.Context, handler Handler, task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job %s panicked: %v", task.Name, r)
+		}
+	}()
+	return handler(ctx, task)
+}
+
+// taskContext restores the request ID and tenant captured at enqueue time.
+func taskContext(ctx context.Context, task Task) context.Context {
+	if task.RequestID != "" {
+		ctx = requestid.WithID(ctx, task.RequestID)
+	}
+	if task.TenantID != "" {
+		ctx = tenancy.WithTenant(ctx, tenancy.Tenant{ID: task.TenantID})
+	}
+	return ctx
+}
+
+func encodePayload(payload any) ([]byte, error) {
+	switch v := payload.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case json.RawMessage:
+		return v, nil
+	default:
+		body, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("encode job payload: %w", err)
+		}
+		return body, nil
+	}
+}
@@ -0,0 +1,98 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/jobs"
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
+	"github.com/aatuh/pureapi-framework/tenancy"
+)
+
+func TestPoolRunsTasksWithPropagatedContext(t *testing.T) {
+	pool := jobs.NewPool(jobs.Config{PollInterval: 10 * time.Millisecond})
+	type email struct {
+		To string `json:"to"`
+	}
+	var mu sync.Mutex
+	var got email
+	var reqID, tenantID string
+	done := make(chan struct{})
+	pool.Register("send_email", func(ctx context.Context, task jobs.Task) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := task.Decode(&got); err != nil {
+			return err
+		}
+		reqID = requestid.FromContext(ctx)
+		tenantID = tenancy.IDFromContext(ctx)
+		close(done)
+		return nil
+	})
+
+	ctx := requestid.WithID(context.Background(), "req-1")
+	ctx = tenancy.WithTenant(ctx, tenancy.Tenant{ID: "acme"})
+	if _, err := pool.Enqueue(ctx, "send_email", email{To: "a@example.com"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	pool.Start(context.Background())
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("task did not run")
+	}
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if got.To != "a@example.com" || reqID != "req-1" || tenantID != "acme" {
+		t.Fatalf("unexpected task state: %+v %q %q", got, reqID, tenantID)
+	}
+}
+
+func TestPoolRetriesThenFails(t *testing.T) {
+	queue := jobs.NewMemoryQueue()
+	pool := jobs.NewPool(jobs.Config{
+		Queue:        queue,
+		PollInterval: 5 * time.Millisecond,
+		MaxAttempts:  2,
+		Backoff:      func(int) time.Duration { return 0 },
+	})
+	var mu sync.Mutex
+	attempts := 0
+	pool.Register("flaky", func(context.Context, jobs.Task) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		return errors.New("boom")
+	})
+	if _, err := pool.Enqueue(context.Background(), "flaky", nil); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	pool.Start(context.Background())
+	deadline := time.Now().Add(time.Second)
+	for len(queue.Failed()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	_ = pool.Shutdown(context.Background())
+	failed := queue.Failed()
+	if len(failed) != 1 || failed[0].Attempts != 2 || failed[0].LastError != "boom" {
+		t.Fatalf("expected task to fail after 2 attempts, got %+v", failed)
+	}
+}
+
+func TestMemoryQueueHonoursRunAt(t *testing.T) {
+	queue := jobs.NewMemoryQueue()
+	now := time.Now()
+	_, _ = queue.Enqueue(context.Background(), jobs.Task{Name: "later", RunAt: now.Add(time.Hour)})
+	if _, ok, _ := queue.Claim(context.Background(), now); ok {
+		t.Fatalf("claimed task before its run time")
+	}
+	if task, ok, _ := queue.Claim(context.Background(), now.Add(2*time.Hour)); !ok || task.Name != "later" {
+		t.Fatalf("expected due task, got %+v %v", task, ok)
+	}
+}
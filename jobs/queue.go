@@ -0,0 +1,146 @@
+package jobs
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Task is a unit of queued work.
+type Task struct {
+	ID        string
+	Name      string
+	Payload   []byte
+	RunAt     time.Time
+	Attempts  int
+	LastError string
+	// RequestID and TenantID are captured from the enqueuing context and
+	// restored when the task runs.
+	RequestID string
+	TenantID  string
+}
+
+// Queue stores tasks until workers claim them. SQL implementations should
+// claim rows with SELECT ... FOR UPDATE SKIP LOCKED where supported.
+type Queue interface {
+	Enqueue(ctx context.Context, task Task) (string, error)
+	// Claim returns a task due at or before now, or ok=false when none is.
+	Claim(ctx context.Context, now time.Time) (task Task, ok bool, err error)
+	Complete(ctx context.Context, id string) error
+	// Retry releases the task to run again at runAt.
+	Retry(ctx context.Context, id string, runAt time.Time, cause error) error
+	// Fail moves the task out of the queue after its final attempt.
+	Fail(ctx context.Context, id string, cause error) error
+}
+
+// MemoryQueue is an in-process Queue.
+type MemoryQueue struct {
+	mu      sync.Mutex
+	seq     uint64
+	ready   map[string]Task
+	claimed map[string]Task
+	failed  []Task
+}
+
+// NewMemoryQueue creates an empty MemoryQueue.
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{ready: make(map[string]Task), claimed: make(map[string]Task)}
+}
+
+// Enqueue stores task and returns its ID.
+func (q *MemoryQueue) Enqueue(_ context.Context, task Task) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.seq++
+	if task.ID == "" {
+		task.ID = strconv.FormatUint(q.seq, 10)
+	}
+	if task.RunAt.IsZero() {
+		task.RunAt = time.Now()
+	}
+	q.ready[task.ID] = task
+	return task.ID, nil
+}
+
+// Claim returns the earliest due task.
+func (q *MemoryQueue) Claim(_ context.Context, now time.Time) (Task, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	due := make([]Task, 0, len(q.ready))
+	for _, task := range q.ready {
+		if !task.RunAt.After(now) {
+			due = append(due, task)
+		}
+	}
+	if len(due) == 0 {
+		return Task{}, false, nil
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].RunAt.Equal(due[j].RunAt) {
+			return due[i].RunAt.Before(due[j].RunAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	task := due[0]
+	task.Attempts++
+	delete(q.ready, task.ID)
+	q.claimed[task.ID] = task
+	return task, true, nil
+}
+
+// Complete drops a finished task.
+func (q *MemoryQueue) Complete(_ context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.claimed, id)
+	return nil
+}
+
+// Retry requeues a claimed task.
+func (q *MemoryQueue) Retry(_ context.Context, id string, runAt time.Time, cause error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	task, ok := q.claimed[id]
+	if !ok {
+		return nil
+	}
+	delete(q.claimed, id)
+	task.RunAt = runAt
+	if cause != nil {
+		task.LastError = cause.Error()
+	}
+	q.ready[id] = task
+	return nil
+}
+
+// Fail records a task that exhausted its attempts.
+func (q *MemoryQueue) Fail(_ context.Context, id string, cause error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	task, ok := q.claimed[id]
+	if !ok {
+		return nil
+	}
+	delete(q.claimed, id)
+	if cause != nil {
+		task.LastError = cause.Error()
+	}
+	q.failed = append(q.failed, task)
+	return nil
+}
+
+// Len reports the number of tasks waiting to run.
+func (q *MemoryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.ready)
+}
+
+// Failed returns tasks that exhausted their attempts.
+func (q *MemoryQueue) Failed() []Task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]Task(nil), q.failed...)
+}
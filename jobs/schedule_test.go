@@ -0,0 +1,40 @@
+package jobs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/jobs"
+)
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2024, time.January, 1, 10, 7, 30, 0, time.UTC) // Monday
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{"30 8 * * 1-5", time.Date(2024, 1, 2, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1 3 *", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, 1, 7, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		schedule, err := jobs.Cron(tc.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.expr, err)
+		}
+		if got := schedule.Next(base); !got.Equal(tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.expr, tc.want, got)
+		}
+	}
+}
+
+func TestCronRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := jobs.Cron(expr); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+}
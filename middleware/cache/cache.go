@@ -0,0 +1,277 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config controls the response cache middleware.
+type Config struct {
+	// TTL bounds how long entries are served. Defaults to one minute.
+	TTL time.Duration
+	// Store persists entries. Defaults to an in-memory LRU.
+	Store Store
+	// Vary lists request headers that partition the cache key.
+	Vary []string
+	// KeyFunc overrides the derived cache key.
+	KeyFunc func(r *http.Request) string
+	// CacheControl is emitted on cacheable responses. Defaults to
+	// "public, max-age=<TTL seconds>", or "private, max-age=<TTL seconds>"
+	// with AllowCredentials.
+	CacheControl string
+	// AllowCredentials caches requests carrying Authorization or Cookie
+	// headers, which are otherwise passed through uncached. Both headers
+	// then partition the cache key.
+	AllowCredentials bool
+}
+
+const defaultTTL = time.Minute
+
+// Middleware caches successful GET and HEAD responses and answers conditional
+// requests with 304 Not Modified when the ETag matches.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	store := cfg.Store
+	if store == nil {
+		store = NewLRU(0)
+	}
+	cacheControl := cfg.CacheControl
+	if cacheControl == "" {
+		scope := "public"
+		if cfg.AllowCredentials {
+			scope = "private"
+		}
+		cacheControl = scope + ", max-age=" + strconv.Itoa(int(ttl.Seconds()))
+	}
+	vary := make([]string, 0, len(cfg.Vary)+2)
+	for _, h := range cfg.Vary {
+		vary = append(vary, http.CanonicalHeaderKey(h))
+	}
+	if cfg.AllowCredentials {
+		vary = append(vary, "Authorization", "Cookie")
+	}
+	keyFunc := cfg.KeyFunc
+	if keyFunc == nil {
+		keyFunc = func(r *http.Request) string { return Key(r, vary) }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			directives := r.Header.Get("Cache-Control")
+			if hasDirective(directives, "no-store") || !cfg.AllowCredentials && hasCredentials(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := r.Context()
+			key := keyFunc(r)
+
+			if !hasDirective(directives, "no-cache") {
+				if entry, ok := store.Get(ctx, key); ok {
+					writeEntry(w, r, entry, "HIT", time.Now())
+					return
+				}
+			}
+
+			rec := &recorder{header: make(http.Header)}
+			next.ServeHTTP(rec, r)
+			entry := Entry{
+				Status: rec.statusCode(),
+				Header: rec.header,
+				Body:   rec.body.Bytes(),
+			}
+			if cacheable(entry) {
+				now := time.Now()
+				entry.ETag = entry.Header.Get("ETag")
+				if entry.ETag == "" {
+					entry.ETag = computeETag(entry.Body)
+					entry.Header.Set("ETag", entry.ETag)
+				}
+				if entry.Header.Get("Cache-Control") == "" {
+					entry.Header.Set("Cache-Control", cacheControl)
+				}
+				mergeVary(entry.Header, append(append([]string{}, keyedHeaders...), vary...))
+				entry.StoredAt = now
+				entry.Expires = now.Add(ttl)
+				store.Set(ctx, key, entry)
+				writeEntry(w, r, entry, "MISS", now)
+				return
+			}
+			writeEntry(w, r, entry, "", time.Time{})
+		})
+	}
+}
+
+// keyedHeaders always partition the cache key, since they select the
+// representation, e.g. a compressed body.
+var keyedHeaders = []string{"Accept", "Accept-Encoding"}
+
+// Key derives a cache key from method, path, sorted query, the Accept and
+// Accept-Encoding headers, and vary headers.
+func Key(r *http.Request, vary []string) string {
+	var b strings.Builder
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	b.WriteString(method)
+	b.WriteByte(' ')
+	b.WriteString(r.URL.Path)
+	if query := r.URL.Query(); len(query) > 0 {
+		b.WriteByte('?')
+		b.WriteString(sortedQuery(query))
+	}
+	for _, h := range append(append([]string{}, keyedHeaders...), vary...) {
+		b.WriteString("|")
+		b.WriteString(h)
+		b.WriteString("=")
+		b.WriteString(strings.Join(r.Header.Values(h), ","))
+	}
+	return b.String()
+}
+
+func sortedQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// hasCredentials reports whether r carries credentials that may select a
+// per-user response.
+func hasCredentials(r *http.Request) bool {
+	return r.Header.Get("Authorization") != "" || r.Header.Get("Cookie") != ""
+}
+
+// mergeVary adds headers to the Vary header of h, keeping the values already
+// set.
+func mergeVary(h http.Header, headers []string) {
+	seen := map[string]bool{}
+	var merged []string
+	for _, value := range h.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name != "" && !seen[name] {
+				seen[name] = true
+				merged = append(merged, name)
+			}
+		}
+	}
+	for _, name := range headers {
+		if !seen[name] && !seen["*"] {
+			seen[name] = true
+			merged = append(merged, name)
+		}
+	}
+	h.Set("Vary", strings.Join(merged, ", "))
+}
+
+func cacheable(entry Entry) bool {
+	if entry.Status != http.StatusOK || entry.Header.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := entry.Header.Get("Cache-Control")
+	return !hasDirective(cc, "no-store") && !hasDirective(cc, "private")
+}
+
+func writeEntry(w http.ResponseWriter, r *http.Request, entry Entry, status string, now time.Time) {
+	header := w.Header()
+	for k, values := range entry.Header {
+		header[k] = append([]string(nil), values...)
+	}
+	if status != "" {
+		header.Set("X-Cache", status)
+	}
+	if status == "HIT" && !entry.StoredAt.IsZero() {
+		header.Set("Age", strconv.Itoa(int(now.Sub(entry.StoredAt).Seconds())))
+	}
+	if entry.ETag != "" && etagMatches(r.Header.Get("If-None-Match"), entry.ETag) {
+		header.Del("Content-Length")
+		header.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(entry.Status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(entry.Body)
+	}
+}
+
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("%q", hex.EncodeToString(sum[:16]))
+}
+
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDirective(header, directive string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(name, directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// recorder buffers the downstream response so it can be stored before writing.
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header { return r.header }
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(p)
+}
+
+func (r *recorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
@@ -0,0 +1,178 @@
+package cache_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/middleware/cache"
+)
+
+func TestMiddlewareCachesAndRevalidates(t *testing.T) {
+	calls := 0
+	h := cache.Middleware(cache.Config{TTL: time.Minute, Vary: []string{"Accept-Language"}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"lang":"` + r.Header.Get("Accept-Language") + `"}`))
+		}),
+	)
+
+	do := func(lang, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/items?b=2&a=1", nil)
+		req.Header.Set("Accept-Language", lang)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := do("en", "")
+	if first.Header().Get("X-Cache") != "MISS" || first.Header().Get("ETag") == "" {
+		t.Fatalf("expected cache miss with ETag, got %v", first.Header())
+	}
+	if first.Header().Get("Cache-Control") != "public, max-age=60" {
+		t.Fatalf("unexpected cache control: %s", first.Header().Get("Cache-Control"))
+	}
+
+	second := do("en", "")
+	if second.Header().Get("X-Cache") != "HIT" || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected cache hit with same body")
+	}
+	if calls != 1 {
+		t.Fatalf("expected single upstream call, got %d", calls)
+	}
+
+	notModified := do("en", first.Header().Get("ETag"))
+	if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+		t.Fatalf("expected 304 without body, got %d", notModified.Code)
+	}
+
+	do("fi", "")
+	if calls != 2 {
+		t.Fatalf("expected vary header to partition cache, got %d calls", calls)
+	}
+}
+
+func TestMiddlewareSkipsUncacheableResponses(t *testing.T) {
+	calls := 0
+	h := cache.Middleware(cache.Config{})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if r.URL.Path == "/private" {
+				w.Header().Set("Cache-Control", "private")
+			}
+			if r.URL.Path == "/missing" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+
+	for _, path := range []string{"/private", "/private", "/missing", "/missing"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	post := httptest.NewRequest(http.MethodPost, "/items", nil)
+	h.ServeHTTP(httptest.NewRecorder(), post)
+	h.ServeHTTP(httptest.NewRecorder(), post)
+
+	if calls != 6 {
+		t.Fatalf("expected every request to reach handler, got %d", calls)
+	}
+}
+
+func TestLRUEvictsAndExpires(t *testing.T) {
+	ctx := context.Background()
+	lru := cache.NewLRU(2)
+	lru.Set(ctx, "a", cache.Entry{})
+	lru.Set(ctx, "b", cache.Entry{})
+	lru.Get(ctx, "a")
+	lru.Set(ctx, "c", cache.Entry{})
+	if _, ok := lru.Get(ctx, "b"); ok {
+		t.Fatalf("expected least recently used entry to be evicted")
+	}
+	lru.Set(ctx, "d", cache.Entry{Expires: time.Now().Add(-time.Second)})
+	if _, ok := lru.Get(ctx, "d"); ok {
+		t.Fatalf("expected expired entry to miss")
+	}
+	if lru.Len() != 1 {
+		t.Fatalf("expected expired entry to be dropped, got %d entries", lru.Len())
+	}
+}
+
+func TestMiddlewareKeysByRepresentation(t *testing.T) {
+	calls := 0
+	h := cache.Middleware(cache.Config{Vary: []string{"Accept-Language"}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Vary", "X-Tenant")
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(r.Header.Get("Accept-Encoding")))
+		}),
+	)
+	do := func(encoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	gzip := do("gzip")
+	plain := do("")
+	if calls != 2 || plain.Header().Get("X-Cache") != "MISS" || plain.Body.String() != "" {
+		t.Fatalf("expected encodings cached apart, got %d calls and %q", calls, plain.Body.String())
+	}
+	if got := gzip.Header().Get("Vary"); got != "X-Tenant, Accept, Accept-Encoding, Accept-Language" {
+		t.Fatalf("expected merged Vary header, got %q", got)
+	}
+	if got := gzip.Header().Get("ETag"); got != `"v1"` {
+		t.Fatalf("expected handler ETag kept, got %q", got)
+	}
+}
+
+func TestMiddlewareSkipsCredentialsAndCookies(t *testing.T) {
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "x"})
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	})
+	h := cache.Middleware(cache.Config{})(next)
+	authed := func(h http.Handler, path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := authed(h, "/me", "Bearer a"); rec.Header().Get("X-Cache") != "" {
+			t.Fatalf("expected credentialed request to bypass the cache, got %v", rec.Header())
+		}
+		authed(h, "/login", "")
+	}
+	if calls != 4 {
+		t.Fatalf("expected no cached credentialed or Set-Cookie responses, got %d calls", calls)
+	}
+
+	calls = 0
+	h = cache.Middleware(cache.Config{AllowCredentials: true})(next)
+	authed(h, "/me", "Bearer a")
+	second := authed(h, "/me", "Bearer a")
+	other := authed(h, "/me", "Bearer b")
+	if calls != 2 || second.Header().Get("X-Cache") != "HIT" || other.Body.String() != "Bearer b" {
+		t.Fatalf("expected opted-in caching keyed by credentials, got %d calls", calls)
+	}
+	if cc := second.Header().Get("Cache-Control"); cc != "private, max-age=60" {
+		t.Fatalf("expected private cache control, got %q", cc)
+	}
+}
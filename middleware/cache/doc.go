@@ -0,0 +1,2 @@
+// Package cache provides a response caching middleware for idempotent endpoints.
+package cache
@@ -0,0 +1,325 @@
+package compress
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EncoderFunc creates a compressing writer for an encoding at the given level.
+type EncoderFunc func(w io.Writer, level int) (io.WriteCloser, error)
+
+// Config controls response compression.
+type Config struct {
+	// Level is passed to the encoder. Zero selects the encoder default.
+	Level int
+	// MinSize is the smallest body, in bytes, worth compressing. Defaults to 1024.
+	MinSize int
+	// SkipContentTypes lists media types or "type/" prefixes that are never
+	// compressed. Defaults to already-compressed and streaming types.
+	SkipContentTypes []string
+	// Encoders registers additional encodings such as "br", keyed by token.
+	Encoders map[string]EncoderFunc
+	// Preference orders encodings when the client weighs them equally.
+	// Defaults to the names in Encoders, alphabetically, then gzip and
+	// deflate.
+	Preference []string
+}
+
+const defaultMinSize = 1024
+
+var defaultSkipContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zstd",
+	"application/x-7z-compressed",
+	"text/event-stream",
+}
+
+var defaultPreference = []string{"gzip", "deflate"}
+
+// Middleware compresses downstream responses.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cw := NewWriter(w, r, cfg)
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// Writer buffers the start of a response to decide whether to compress it. It
+// must be closed once the handler finishes.
+type Writer struct {
+	http.ResponseWriter
+	encoding   string
+	encoder    EncoderFunc
+	level      int
+	minSize    int
+	skip       []string
+	headOnly   bool
+	status     int
+	buf        []byte
+	enc        io.WriteCloser
+	decided    bool
+	compressed bool
+}
+
+// NewWriter wraps w, negotiating an encoding from the request's Accept-Encoding.
+// When nothing acceptable is available the writer passes bytes through.
+func NewWriter(w http.ResponseWriter, r *http.Request, cfg Config) *Writer {
+	encoders := map[string]EncoderFunc{
+		"gzip":    newGzip,
+		"deflate": newDeflate,
+	}
+	for name, fn := range cfg.Encoders {
+		if fn != nil {
+			encoders[strings.ToLower(name)] = fn
+		}
+	}
+	preference := cfg.Preference
+	if len(preference) == 0 {
+		for name := range encoders {
+			if !slices.Contains(defaultPreference, name) {
+				preference = append(preference, name)
+			}
+		}
+		slices.Sort(preference)
+		preference = append(preference, defaultPreference...)
+	}
+	minSize := cfg.MinSize
+	if minSize <= 0 {
+		minSize = defaultMinSize
+	}
+	skip := cfg.SkipContentTypes
+	if skip == nil {
+		skip = defaultSkipContentTypes
+	}
+	cw := &Writer{ResponseWriter: w, level: cfg.Level, minSize: minSize, skip: skip}
+	if r != nil {
+		cw.headOnly = r.Method == http.MethodHead
+		cw.encoding = Negotiate(r.Header.Get("Accept-Encoding"), preference, encoders)
+		cw.encoder = encoders[cw.encoding]
+	}
+	if cw.encoding != "" {
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+	return cw
+}
+
+// Encoding returns the negotiated encoding, or "" when passing through.
+func (cw *Writer) Encoding() string {
+	return cw.encoding
+}
+
+// Compressed reports whether the response body is being compressed.
+func (cw *Writer) Compressed() bool {
+	return cw.compressed
+}
+
+// WriteHeader records the status; headers are sent with the first body bytes.
+func (cw *Writer) WriteHeader(status int) {
+	if cw.decided || status < http.StatusOK {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if cw.status != 0 {
+		return
+	}
+	cw.status = status
+	if cw.encoding == "" || !bodyAllowed(status) {
+		_ = cw.decide(false)
+	}
+}
+
+// Write buffers until MinSize bytes are available, then streams through the
+// encoder if the response qualifies.
+func (cw *Writer) Write(p []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if cw.encoding == "" && !cw.decided {
+		_ = cw.decide(false)
+	}
+	if cw.decided {
+		return cw.writeThrough(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends buffered bytes and flushes the encoder and underlying writer.
+func (cw *Writer) Flush() {
+	if !cw.decided {
+		_ = cw.decide(len(cw.buf) >= cw.minSize)
+	}
+	if flusher, ok := cw.enc.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close finalizes the response, writing any buffered bytes.
+func (cw *Writer) Close() error {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buf) == 0 {
+			return nil
+		}
+		if err := cw.decide(len(cw.buf) >= cw.minSize); err != nil {
+			return err
+		}
+	}
+	if cw.enc != nil {
+		err := cw.enc.Close()
+		cw.enc = nil
+		return err
+	}
+	return nil
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (cw *Writer) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *Writer) decide(largeEnough bool) error {
+	cw.decided = true
+	header := cw.Header()
+	if largeEnough && cw.eligible(header) {
+		enc, err := cw.encoder(cw.ResponseWriter, cw.level)
+		if err == nil {
+			cw.enc = enc
+			cw.compressed = true
+			header.Set("Content-Encoding", cw.encoding)
+			header.Del("Content-Length")
+		}
+	}
+	if cw.status != 0 {
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	buf := cw.buf
+	cw.buf = nil
+	_, err := cw.writeThrough(buf)
+	return err
+}
+
+func (cw *Writer) writeThrough(p []byte) (int, error) {
+	if cw.headOnly {
+		return len(p), nil
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+func (cw *Writer) eligible(header http.Header) bool {
+	if cw.encoder == nil || !bodyAllowed(cw.status) {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(cw.buf)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	for _, skip := range cw.skip {
+		skip = strings.ToLower(skip)
+		if mediaType == skip || (strings.HasSuffix(skip, "/") && strings.HasPrefix(mediaType, skip)) {
+			return false
+		}
+	}
+	return true
+}
+
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// Negotiate picks the encoding with the highest q-value in the Accept-Encoding
+// header, breaking ties by preference. It returns "" when none is acceptable.
+func Negotiate(acceptEncoding string, preference []string, available map[string]EncoderFunc) string {
+	if strings.TrimSpace(acceptEncoding) == "" {
+		return ""
+	}
+	weights := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		segments := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(segments[0]))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range segments[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.ToLower(strings.TrimSpace(key)) != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		if name == "*" {
+			wildcard = q
+			continue
+		}
+		weights[name] = q
+	}
+	best, bestQ := "", 0.0
+	for _, name := range preference {
+		if _, ok := available[name]; !ok {
+			continue
+		}
+		q, ok := weights[name]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+func newGzip(w io.Writer, level int) (io.WriteCloser, error) {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+// newDeflate writes the zlib format: HTTP's "deflate" coding wraps the raw
+// DEFLATE stream in a zlib header and checksum (RFC 9110, section 8.4.1.2).
+func newDeflate(w io.Writer, level int) (io.WriteCloser, error) {
+	if level == 0 {
+		level = zlib.DefaultCompression
+	}
+	return zlib.NewWriterLevel(w, level)
+}
@@ -0,0 +1,109 @@
+package compress_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/middleware/compress"
+)
+
+func serve(cfg compress.Config, contentType, body, acceptEncoding string) *httptest.ResponseRecorder {
+	h := compress.Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", "999")
+		_, _ = io.WriteString(w, body)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareCompressesLargeBodies(t *testing.T) {
+	body := strings.Repeat("a", 2048)
+	rec := serve(compress.Config{}, "text/plain", body, "gzip")
+	if rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("Content-Length") != "" {
+		t.Fatalf("unexpected headers: %v", rec.Header())
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected Vary header, got %q", rec.Header().Get("Vary"))
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	decoded, _ := io.ReadAll(zr)
+	if string(decoded) != body {
+		t.Fatalf("unexpected decoded body length %d", len(decoded))
+	}
+}
+
+func TestMiddlewareDeflateUsesZlibFormat(t *testing.T) {
+	body := strings.Repeat("a", 2048)
+	rec := serve(compress.Config{}, "text/plain", body, "deflate")
+	if rec.Header().Get("Content-Encoding") != "deflate" {
+		t.Fatalf("unexpected headers: %v", rec.Header())
+	}
+	zr, err := zlib.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("zlib reader: %v", err)
+	}
+	decoded, _ := io.ReadAll(zr)
+	if string(decoded) != body {
+		t.Fatalf("unexpected decoded body length %d", len(decoded))
+	}
+}
+
+func TestMiddlewarePrefersRegisteredEncoders(t *testing.T) {
+	body := strings.Repeat("a", 2048)
+	if rec := serve(compress.Config{}, "text/plain", body, "br, gzip"); rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip without a br encoder, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	cfg := compress.Config{Encoders: map[string]compress.EncoderFunc{
+		"br": func(w io.Writer, _ int) (io.WriteCloser, error) { return nopCloser{w}, nil },
+	}}
+	if rec := serve(cfg, "text/plain", body, "br, gzip"); rec.Header().Get("Content-Encoding") != "br" {
+		t.Fatalf("expected registered br encoder, got %q", rec.Header().Get("Content-Encoding"))
+	}
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func TestMiddlewareSkipsSmallAndCompressedBodies(t *testing.T) {
+	if rec := serve(compress.Config{}, "text/plain", "tiny", "gzip"); rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != "tiny" {
+		t.Fatalf("expected small body to pass through")
+	}
+	large := strings.Repeat("a", 2048)
+	if rec := serve(compress.Config{}, "image/png", large, "gzip"); rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected image content to pass through")
+	}
+	if rec := serve(compress.Config{}, "text/plain", large, "gzip;q=0, identity"); rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected refused encoding to pass through")
+	}
+}
+
+func TestNegotiateHonoursQualityAndPreference(t *testing.T) {
+	available := map[string]compress.EncoderFunc{"gzip": nil, "deflate": nil, "br": nil}
+	preference := []string{"br", "gzip", "deflate"}
+	cases := map[string]string{
+		"gzip, deflate":             "gzip",
+		"deflate, gzip;q=0.5":       "deflate",
+		"*":                         "br",
+		"*, br;q=0":                 "gzip",
+		"identity":                  "",
+		"gzip;q=0.8, deflate;q=0.8": "gzip",
+	}
+	for header, want := range cases {
+		if got := compress.Negotiate(header, preference, available); got != want {
+			t.Fatalf("Negotiate(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package compress provides Accept-Encoding negotiated response compression.
+package compress
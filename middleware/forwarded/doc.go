@@ -0,0 +1,2 @@
+// Package forwarded resolves the real client address, scheme, and host behind trusted proxies.
+package forwarded
@@ -0,0 +1,255 @@
+package forwarded
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Config controls which proxies are trusted to report the client.
+type Config struct {
+	// TrustedProxies lists proxy addresses or CIDR ranges, e.g. "10.0.0.0/8".
+	// Forwarded headers from any other peer are ignored.
+	TrustedProxies []string
+}
+
+// Info describes the client as seen through trusted proxies.
+type Info struct {
+	// ClientIP is the nearest address not belonging to a trusted proxy.
+	ClientIP string
+	// Scheme is "http" or "https".
+	Scheme string
+	// Host is the host the client addressed.
+	Host string
+}
+
+type contextKey struct{}
+
+// Resolver evaluates forwarded headers against a trusted proxy list.
+type Resolver struct {
+	trusted []netip.Prefix
+}
+
+// NewResolver parses cfg.TrustedProxies.
+func NewResolver(cfg Config) (*Resolver, error) {
+	r := &Resolver{}
+	for _, raw := range cfg.TrustedProxies {
+		raw = strings.TrimSpace(raw)
+		if prefix, err := netip.ParsePrefix(raw); err == nil {
+			r.trusted = append(r.trusted, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(raw)
+		if err != nil {
+			return nil, fmt.Errorf("forwarded: invalid trusted proxy %q", raw)
+		}
+		r.trusted = append(r.trusted, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return r, nil
+}
+
+// Middleware resolves Info for every request and stores it in the context.
+// It panics when cfg lists an invalid proxy.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	resolver, err := NewResolver(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), contextKey{}, resolver.Resolve(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Resolve walks the Forwarded (or X-Forwarded-For) chain from the nearest hop,
+// skipping trusted proxies. Headers are ignored unless the peer itself is
+// trusted. Scheme and host come from the entry the outermost trusted proxy
+// added, never from entries further left, which the client controls.
+func (res *Resolver) Resolve(r *http.Request) Info {
+	info := direct(r)
+	if !res.trustedAddr(info.ClientIP) {
+		return info
+	}
+	var proto, host string
+	if elements := forwardedHeader(r.Header.Values("Forwarded")); len(elements) > 0 {
+		stop := res.walk(&info, len(elements), func(i int) string { return elements[i].hop })
+		// Every element from stop on was added by a trusted proxy.
+		for _, element := range elements[stop:] {
+			if proto == "" {
+				proto = element.proto
+			}
+			if host == "" {
+				host = element.host
+			}
+		}
+	} else {
+		hops := splitList(r.Header.Values("X-Forwarded-For"))
+		stop := res.walk(&info, len(hops), func(i int) string { return hops[i] })
+		fromRight := len(hops) - 1 - stop
+		proto = fromEnd(r.Header.Values("X-Forwarded-Proto"), fromRight)
+		host = fromEnd(r.Header.Values("X-Forwarded-Host"), fromRight)
+	}
+	if proto = strings.ToLower(proto); proto == "http" || proto == "https" {
+		info.Scheme = proto
+	}
+	if host != "" {
+		info.Host = host
+	}
+	return info
+}
+
+// walk sets info.ClientIP from the n hops, nearest last, skipping trusted
+// proxies. It returns the index of the hop it stopped at.
+func (res *Resolver) walk(info *Info, n int, hop func(i int) string) int {
+	i := n - 1
+	for ; i >= 0; i-- {
+		ip := hopIP(hop(i))
+		if ip == "" {
+			break
+		}
+		info.ClientIP = ip
+		if !res.trustedAddr(ip) {
+			break
+		}
+	}
+	return max(i, 0)
+}
+
+func (res *Resolver) trustedAddr(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range res.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromContext returns the Info stored by Middleware.
+func FromContext(ctx context.Context) (Info, bool) {
+	if ctx == nil {
+		return Info{}, false
+	}
+	info, ok := ctx.Value(contextKey{}).(Info)
+	return info, ok
+}
+
+// ClientIP returns the resolved client address, falling back to the host of
+// RemoteAddr when Middleware did not run.
+func ClientIP(r *http.Request) string {
+	if info, ok := FromContext(r.Context()); ok {
+		return info.ClientIP
+	}
+	return direct(r).ClientIP
+}
+
+// Scheme returns the resolved request scheme.
+func Scheme(r *http.Request) string {
+	if info, ok := FromContext(r.Context()); ok {
+		return info.Scheme
+	}
+	return direct(r).Scheme
+}
+
+// Host returns the resolved host the client addressed.
+func Host(r *http.Request) string {
+	if info, ok := FromContext(r.Context()); ok {
+		return info.Host
+	}
+	return r.Host
+}
+
+func direct(r *http.Request) Info {
+	info := Info{ClientIP: r.RemoteAddr, Scheme: "http", Host: r.Host}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		info.ClientIP = host
+	}
+	if r.TLS != nil {
+		info.Scheme = "https"
+	}
+	return info
+}
+
+// forwardedElement is one proxy's entry in an RFC 7239 Forwarded header.
+type forwardedElement struct {
+	hop   string
+	proto string
+	host  string
+}
+
+// forwardedHeader parses RFC 7239 Forwarded values into their elements,
+// outermost first.
+func forwardedHeader(values []string) []forwardedElement {
+	var elements []forwardedElement
+	for _, raw := range splitList(values) {
+		var element forwardedElement
+		for _, pair := range strings.Split(raw, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+			switch strings.ToLower(key) {
+			case "for":
+				element.hop = value
+			case "proto":
+				element.proto = value
+			case "host":
+				element.host = value
+			}
+		}
+		elements = append(elements, element)
+	}
+	return elements
+}
+
+// hopIP extracts the address from a hop such as "[2001:db8::1]:4711" or
+// "192.0.2.60:80"; obfuscated and "unknown" hops yield "".
+func hopIP(hop string) string {
+	hop = strings.TrimSpace(hop)
+	if host, _, err := net.SplitHostPort(hop); err == nil {
+		hop = host
+	}
+	hop = strings.Trim(hop, "[]")
+	addr, err := netip.ParseAddr(hop)
+	if err != nil {
+		return ""
+	}
+	return addr.Unmap().String()
+}
+
+func splitList(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// fromEnd returns the list entry i places from the right, matching the hop
+// of the same position in X-Forwarded-For. Proxies that overwrite rather
+// than append leave fewer entries; the nearest proxy's entry, the last one,
+// is used then.
+func fromEnd(values []string, i int) string {
+	list := splitList(values)
+	if len(list) == 0 {
+		return ""
+	}
+	if i >= len(list) {
+		i = 0
+	}
+	return list[len(list)-1-i]
+}
@@ -0,0 +1,102 @@
+package forwarded_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/middleware/forwarded"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver, err := forwarded.NewResolver(forwarded.Config{TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1"}})
+	if err != nil {
+		t.Fatalf("new resolver: %v", err)
+	}
+	request := func(remote string, header http.Header) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "http://internal/items", nil)
+		req.RemoteAddr = remote
+		req.Header = header
+		return req
+	}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		expect forwarded.Info
+	}{
+		{
+			name:   "untrusted peer is the client",
+			req:    request("203.0.113.9:5000", http.Header{"X-Forwarded-For": {"1.2.3.4"}, "X-Forwarded-Proto": {"https"}}),
+			expect: forwarded.Info{ClientIP: "203.0.113.9", Scheme: "http", Host: "internal"},
+		},
+		{
+			name: "x-forwarded skips trusted hops and ignores spoofed entries",
+			req: request("10.0.0.5:5000", http.Header{
+				"X-Forwarded-For":   {"6.6.6.6, 198.51.100.7", "10.1.2.3"},
+				"X-Forwarded-Proto": {"https, http"},
+				"X-Forwarded-Host":  {"api.example.com"},
+			}),
+			expect: forwarded.Info{ClientIP: "198.51.100.7", Scheme: "https", Host: "api.example.com"},
+		},
+		{
+			name: "forwarded header wins",
+			req: request("192.0.2.1:443", http.Header{
+				"Forwarded":       {`for="[2001:db8::1]:4711";proto=https;host=example.com, for=10.0.0.2`},
+				"X-Forwarded-For": {"1.1.1.1"},
+			}),
+			expect: forwarded.Info{ClientIP: "2001:db8::1", Scheme: "https", Host: "example.com"},
+		},
+		{
+			name: "client-supplied forwarded element cannot set scheme or host",
+			req: request("10.0.0.5:5000", http.Header{
+				"Forwarded": {`for=6.6.6.6;proto=http;host=evil`, `for=198.51.100.7;proto=https;host=api.example.com`},
+			}),
+			expect: forwarded.Info{ClientIP: "198.51.100.7", Scheme: "https", Host: "api.example.com"},
+		},
+		{
+			name: "client-supplied x-forwarded values are skipped by position",
+			req: request("10.0.0.5:5000", http.Header{
+				"X-Forwarded-For":   {"6.6.6.6, 198.51.100.7"},
+				"X-Forwarded-Proto": {"http, https"},
+				"X-Forwarded-Host":  {"evil, api.example.com"},
+			}),
+			expect: forwarded.Info{ClientIP: "198.51.100.7", Scheme: "https", Host: "api.example.com"},
+		},
+		{
+			name:   "obfuscated hop stops at the last known proxy",
+			req:    request("10.0.0.5:5000", http.Header{"Forwarded": {"for=unknown, for=10.0.0.9"}}),
+			expect: forwarded.Info{ClientIP: "10.0.0.9", Scheme: "http", Host: "internal"},
+		},
+	}
+	for _, tt := range tests {
+		if got := resolver.Resolve(tt.req); got != tt.expect {
+			t.Fatalf("%s: expected %+v, got %+v", tt.name, tt.expect, got)
+		}
+	}
+
+	if _, err := forwarded.NewResolver(forwarded.Config{TrustedProxies: []string{"not-an-ip"}}); err == nil {
+		t.Fatalf("expected invalid proxy to be rejected")
+	}
+}
+
+func TestMiddleware_ExposesClientInfo(t *testing.T) {
+	var ip, scheme string
+	handler := forwarded.Middleware(forwarded.Config{TrustedProxies: []string{"127.0.0.1"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, scheme = forwarded.ClientIP(r), forwarded.Scheme(r)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "127.0.0.1:9000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if ip != "198.51.100.7" || scheme != "https" {
+		t.Fatalf("unexpected client info: %q %q", ip, scheme)
+	}
+
+	plain := httptest.NewRequest(http.MethodGet, "/", nil)
+	plain.RemoteAddr = "203.0.113.9:1234"
+	if forwarded.ClientIP(plain) != "203.0.113.9" {
+		t.Fatalf("expected RemoteAddr fallback, got %q", forwarded.ClientIP(plain))
+	}
+}
@@ -0,0 +1,2 @@
+// Package loadshed caps in-flight requests and sheds the excess with 429 or 503.
+package loadshed
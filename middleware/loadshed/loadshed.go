@@ -0,0 +1,201 @@
+package loadshed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// Catalog entries rendered for shed requests, by status.
+const (
+	CatalogIDTooManyRequests = "too_many_requests"
+	CatalogIDUnavailable     = "unavailable"
+)
+
+// ErrOverloaded is matched by every error Limiter.Acquire returns.
+var ErrOverloaded = errors.New("server overloaded")
+
+// Reason tells why a request was shed.
+type Reason string
+
+const (
+	// ReasonQueueFull means every slot and queue position was taken.
+	ReasonQueueFull Reason = "queue_full"
+	// ReasonQueueTimeout means the request waited QueueTimeout for a slot.
+	ReasonQueueTimeout Reason = "queue_timeout"
+)
+
+// Config controls a Limiter.
+type Config struct {
+	// Name identifies the limiter in OnReject, e.g. "global" or a route.
+	Name string
+	// MaxInFlight is the number of requests served concurrently. Zero or
+	// negative disables the limiter.
+	MaxInFlight int
+	// MaxQueue is the number of requests that may wait for a slot once
+	// MaxInFlight is reached. Zero sheds immediately.
+	MaxQueue int
+	// QueueTimeout bounds how long a queued request waits. Zero waits until
+	// the request context ends.
+	QueueTimeout time.Duration
+	// Status is sent for shed requests: http.StatusServiceUnavailable (the
+	// default) or http.StatusTooManyRequests.
+	Status int
+	// RetryAfter is sent in the Retry-After header when positive.
+	RetryAfter time.Duration
+	// OnReject is called for every shed request, e.g. to increment a metric.
+	OnReject func(r *http.Request, name string, reason Reason)
+}
+
+// Error reports that a request was shed.
+type Error struct {
+	Name       string
+	Reason     Reason
+	Status     int
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return ErrOverloaded.Error() + ": " + string(e.Reason)
+}
+
+// Is matches ErrOverloaded.
+func (e *Error) Is(target error) bool { return target == ErrOverloaded }
+
+// CatalogID maps the error to "too_many_requests" or "unavailable" by Status.
+func (e *Error) CatalogID() string {
+	if e.Status == http.StatusTooManyRequests {
+		return CatalogIDTooManyRequests
+	}
+	return CatalogIDUnavailable
+}
+
+// Limiter is a semaphore over in-flight requests with a bounded wait queue.
+// It is safe for concurrent use.
+type Limiter struct {
+	cfg      Config
+	slots    chan struct{}
+	queued   atomic.Int64
+	rejected atomic.Uint64
+}
+
+// NewLimiter returns a Limiter for cfg, or nil when cfg.MaxInFlight is not
+// positive. A nil Limiter admits every request.
+func NewLimiter(cfg Config) *Limiter {
+	if cfg.MaxInFlight <= 0 {
+		return nil
+	}
+	if cfg.Status != http.StatusTooManyRequests {
+		cfg.Status = http.StatusServiceUnavailable
+	}
+	return &Limiter{cfg: cfg, slots: make(chan struct{}, cfg.MaxInFlight)}
+}
+
+// Acquire takes a slot for r, queueing when allowed, and returns the function
+// that releases it. It fails with an *Error when the request is shed, or with
+// the context error when r is cancelled while queued.
+func (l *Limiter) Acquire(r *http.Request) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+	if l.queued.Add(1) > int64(l.cfg.MaxQueue) {
+		l.queued.Add(-1)
+		return nil, l.reject(r, ReasonQueueFull)
+	}
+	defer l.queued.Add(-1)
+
+	ctx := r.Context()
+	if l.cfg.QueueTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.cfg.QueueTimeout)
+		defer cancel()
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-ctx.Done():
+		if err := r.Context().Err(); err != nil {
+			return nil, err
+		}
+		return nil, l.reject(r, ReasonQueueTimeout)
+	}
+}
+
+func (l *Limiter) release() { <-l.slots }
+
+func (l *Limiter) reject(r *http.Request, reason Reason) error {
+	l.rejected.Add(1)
+	if l.cfg.OnReject != nil {
+		l.cfg.OnReject(r, l.cfg.Name, reason)
+	}
+	return &Error{Name: l.cfg.Name, Reason: reason, Status: l.cfg.Status, RetryAfter: l.cfg.RetryAfter}
+}
+
+// InFlight returns the number of requests holding a slot.
+func (l *Limiter) InFlight() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.slots)
+}
+
+// Queued returns the number of requests waiting for a slot.
+func (l *Limiter) Queued() int {
+	if l == nil {
+		return 0
+	}
+	return int(l.queued.Load())
+}
+
+// Rejected returns the number of requests shed so far.
+func (l *Limiter) Rejected() uint64 {
+	if l == nil {
+		return 0
+	}
+	return l.rejected.Load()
+}
+
+// SetRetryAfter writes the Retry-After header for err when it carries one.
+func SetRetryAfter(h http.Header, err error) {
+	var shedErr *Error
+	if errors.As(err, &shedErr) && shedErr.RetryAfter > 0 {
+		seconds := int64((shedErr.RetryAfter + time.Second - 1) / time.Second)
+		h.Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+}
+
+// Middleware sheds requests beyond cfg's limits with a JSON error body and
+// Retry-After. Engine endpoints get the same behaviour, rendered through
+// their error mapper, with WithLoadShedding and WithEndpointLoadShedding.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	limiter := NewLimiter(cfg)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			release, err := limiter.Acquire(r)
+			if err != nil {
+				var shedErr *Error
+				if !errors.As(err, &shedErr) {
+					return
+				}
+				SetRetryAfter(w.Header(), err)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(shedErr.Status)
+				_ = json.NewEncoder(w).Encode(apierror.NewAPIError(shedErr.CatalogID()).WithMessage(http.StatusText(shedErr.Status)))
+				return
+			}
+			defer release()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
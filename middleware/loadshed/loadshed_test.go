@@ -0,0 +1,106 @@
+package loadshed_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/middleware/loadshed"
+)
+
+func TestMiddleware_QueuesThenSheds(t *testing.T) {
+	var mu sync.Mutex
+	var reasons []loadshed.Reason
+	entered := make(chan struct{}, 4)
+	unblock := make(chan struct{})
+	handler := loadshed.Middleware(loadshed.Config{
+		Name:         "db",
+		MaxInFlight:  1,
+		MaxQueue:     1,
+		QueueTimeout: 100 * time.Millisecond,
+		Status:       http.StatusTooManyRequests,
+		RetryAfter:   1500 * time.Millisecond,
+		OnReject: func(r *http.Request, name string, reason loadshed.Reason) {
+			mu.Lock()
+			defer mu.Unlock()
+			if name == "db" {
+				reasons = append(reasons, reason)
+			}
+		},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-unblock
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+		return rec
+	}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- serve() }()
+	<-entered
+
+	// The queued request times out; with the queue occupied, the next one
+	// is shed immediately.
+	queued := make(chan *httptest.ResponseRecorder)
+	go func() { queued <- serve() }()
+	time.Sleep(5 * time.Millisecond)
+	rec := serve()
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Fatalf("expected 429 with Retry-After, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(rec.Body.String(), `"too_many_requests"`) {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+	if rec := <-queued; rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected queued request to time out, got %d", rec.Code)
+	}
+
+	close(unblock)
+	if rec := <-first; rec.Code != http.StatusNoContent {
+		t.Fatalf("expected first request to complete, got %d", rec.Code)
+	}
+	if rec := serve(); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected slot to be released, got %d", rec.Code)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reasons) != 2 || reasons[0] != loadshed.ReasonQueueFull || reasons[1] != loadshed.ReasonQueueTimeout {
+		t.Fatalf("unexpected reject reasons: %v", reasons)
+	}
+}
+
+func TestLimiter_QueuedRequestGetsReleasedSlot(t *testing.T) {
+	limiter := loadshed.NewLimiter(loadshed.Config{MaxInFlight: 1, MaxQueue: 1})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	release, err := limiter.Acquire(req)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	done := make(chan error)
+	go func() {
+		releaseQueued, err := limiter.Acquire(req)
+		if err == nil {
+			releaseQueued()
+		}
+		done <- err
+	}()
+	for limiter.Queued() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	release()
+	if err := <-done; err != nil {
+		t.Fatalf("expected queued request to acquire, got %v", err)
+	}
+	if limiter.InFlight() != 0 || limiter.Rejected() != 0 {
+		t.Fatalf("unexpected counters: in-flight %d, rejected %d", limiter.InFlight(), limiter.Rejected())
+	}
+	if loadshed.NewLimiter(loadshed.Config{}) != nil {
+		t.Fatalf("expected nil limiter without MaxInFlight")
+	}
+}
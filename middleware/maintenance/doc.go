@@ -0,0 +1,2 @@
+// Package maintenance answers requests with 503 while the service drains for maintenance.
+package maintenance
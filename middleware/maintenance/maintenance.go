@@ -0,0 +1,133 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-framework/settings"
+)
+
+// CatalogID is the catalog entry rendered for requests rejected during
+// maintenance.
+const CatalogID = "unavailable"
+
+// ErrUnavailable is matched by every error Check returns.
+var ErrUnavailable = errors.New("service under maintenance")
+
+// DefaultExemptPaths stay reachable during maintenance so orchestrators can
+// keep probing the service.
+var DefaultExemptPaths = []string{"/health", "/healthz", "/livez", "/readyz"}
+
+// Config controls maintenance mode.
+type Config struct {
+	// Enabled turns maintenance mode on regardless of Settings.
+	Enabled bool
+	// Settings, when set, enables maintenance mode while its snapshot has
+	// Maintenance.Enabled; its RetryAfter and Message take precedence.
+	Settings *settings.Store
+	// RetryAfter is sent in the Retry-After header when positive.
+	RetryAfter time.Duration
+	// Message replaces the catalog message.
+	Message string
+	// ExemptPaths are matched exactly, or as prefixes when they end in "/".
+	// Defaults to DefaultExemptPaths.
+	ExemptPaths []string
+	// Allow lets individual requests through, e.g. operators by IP or token.
+	Allow func(r *http.Request) bool
+}
+
+// Error reports that a request was rejected because of maintenance mode.
+type Error struct {
+	RetryAfter time.Duration
+	Message    string
+}
+
+func (e *Error) Error() string { return ErrUnavailable.Error() }
+
+// Is matches ErrUnavailable.
+func (e *Error) Is(target error) bool { return target == ErrUnavailable }
+
+// CatalogID maps the error to the "unavailable" catalog entry.
+func (e *Error) CatalogID() string { return CatalogID }
+
+// WireMessage overrides the catalog message when Message is set.
+func (e *Error) WireMessage() string { return e.Message }
+
+// Check returns an *Error when maintenance mode is active and r is not exempt.
+func (cfg Config) Check(r *http.Request) error {
+	active, retryAfter, message := cfg.Enabled, cfg.RetryAfter, cfg.Message
+	if snapshot, ok := cfg.snapshot(r); ok && snapshot.Maintenance.Enabled {
+		active = true
+		if d, _ := snapshot.Maintenance.RetryAfterDuration(); d > 0 {
+			retryAfter = d
+		}
+		if snapshot.Maintenance.Message != "" {
+			message = snapshot.Maintenance.Message
+		}
+	}
+	if !active || cfg.exempt(r) {
+		return nil
+	}
+	return &Error{RetryAfter: retryAfter, Message: message}
+}
+
+// snapshot prefers the settings pinned for the request over a fresh read.
+func (cfg Config) snapshot(r *http.Request) (settings.Settings, bool) {
+	if s, ok := settings.FromContext(r.Context()); ok {
+		return s, true
+	}
+	if cfg.Settings != nil {
+		return cfg.Settings.Snapshot(), true
+	}
+	return settings.Settings{}, false
+}
+
+func (cfg Config) exempt(r *http.Request) bool {
+	paths := cfg.ExemptPaths
+	if paths == nil {
+		paths = DefaultExemptPaths
+	}
+	for _, path := range paths {
+		if r.URL.Path == path || strings.HasSuffix(path, "/") && strings.HasPrefix(r.URL.Path, path) {
+			return true
+		}
+	}
+	return cfg.Allow != nil && cfg.Allow(r)
+}
+
+// SetRetryAfter writes the Retry-After header for err when it carries one.
+func SetRetryAfter(h http.Header, err error) {
+	var maintErr *Error
+	if errors.As(err, &maintErr) && maintErr.RetryAfter > 0 {
+		seconds := int64((maintErr.RetryAfter + time.Second - 1) / time.Second)
+		h.Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+}
+
+// Middleware rejects requests with 503, Retry-After, and an "unavailable"
+// JSON error body while maintenance mode is active. Engine endpoints get the
+// same behaviour, rendered through their error mapper, with WithMaintenance.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := cfg.Check(r)
+			if err == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			message := err.(*Error).Message
+			if message == "" {
+				message = "Service temporarily unavailable"
+			}
+			SetRetryAfter(w.Header(), err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(apierror.NewAPIError(CatalogID).WithMessage(message))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package maintenance_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/middleware/maintenance"
+	"github.com/aatuh/pureapi-framework/settings"
+)
+
+func TestMiddleware_RejectsUntilDisabled(t *testing.T) {
+	store, _ := settings.NewStore(settings.Settings{})
+	handler := maintenance.Middleware(maintenance.Config{
+		Settings:   store,
+		RetryAfter: 90 * time.Second,
+		Allow:      func(r *http.Request) bool { return r.Header.Get("X-Operator") == "yes" },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve("/items", nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected pass-through, got %d", rec.Code)
+	}
+
+	_ = store.Update(func(s *settings.Settings) {
+		s.Maintenance = settings.Maintenance{Enabled: true, Message: "Back soon"}
+	})
+	rec := serve("/items", nil)
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "90" {
+		t.Fatalf("expected 503 with Retry-After, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"unavailable"`) || !strings.Contains(body, "Back soon") {
+		t.Fatalf("unexpected body: %s", body)
+	}
+	if rec := serve("/healthz", nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected health check to stay reachable, got %d", rec.Code)
+	}
+	if rec := serve("/items", http.Header{"X-Operator": {"yes"}}); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected allow-listed request to pass, got %d", rec.Code)
+	}
+
+	_ = store.Update(func(s *settings.Settings) { s.Maintenance.RetryAfter = "1500ms" })
+	if rec := serve("/items", nil); rec.Header().Get("Retry-After") != "2" {
+		t.Fatalf("expected settings retry_after rounded up, got %q", rec.Header().Get("Retry-After"))
+	}
+}
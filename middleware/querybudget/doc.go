@@ -0,0 +1,3 @@
+// Package querybudget limits the number and cumulative time of database
+// queries a request may run, to catch N+1 patterns in production.
+package querybudget
@@ -0,0 +1,169 @@
+package querybudget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	obslog "github.com/aatuh/pureapi-framework/obs/log"
+)
+
+// CatalogID is the catalog entry rendered for requests rejected for
+// exceeding their query budget.
+const CatalogID = "query_budget_exceeded"
+
+// ErrExceeded is matched by every error Record returns.
+var ErrExceeded = errors.New("query budget exceeded")
+
+// Mode selects what happens once a budget is exceeded.
+type Mode int
+
+const (
+	// ModeLog reports the overrun and lets the request continue.
+	ModeLog Mode = iota
+	// ModeReject fails the query that overran the budget and every query
+	// after it.
+	ModeReject
+)
+
+// Config controls a query budget.
+type Config struct {
+	// Name identifies the budget in reports, e.g. "global" or a route.
+	Name string
+	// MaxQueries caps the number of queries per request. Zero is unlimited.
+	MaxQueries int
+	// MaxDuration caps the cumulative query time per request. Zero is
+	// unlimited.
+	MaxDuration time.Duration
+	// Mode selects logging (the default) or rejection.
+	Mode Mode
+	// OnExceeded is called once per request when the budget is first
+	// exceeded, e.g. to increment a metric. Defaults to a warning on the
+	// context logger.
+	OnExceeded func(ctx context.Context, err *Error)
+}
+
+// Usage is the query activity recorded for a request.
+type Usage struct {
+	Queries  int
+	Duration time.Duration
+}
+
+// Error reports that a request exceeded its query budget.
+type Error struct {
+	Name        string
+	Usage       Usage
+	MaxQueries  int
+	MaxDuration time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %d queries in %s", ErrExceeded.Error(), e.Usage.Queries, e.Usage.Duration)
+}
+
+// Is matches ErrExceeded.
+func (e *Error) Is(target error) bool { return target == ErrExceeded }
+
+// CatalogID maps the error to the "query_budget_exceeded" catalog entry.
+func (e *Error) CatalogID() string { return CatalogID }
+
+// Budget tracks the queries of one request. It is safe for concurrent use;
+// a nil Budget records nothing.
+type Budget struct {
+	cfg Config
+
+	mu       sync.Mutex
+	usage    Usage
+	exceeded bool
+}
+
+// New returns a budget for one request.
+func New(cfg Config) *Budget {
+	return &Budget{cfg: cfg}
+}
+
+// Record accounts for one query that took d. Once the budget is exceeded it
+// reports the overrun, and in ModeReject returns an error matching
+// ErrExceeded for this and every later query.
+func (b *Budget) Record(ctx context.Context, d time.Duration) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	b.usage.Queries++
+	b.usage.Duration += d
+	over := b.cfg.MaxQueries > 0 && b.usage.Queries > b.cfg.MaxQueries ||
+		b.cfg.MaxDuration > 0 && b.usage.Duration > b.cfg.MaxDuration
+	first := over && !b.exceeded
+	b.exceeded = b.exceeded || over
+	exceeded := b.exceeded
+	err := &Error{Name: b.cfg.Name, Usage: b.usage, MaxQueries: b.cfg.MaxQueries, MaxDuration: b.cfg.MaxDuration}
+	b.mu.Unlock()
+
+	if first {
+		if b.cfg.OnExceeded != nil {
+			b.cfg.OnExceeded(ctx, err)
+		} else {
+			obslog.FromContext(ctx).Warn("query budget exceeded",
+				"budget", err.Name, "queries", err.Usage.Queries, "duration", err.Usage.Duration,
+				"max_queries", err.MaxQueries, "max_duration", err.MaxDuration)
+		}
+	}
+	if exceeded && b.cfg.Mode == ModeReject {
+		return err
+	}
+	return nil
+}
+
+// Usage returns the queries recorded so far.
+func (b *Budget) Usage() Usage {
+	if b == nil {
+		return Usage{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.usage
+}
+
+type contextKey struct{}
+
+// WithBudget returns a context carrying b, replacing any budget already set.
+func WithBudget(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, contextKey{}, b)
+}
+
+// FromContext returns the budget of ctx, or nil when none is set.
+func FromContext(ctx context.Context) *Budget {
+	b, _ := ctx.Value(contextKey{}).(*Budget)
+	return b
+}
+
+// Record accounts for one query against the budget of ctx, if any. Data
+// layers call it after every query:
+//
+//	start := time.Now()
+//	rows, err := db.QueryContext(ctx, query, args...)
+//	if budgetErr := querybudget.Record(ctx, time.Since(start)); budgetErr != nil {
+//		return budgetErr
+//	}
+func Record(ctx context.Context, d time.Duration) error {
+	return FromContext(ctx).Record(ctx, d)
+}
+
+// Middleware gives every request a fresh budget. A budget installed further
+// in, e.g. per endpoint, replaces the outer one; a config without limits
+// lifts it.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var b *Budget
+			if cfg.MaxQueries > 0 || cfg.MaxDuration > 0 {
+				b = New(cfg)
+			}
+			next.ServeHTTP(w, r.WithContext(WithBudget(r.Context(), b)))
+		})
+	}
+}
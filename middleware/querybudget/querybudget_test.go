@@ -0,0 +1,76 @@
+package querybudget_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/middleware/querybudget"
+)
+
+func TestBudget_RejectsOverrun(t *testing.T) {
+	var reports int
+	b := querybudget.New(querybudget.Config{
+		Name:       "items",
+		MaxQueries: 2,
+		Mode:       querybudget.ModeReject,
+		OnExceeded: func(ctx context.Context, err *querybudget.Error) { reports++ },
+	})
+	ctx := querybudget.WithBudget(context.Background(), b)
+	for i := 0; i < 2; i++ {
+		if err := querybudget.Record(ctx, time.Millisecond); err != nil {
+			t.Fatalf("query %d: unexpected error %v", i+1, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		err := querybudget.Record(ctx, time.Millisecond)
+		var budgetErr *querybudget.Error
+		if !errors.Is(err, querybudget.ErrExceeded) || !errors.As(err, &budgetErr) || budgetErr.CatalogID() != querybudget.CatalogID {
+			t.Fatalf("expected budget error, got %v", err)
+		}
+	}
+	if reports != 1 {
+		t.Fatalf("expected one report, got %d", reports)
+	}
+	if usage := b.Usage(); usage.Queries != 4 || usage.Duration != 4*time.Millisecond {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestBudget_LogModeAndDuration(t *testing.T) {
+	var got *querybudget.Error
+	b := querybudget.New(querybudget.Config{
+		MaxDuration: 10 * time.Millisecond,
+		OnExceeded:  func(ctx context.Context, err *querybudget.Error) { got = err },
+	})
+	ctx := querybudget.WithBudget(context.Background(), b)
+	for i := 0; i < 3; i++ {
+		if err := querybudget.Record(ctx, 4*time.Millisecond); err != nil {
+			t.Fatalf("log mode must not fail queries, got %v", err)
+		}
+	}
+	if got == nil || got.Usage.Queries != 3 {
+		t.Fatalf("expected overrun reported on the third query, got %+v", got)
+	}
+	if err := querybudget.Record(context.Background(), time.Hour); err != nil {
+		t.Fatalf("expected no budget without middleware, got %v", err)
+	}
+}
+
+func TestMiddleware_InnerBudgetReplacesOuter(t *testing.T) {
+	var outer, inner *querybudget.Budget
+	handler := querybudget.Middleware(querybudget.Config{MaxQueries: 1})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			outer = querybudget.FromContext(r.Context())
+			querybudget.Middleware(querybudget.Config{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				inner = querybudget.FromContext(r.Context())
+			})).ServeHTTP(w, r)
+		}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if outer == nil || inner != nil {
+		t.Fatalf("expected outer budget lifted by an unlimited inner one, got %v %v", outer, inner)
+	}
+}
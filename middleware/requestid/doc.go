@@ -0,0 +1,2 @@
+// Package requestid assigns, validates, and propagates request identifiers.
+package requestid
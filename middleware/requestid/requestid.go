@@ -0,0 +1,101 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+)
+
+// DefaultHeader carries request IDs in both directions.
+const DefaultHeader = "X-Request-ID"
+
+const maxIncomingLength = 128
+
+type contextKey struct{}
+
+// Config controls the request ID middleware.
+type Config struct {
+	// Header is read from incoming requests. Defaults to X-Request-ID.
+	Header string
+	// ResponseHeader echoes the ID. Defaults to Header.
+	ResponseHeader string
+	// IgnoreIncoming always generates a fresh ID.
+	IgnoreIncoming bool
+	// Validate accepts or rejects incoming IDs; rejected IDs are replaced.
+	// Defaults to ValidID.
+	Validate func(id string) bool
+	// Generator creates new IDs. Defaults to UUIDv7.
+	Generator Generator
+}
+
+// Middleware assigns a request ID, stores it in the context, and echoes it in
+// the response header.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	header := cfg.Header
+	if header == "" {
+		header = DefaultHeader
+	}
+	responseHeader := cfg.ResponseHeader
+	if responseHeader == "" {
+		responseHeader = header
+	}
+	validate := cfg.Validate
+	if validate == nil {
+		validate = ValidID
+	}
+	generate := cfg.Generator
+	if generate == nil {
+		generate = UUIDv7
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := ""
+			if !cfg.IgnoreIncoming {
+				if incoming := r.Header.Get(header); incoming != "" && validate(incoming) {
+					id = incoming
+				}
+			}
+			if id == "" {
+				id = generate()
+			}
+			r.Header.Set(header, id)
+			w.Header().Set(responseHeader, id)
+			next.ServeHTTP(w, r.WithContext(WithID(r.Context(), id)))
+		})
+	}
+}
+
+// WithID stores id in ctx.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID assigned by Middleware, falling back to
+// the pureapi-core request ID middleware.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return endpoint.RequestIDFromContext(ctx)
+}
+
+// ValidID accepts 1–128 characters of letters, digits, and "-_.:".
+func ValidID(id string) bool {
+	if id == "" || len(id) > maxIncomingLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package requestid_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
+)
+
+func serve(cfg requestid.Config, incoming string) (string, *httptest.ResponseRecorder) {
+	var seen string
+	h := requestid.Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestid.FromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if incoming != "" {
+		req.Header.Set("X-Request-ID", incoming)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return seen, rec
+}
+
+func TestMiddlewareHonoursValidIncomingIDs(t *testing.T) {
+	seen, rec := serve(requestid.Config{ResponseHeader: "X-Trace-ID"}, "abc-123")
+	if seen != "abc-123" || rec.Header().Get("X-Trace-ID") != "abc-123" {
+		t.Fatalf("expected incoming id to be propagated, got %q / %q", seen, rec.Header().Get("X-Trace-ID"))
+	}
+
+	seen, _ = serve(requestid.Config{}, "bad id\n")
+	if seen == "bad id\n" || !requestid.ValidID(seen) {
+		t.Fatalf("expected invalid incoming id to be replaced, got %q", seen)
+	}
+
+	seen, _ = serve(requestid.Config{IgnoreIncoming: true, Generator: func() string { return "fixed" }}, "abc")
+	if seen != "fixed" {
+		t.Fatalf("expected generated id, got %q", seen)
+	}
+}
+
+func TestGeneratorsProduceSortableIDs(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ulid := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+
+	first := []string{requestid.UUIDv7(), requestid.ULID()}
+	time.Sleep(2 * time.Millisecond)
+	second := []string{requestid.UUIDv7(), requestid.ULID()}
+
+	if !uuid.MatchString(first[0]) {
+		t.Fatalf("invalid UUIDv7 %q", first[0])
+	}
+	if !ulid.MatchString(first[1]) {
+		t.Fatalf("invalid ULID %q", first[1])
+	}
+	for i := range first {
+		ids := []string{second[i], first[i]}
+		sort.Strings(ids)
+		if ids[0] != first[i] {
+			t.Fatalf("expected %q to sort before %q", first[i], second[i])
+		}
+	}
+}
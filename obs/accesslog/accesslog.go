@@ -2,21 +2,30 @@ package accesslog
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"time"
 )
 
 // Entry captures request/response metadata for structured logging.
 type Entry struct {
-	Method       string
-	Path         string
-	Status       int
-	Duration     time.Duration
-	RequestID    string
-	RemoteAddr   string
+	Method     string
+	Path       string
+	Status     int
+	Duration   time.Duration
+	RequestID  string
+	RemoteAddr string
+	// ClientIP is the client address, resolved through trusted proxies when
+	// forwarded headers are enabled.
+	ClientIP     string
 	UserAgent    string
 	ResponseSize int
 	Err          error
+	TimedOut     bool
+	// Fields holds custom values recorded with AddField during the request.
+	Fields map[string]any
 }
 
 // AccessLogger handles structured access log entries.
@@ -47,5 +56,21 @@ func NewStdLogger(l *log.Logger) *StdLogger {
 
 // Log implements AccessLogger.
 func (l *StdLogger) Log(_ context.Context, entry Entry) {
-	l.logger.Printf("method=%s path=%s status=%d duration=%s bytes=%d request_id=%s error=%v", entry.Method, entry.Path, entry.Status, entry.Duration, entry.ResponseSize, entry.RequestID, entry.Err)
+	l.logger.Printf("method=%s path=%s status=%d duration=%s bytes=%d request_id=%s client_ip=%s timed_out=%t error=%v%s", entry.Method, entry.Path, entry.Status, entry.Duration, entry.ResponseSize, entry.RequestID, entry.ClientIP, entry.TimedOut, entry.Err, formatFields(entry.Fields))
+}
+
+func formatFields(fields map[string]any) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+	return b.String()
 }
@@ -0,0 +1,66 @@
+package accesslog
+
+import (
+	"context"
+	"sync"
+)
+
+type fieldsContextKey struct{}
+
+type fieldBag struct {
+	mu     sync.Mutex
+	fields map[string]any
+}
+
+// WithFields attaches an empty field bag to ctx so AddField calls made while
+// handling the request are collected for the access log entry. It returns ctx
+// unchanged when a bag is already present.
+func WithFields(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := ctx.Value(fieldsContextKey{}).(*fieldBag); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsContextKey{}, &fieldBag{})
+}
+
+// AddField records a key/value pair on the current request's access log entry.
+// It reports false when ctx carries no field bag.
+func AddField(ctx context.Context, key string, value any) bool {
+	if ctx == nil || key == "" {
+		return false
+	}
+	bag, ok := ctx.Value(fieldsContextKey{}).(*fieldBag)
+	if !ok {
+		return false
+	}
+	bag.mu.Lock()
+	defer bag.mu.Unlock()
+	if bag.fields == nil {
+		bag.fields = make(map[string]any)
+	}
+	bag.fields[key] = value
+	return true
+}
+
+// FieldsFromContext returns a copy of the fields recorded so far.
+func FieldsFromContext(ctx context.Context) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+	bag, ok := ctx.Value(fieldsContextKey{}).(*fieldBag)
+	if !ok {
+		return nil
+	}
+	bag.mu.Lock()
+	defer bag.mu.Unlock()
+	if len(bag.fields) == 0 {
+		return nil
+	}
+	copy := make(map[string]any, len(bag.fields))
+	for k, v := range bag.fields {
+		copy[k] = v
+	}
+	return copy
+}
@@ -0,0 +1,411 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
+)
+
+// Redacted replaces the values of redacted fields.
+const Redacted = "[REDACTED]"
+
+const defaultMaxBodyBytes = 64 << 10
+
+var (
+	defaultMethods      = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultRedactFields = []string{"password", "token", "secret", "authorization"}
+)
+
+// Change holds the before and after values of a modified field.
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Record is one audited request.
+type Record struct {
+	Time      time.Time
+	Duration  time.Duration
+	Method    string
+	Path      string
+	Status    int
+	RequestID string
+	Actor     string
+	// RequestBody and ResponseBody hold redacted JSON or form bodies; bodies
+	// of other types are omitted, since they cannot be redacted.
+	RequestBody  []byte
+	ResponseBody []byte
+	// Truncated reports that a body exceeded MaxBodyBytes and was dropped.
+	Truncated bool
+	// Diff holds the change recorded with SetChange, redacted like bodies.
+	Diff map[string]Change
+}
+
+// Sink persists audit records.
+type Sink interface {
+	Write(ctx context.Context, record Record) error
+}
+
+// SinkFunc lifts a function into a Sink.
+type SinkFunc func(ctx context.Context, record Record) error
+
+// Write implements Sink.
+func (f SinkFunc) Write(ctx context.Context, record Record) error {
+	return f(ctx, record)
+}
+
+// Config controls the audit middleware.
+type Config struct {
+	// Sink receives records. Required.
+	Sink Sink
+	// Methods limits auditing to these methods. Defaults to POST, PUT, PATCH, DELETE.
+	Methods []string
+	// MaxBodyBytes caps captured bodies. Defaults to 64 KiB.
+	MaxBodyBytes int
+	// RedactFields lists JSON or form keys whose values are replaced,
+	// case-insensitively at any depth. Defaults to password, token, secret,
+	// and authorization.
+	RedactFields []string
+	// OnError receives sink failures. Errors are dropped when nil.
+	OnError func(ctx context.Context, err error)
+}
+
+// Middleware captures request and response bodies and writes a Record once
+// the handler completes. Handlers attach the actor and entity diff with
+// SetActor and SetChange.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	methods := cfg.Methods
+	if len(methods) == 0 {
+		methods = defaultMethods
+	}
+	maxBytes := cfg.MaxBodyBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBodyBytes
+	}
+	redact := cfg.RedactFields
+	if redact == nil {
+		redact = defaultRedactFields
+	}
+	redactSet := make(map[string]struct{}, len(redact))
+	for _, field := range redact {
+		redactSet[strings.ToLower(field)] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.Sink == nil || !containsMethod(methods, r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			start := time.Now()
+			state := &recordState{maxBytes: maxBytes}
+			ctx := context.WithValue(r.Context(), stateKey{}, state)
+
+			reqCapture := &limitedBuffer{limit: maxBytes}
+			if r.Body != nil {
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.TeeReader(r.Body, reqCapture), r.Body}
+			}
+			rw := &captureWriter{ResponseWriter: w, body: limitedBuffer{limit: maxBytes}}
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			reqBody, reqOK := redactBody(reqCapture, r.Header.Get("Content-Type"), redactSet)
+			state.mu.Lock()
+			respCapture := &rw.body
+			if state.response != nil {
+				respCapture = state.response
+			}
+			respBody, respOK := redactBody(respCapture, w.Header().Get("Content-Type"), redactSet)
+			record := Record{
+				Time:         start,
+				Duration:     time.Since(start),
+				Method:       r.Method,
+				Path:         r.URL.Path,
+				Status:       rw.statusCode(),
+				RequestID:    requestid.FromContext(ctx),
+				Actor:        state.actor,
+				RequestBody:  reqBody,
+				ResponseBody: respBody,
+				Truncated:    !reqOK || !respOK,
+				Diff:         redactDiff(state.diff, redactSet),
+			}
+			state.mu.Unlock()
+			if err := cfg.Sink.Write(context.WithoutCancel(ctx), record); err != nil && cfg.OnError != nil {
+				cfg.OnError(ctx, err)
+			}
+		})
+	}
+}
+
+type stateKey struct{}
+
+type recordState struct {
+	mu       sync.Mutex
+	actor    string
+	diff     map[string]Change
+	maxBytes int
+	// response captures the body before content encoding when the writer
+	// encoding it is wrapped with TapResponse.
+	response *limitedBuffer
+}
+
+// SetActor records who performed the audited request.
+func SetActor(ctx context.Context, actor string) {
+	if state, ok := ctx.Value(stateKey{}).(*recordState); ok {
+		state.mu.Lock()
+		state.actor = actor
+		state.mu.Unlock()
+	}
+}
+
+// TapResponse wraps w, which must write the unencoded response body, so the
+// audit record captures that body rather than the compressed bytes reaching
+// the client. It returns w unchanged outside audited requests.
+func TapResponse(ctx context.Context, w http.ResponseWriter) http.ResponseWriter {
+	state, ok := ctx.Value(stateKey{}).(*recordState)
+	if !ok {
+		return w
+	}
+	state.mu.Lock()
+	if state.response == nil {
+		state.response = &limitedBuffer{limit: state.maxBytes}
+	}
+	buf := state.response
+	state.mu.Unlock()
+	return &tapWriter{ResponseWriter: w, state: state, body: buf}
+}
+
+// SetChange records the field-level difference between before and after,
+// compared through their JSON representations.
+func SetChange(ctx context.Context, before, after any) {
+	state, ok := ctx.Value(stateKey{}).(*recordState)
+	if !ok {
+		return
+	}
+	diff := Diff(before, after)
+	state.mu.Lock()
+	state.diff = diff
+	state.mu.Unlock()
+}
+
+// Diff compares the top-level JSON fields of before and after.
+func Diff(before, after any) map[string]Change {
+	b, a := toFields(before), toFields(after)
+	diff := map[string]Change{}
+	for key, bv := range b {
+		av, ok := a[key]
+		if !ok || !jsonEqual(bv, av) {
+			diff[key] = Change{Before: bv, After: av}
+		}
+	}
+	for key, av := range a {
+		if _, ok := b[key]; !ok {
+			diff[key] = Change{After: av}
+		}
+	}
+	return diff
+}
+
+func toFields(v any) map[string]any {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+func jsonEqual(a, b any) bool {
+	ad, _ := json.Marshal(a)
+	bd, _ := json.Marshal(b)
+	return bytes.Equal(ad, bd)
+}
+
+// redactBody returns a redacted copy of the captured body. It reports false
+// when the body was truncated and has been dropped.
+func redactBody(buf *limitedBuffer, contentType string, redact map[string]struct{}) ([]byte, bool) {
+	if buf.truncated {
+		return nil, false
+	}
+	data := buf.Bytes()
+	if len(data) == 0 {
+		return nil, true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	// Untyped bodies, including those net/http sniffed as text/plain, are
+	// redacted as JSON when they parse as such.
+	if (mediaType == "" || mediaType == "text/plain") && json.Valid(data) {
+		mediaType = "application/json"
+	}
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, true
+		}
+		for key := range values {
+			if _, ok := redact[strings.ToLower(key)]; ok {
+				values[key] = []string{Redacted}
+			}
+		}
+		return []byte(values.Encode()), true
+	case strings.HasSuffix(mediaType, "json"):
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, true
+		}
+		redacted, err := json.Marshal(redactValue(doc, redact))
+		if err != nil {
+			return nil, true
+		}
+		return redacted, true
+	}
+	// Bodies that cannot be parsed may hold anything, so they are omitted
+	// rather than stored unredacted.
+	return nil, true
+}
+
+// redactDiff returns a copy of diff with redacted fields masked at any depth.
+func redactDiff(diff map[string]Change, redact map[string]struct{}) map[string]Change {
+	if diff == nil {
+		return nil
+	}
+	out := make(map[string]Change, len(diff))
+	for key, change := range diff {
+		if _, ok := redact[strings.ToLower(key)]; ok {
+			change = Change{Before: Redacted, After: Redacted}
+		} else {
+			change = Change{Before: redactValue(change.Before, redact), After: redactValue(change.After, redact)}
+		}
+		out[key] = change
+	}
+	return out
+}
+
+func redactValue(v any, redact map[string]struct{}) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, child := range value {
+			if _, ok := redact[strings.ToLower(key)]; ok {
+				value[key] = Redacted
+				continue
+			}
+			value[key] = redactValue(child, redact)
+		}
+	case []any:
+		for i := range value {
+			value[i] = redactValue(value[i], redact)
+		}
+	}
+	return v
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedBuffer keeps at most limit bytes and remembers overflow.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); remaining < len(p) {
+		b.truncated = true
+		if remaining > 0 {
+			b.Buffer.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	body   limitedBuffer
+}
+
+func (w *captureWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	_, _ = w.body.Write(p[:n])
+	return n, err
+}
+
+// Flush implements http.Flusher when the underlying writer supports it.
+func (w *captureWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// tapWriter copies the body written through it into the audit state.
+type tapWriter struct {
+	http.ResponseWriter
+	state *recordState
+	body  *limitedBuffer
+}
+
+func (w *tapWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.state.mu.Lock()
+	_, _ = w.body.Write(p[:n])
+	w.state.mu.Unlock()
+	return n, err
+}
+
+// Flush implements http.Flusher when the underlying writer supports it.
+func (w *tapWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *tapWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *captureWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,132 @@
+package audit_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/obs/audit"
+)
+
+func TestMiddlewareCapturesRedactedBodies(t *testing.T) {
+	var records []audit.Record
+	sink := audit.SinkFunc(func(_ context.Context, record audit.Record) error {
+		records = append(records, record)
+		return nil
+	})
+	h := audit.Middleware(audit.Config{Sink: sink})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost && !strings.Contains(string(body), "hunter2") {
+			t.Errorf("handler must see the original body")
+		}
+		audit.SetActor(r.Context(), "alice")
+		audit.SetChange(r.Context(), map[string]any{"name": "old", "id": 1}, map[string]any{"name": "new", "id": 1})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"id":1,"token":"abc"}`)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"new","credentials":{"password":"hunter2"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(records) != 1 {
+		t.Fatalf("expected one record, got %d", len(records))
+	}
+	record := records[0]
+	if record.Actor != "alice" || record.Status != http.StatusCreated || record.Method != http.MethodPost {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+	if strings.Contains(string(record.RequestBody), "hunter2") || !strings.Contains(string(record.RequestBody), audit.Redacted) {
+		t.Fatalf("expected password to be redacted, got %s", record.RequestBody)
+	}
+	if strings.Contains(string(record.ResponseBody), "abc") {
+		t.Fatalf("expected token to be redacted, got %s", record.ResponseBody)
+	}
+	if len(record.Diff) != 1 || record.Diff["name"].Before != "old" || record.Diff["name"].After != "new" {
+		t.Fatalf("unexpected diff: %+v", record.Diff)
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	if len(records) != 1 {
+		t.Fatalf("expected GET to be skipped")
+	}
+}
+
+func TestMiddlewareDropsTruncatedBodies(t *testing.T) {
+	var record audit.Record
+	sink := audit.SinkFunc(func(_ context.Context, r audit.Record) error {
+		record = r
+		return nil
+	})
+	h := audit.Middleware(audit.Config{Sink: sink, MaxBodyBytes: 8})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = io.WriteString(w, `"ok"`)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/x", strings.NewReader(`{"password":"long enough"}`)))
+	if !record.Truncated || record.RequestBody != nil || string(record.ResponseBody) != `"ok"` {
+		t.Fatalf("unexpected truncated record: %+v", record)
+	}
+}
+
+func TestMiddlewareFailsClosed(t *testing.T) {
+	var record audit.Record
+	sink := audit.SinkFunc(func(_ context.Context, r audit.Record) error {
+		record = r
+		return nil
+	})
+	h := audit.Middleware(audit.Config{Sink: sink})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		audit.SetChange(r.Context(),
+			map[string]any{"password": "old", "profile": map[string]any{"token": "a"}},
+			map[string]any{"password": "new", "profile": map[string]any{"token": "b"}})
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, "password=hunter2")
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"password":"hunter2"}`)))
+	if strings.Contains(string(record.RequestBody), "hunter2") || !strings.Contains(string(record.RequestBody), audit.Redacted) {
+		t.Fatalf("expected untyped JSON body redacted, got %s", record.RequestBody)
+	}
+	if record.ResponseBody != nil {
+		t.Fatalf("expected body of unknown type omitted, got %s", record.ResponseBody)
+	}
+	if record.Diff["password"].Before != audit.Redacted || record.Diff["profile"].After.(map[string]any)["token"] != audit.Redacted {
+		t.Fatalf("expected diff redacted, got %+v", record.Diff)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("password=hunter2"))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if record.RequestBody != nil {
+		t.Fatalf("expected opaque body omitted, got %s", record.RequestBody)
+	}
+}
+
+func TestTapResponseCapturesUnencodedBody(t *testing.T) {
+	var record audit.Record
+	sink := audit.SinkFunc(func(_ context.Context, r audit.Record) error {
+		record = r
+		return nil
+	})
+	h := audit.Middleware(audit.Config{Sink: sink})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Stand-in for a compressing writer: the client sees other bytes.
+		_, _ = w.Write([]byte{0x1f, 0x8b})
+		_, _ = io.WriteString(audit.TapResponse(r.Context(), discardWriter{w}), `{"id":1}`)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items", nil))
+	if string(record.ResponseBody) != `{"id":1}` {
+		t.Fatalf("expected tapped body, got %q", record.ResponseBody)
+	}
+	if w := audit.TapResponse(context.Background(), httptest.NewRecorder()); w == nil {
+		t.Fatalf("expected writer returned outside audited requests")
+	}
+}
+
+type discardWriter struct{ http.ResponseWriter }
+
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
@@ -0,0 +1,2 @@
+// Package audit captures redacted request and response bodies for an audit trail.
+package audit
@@ -0,0 +1,3 @@
+// Package log defines a minimal structured logger and stores a request-scoped
+// logger in the context so handlers and hooks log with the same fields.
+package log
@@ -0,0 +1,118 @@
+package log
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/aatuh/pureapi-framework/hooks"
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
+	"github.com/aatuh/pureapi-framework/tenancy"
+)
+
+// Field keys attached by Enricher.
+const (
+	FieldRequestID = "request_id"
+	FieldMethod    = "method"
+	FieldRoute     = "route"
+	FieldTenant    = "tenant"
+)
+
+// Logger writes structured records. Args alternate keys and values as in
+// log/slog.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+	// With returns a logger that adds args to every record.
+	With(args ...any) Logger
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlog adapts l to Logger. A nil l uses slog.Default.
+func NewSlog(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return slogLogger{l: l}
+}
+
+func (s slogLogger) Debug(msg string, args ...any) { s.l.Debug(msg, args...) }
+func (s slogLogger) Info(msg string, args ...any)  { s.l.Info(msg, args...) }
+func (s slogLogger) Warn(msg string, args ...any)  { s.l.Warn(msg, args...) }
+func (s slogLogger) Error(msg string, args ...any) { s.l.Error(msg, args...) }
+
+func (s slogLogger) With(args ...any) Logger {
+	return slogLogger{l: s.l.With(args...)}
+}
+
+// Discard returns a Logger that drops every record.
+func Discard() Logger {
+	return NewSlog(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+type contextKey struct{}
+
+// WithLogger stores l in ctx.
+func WithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx, or the slog default logger
+// when none was stored, so callers never need nil checks.
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+			return l
+		}
+	}
+	return NewSlog(nil)
+}
+
+// Config controls Enricher.
+type Config struct {
+	// Logger is the base logger. Defaults to the slog default logger.
+	Logger Logger
+	// Fields adds application fields, e.g. the authenticated user.
+	Fields func(ctx context.Context, r *http.Request) []any
+}
+
+// Enricher stores a logger carrying the request ID, method, route, and
+// tenant of each request. Register it after the tenancy enricher so the
+// tenant is known.
+func Enricher(cfg Config) hooks.ContextEnricher {
+	base := cfg.Logger
+	if base == nil {
+		base = NewSlog(nil)
+	}
+	return hooks.ContextEnricherFunc(func(ctx context.Context, r *http.Request) (context.Context, error) {
+		args := []any{FieldMethod, r.Method, FieldRoute, route(r)}
+		if id := requestid.FromContext(ctx); id != "" {
+			args = append(args, FieldRequestID, id)
+		}
+		if tenant := tenancy.IDFromContext(ctx); tenant != "" {
+			args = append(args, FieldTenant, tenant)
+		}
+		if cfg.Fields != nil {
+			args = append(args, cfg.Fields(ctx, r)...)
+		}
+		return WithLogger(ctx, base.With(args...)), nil
+	})
+}
+
+// route returns the matched path template, falling back to the request path.
+func route(r *http.Request) string {
+	if r.Pattern == "" {
+		return r.URL.Path
+	}
+	if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+		return path
+	}
+	return r.Pattern
+}
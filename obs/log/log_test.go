@@ -0,0 +1,55 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/middleware/requestid"
+	obslog "github.com/aatuh/pureapi-framework/obs/log"
+	"github.com/aatuh/pureapi-framework/tenancy"
+)
+
+func TestEnricherStoresRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	base := obslog.NewSlog(slog.New(slog.NewJSONHandler(&buf, nil)))
+	enricher := obslog.Enricher(obslog.Config{
+		Logger: base,
+		Fields: func(ctx context.Context, r *http.Request) []any { return []any{"user", "alice"} },
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	req.Pattern = "GET /items/{id}"
+	ctx := requestid.WithID(req.Context(), "req-1")
+	ctx = tenancy.WithTenant(ctx, tenancy.Tenant{ID: "acme"})
+	ctx, err := enricher.Enrich(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obslog.FromContext(ctx).Info("loaded item", "id", "42")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid record %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"msg": "loaded item", "request_id": "req-1", "method": "GET",
+		"route": "/items/{id}", "tenant": "acme", "user": "alice", "id": "42",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Fatalf("expected %s=%v, got %v", key, value, record)
+		}
+	}
+}
+
+func TestFromContextFallsBackToDefault(t *testing.T) {
+	if obslog.FromContext(context.Background()) == nil {
+		t.Fatalf("expected default logger")
+	}
+	obslog.Discard().With("k", "v").Error("dropped")
+}
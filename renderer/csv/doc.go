@@ -0,0 +1,2 @@
+// Package csv renders list payloads as CSV exports.
+package csv
@@ -0,0 +1,288 @@
+package csv
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	stdcsv "encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/renderer/registry"
+)
+
+const (
+	// MediaType is the Accept media type to register the renderer under.
+	MediaType = "text/csv"
+	// ContentType is sent with exports.
+	ContentType = "text/csv; charset=utf-8"
+)
+
+// Config controls CSV exports.
+type Config struct {
+	// Comma is the field delimiter. Defaults to ','.
+	Comma rune
+	// NoHeader omits the header row.
+	NoHeader bool
+	// Filename, when set, is sent in a Content-Disposition attachment header
+	// so browsers download the export.
+	Filename string
+}
+
+// ContentDisposition returns the Content-Disposition header for cfg, or "".
+func (cfg Config) ContentDisposition() string {
+	if cfg.Filename == "" {
+		return ""
+	}
+	return mime.FormatMediaType("attachment", map[string]string{"filename": cfg.Filename})
+}
+
+// column is one exported struct field.
+type column struct {
+	header string
+	index  []int
+}
+
+// columns lists the exported fields of t in declaration order, flattening
+// embedded structs. Headers come from the `csv` tag, then the `json` tag,
+// then the field name; `csv:"-"` skips a field.
+func columns(t reflect.Type) []column {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return []column{{header: "value"}}
+	}
+	var out []column
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && indirect(field.Type).Kind() == reflect.Struct {
+			continue
+		}
+		header := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if name, _, _ := strings.Cut(tag, ","); name == "-" {
+				continue
+			} else if name != "" {
+				header = name
+			}
+		}
+		if tag, ok := field.Tag.Lookup("csv"); ok {
+			if tag == "-" {
+				continue
+			}
+			if name, _, _ := strings.Cut(tag, ","); name != "" {
+				header = name
+			}
+		}
+		out = append(out, column{header: header, index: field.Index})
+	}
+	return out
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// Writer streams rows of one element type as CSV.
+type Writer struct {
+	cfg     Config
+	csv     *stdcsv.Writer
+	columns []column
+	record  []string
+}
+
+// NewWriter returns a Writer encoding to w.
+func NewWriter(w io.Writer, cfg Config) *Writer {
+	cw := stdcsv.NewWriter(w)
+	if cfg.Comma != 0 {
+		cw.Comma = cfg.Comma
+	}
+	return &Writer{cfg: cfg, csv: cw}
+}
+
+// Header selects the columns of elem and writes the header row unless
+// disabled. Write calls it with the first row's type when needed.
+func (w *Writer) Header(elem reflect.Type) error {
+	if w.columns != nil {
+		return nil
+	}
+	w.columns = columns(elem)
+	w.record = make([]string, len(w.columns))
+	if w.cfg.NoHeader {
+		return nil
+	}
+	for i, col := range w.columns {
+		w.record[i] = col.header
+	}
+	return w.csv.Write(w.record)
+}
+
+// Write encodes item as one row.
+func (w *Writer) Write(item any) error {
+	v := reflect.ValueOf(item)
+	if !v.IsValid() {
+		return nil
+	}
+	if err := w.Header(v.Type()); err != nil {
+		return err
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	for i, col := range w.columns {
+		field := v
+		if col.index != nil {
+			var err error
+			if field, err = v.FieldByIndexErr(col.index); err != nil {
+				w.record[i] = ""
+				continue
+			}
+		}
+		cell, err := formatCell(field)
+		if err != nil {
+			return fmt.Errorf("csv column %s: %w", col.header, err)
+		}
+		w.record[i] = cell
+	}
+	return w.csv.Write(w.record)
+}
+
+// Flush writes buffered rows to the underlying writer.
+func (w *Writer) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func formatCell(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case time.Time:
+			return x.Format(time.RFC3339Nano), nil
+		case encoding.TextMarshaler:
+			text, err := x.MarshalText()
+			return string(text), err
+		case fmt.Stringer:
+			return x.String(), nil
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.IsNil() {
+		return "", nil
+	}
+	data, err := json.Marshal(v.Interface())
+	return string(data), err
+}
+
+// Rows returns the list an export renders: payload itself when it is a slice
+// or array, otherwise the first exported slice field of a struct payload,
+// such as Items or Entities.
+func Rows(payload any) (reflect.Value, bool) {
+	v := reflect.ValueOf(payload)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return reflect.Value{}, false
+		}
+		return v, true
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.IsExported() && field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8 {
+				return v.Field(i), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// Encode writes the rows of payload to w. Payloads without rows yield
+// registry.ErrUnsupportedPayload.
+func Encode(w io.Writer, payload any, cfg Config) error {
+	rows, ok := Rows(payload)
+	if !ok {
+		return registry.ErrUnsupportedPayload
+	}
+	cw := NewWriter(w, cfg)
+	if err := cw.Header(rows.Type().Elem()); err != nil {
+		return err
+	}
+	for i := 0; i < rows.Len(); i++ {
+		if err := cw.Write(rows.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return cw.Flush()
+}
+
+// Renderer renders list payloads as CSV.
+type Renderer struct {
+	Config Config
+}
+
+// Encode implements registry.BufferRenderFunc.
+func (r Renderer) Encode(ctx context.Context, buf *bytes.Buffer, status int, payload any) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	start := buf.Len()
+	if err := Encode(buf, payload, r.Config); err != nil {
+		buf.Truncate(start)
+		return "", err
+	}
+	return ContentType, nil
+}
+
+// RenderFunc returns a registry.RenderFunc compatible closure.
+func (r Renderer) RenderFunc() registry.RenderFunc {
+	return func(ctx context.Context, status int, payload any) ([]byte, string, error) {
+		var buf bytes.Buffer
+		contentType, err := r.Encode(ctx, &buf, status, payload)
+		return buf.Bytes(), contentType, err
+	}
+}
+
+// BufferRenderFunc returns a registry.BufferRenderFunc compatible closure.
+func (r Renderer) BufferRenderFunc() registry.BufferRenderFunc {
+	return r.Encode
+}
@@ -0,0 +1,67 @@
+package csv_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/renderer/csv"
+	"github.com/aatuh/pureapi-framework/renderer/registry"
+)
+
+type Audit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type user struct {
+	Audit
+	ID       int      `json:"id"`
+	Name     string   `csv:"Full name"`
+	Email    *string  `json:"email,omitempty"`
+	Tags     []string `json:"tags"`
+	Password string   `json:"-"`
+	internal string
+}
+
+func TestEncode_UsesTagsForHeadersAndFormatsCells(t *testing.T) {
+	email := "ann@example.com"
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	page := struct {
+		Total int
+		Items []user
+	}{
+		Total: 2,
+		Items: []user{
+			{Audit: Audit{CreatedAt: at}, ID: 1, Name: "Ann, A.", Email: &email, Tags: []string{"a"}, Password: "x"},
+			{ID: 2, Name: "Bob"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := csv.Encode(&buf, page, csv.Config{}); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	want := "created_at,id,Full name,email,tags\n" +
+		"2024-05-01T12:00:00Z,1,\"Ann, A.\",ann@example.com,\"[\"\"a\"\"]\"\n" +
+		"0001-01-01T00:00:00Z,2,Bob,,\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected csv:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := csv.Encode(&buf, []user{}, csv.Config{Comma: ';'}); err != nil || buf.String() != "created_at;id;Full name;email;tags\n" {
+		t.Fatalf("expected header only for empty list, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestRenderer_RejectsPayloadsWithoutRows(t *testing.T) {
+	_, _, err := csv.Renderer{}.RenderFunc()(context.Background(), http.StatusOK, map[string]string{"error": "x"})
+	if !errors.Is(err, registry.ErrUnsupportedPayload) {
+		t.Fatalf("expected ErrUnsupportedPayload, got %v", err)
+	}
+	if got := (csv.Config{Filename: "users.csv"}).ContentDisposition(); got != "attachment; filename=users.csv" {
+		t.Fatalf("unexpected disposition %q", got)
+	}
+}
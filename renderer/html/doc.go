@@ -0,0 +1,2 @@
+// Package html renders server-side pages with html/template.
+package html
@@ -0,0 +1,200 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/aatuh/pureapi-framework/renderer/registry"
+)
+
+const (
+	// MediaType is the Accept media type to register the renderer under.
+	MediaType = "text/html"
+	// ContentType is sent with rendered pages.
+	ContentType = "text/html; charset=utf-8"
+)
+
+// TemplateResult is returned by handlers to render a page.
+type TemplateResult struct {
+	// Name is the page template, e.g. "users/list.html".
+	Name string
+	// Data is passed to the template as dot.
+	Data any
+	// Layout overrides Config.Layout for this page; "-" renders the page
+	// without a layout.
+	Layout string
+}
+
+// Config controls a Renderer.
+type Config struct {
+	// FS holds the templates.
+	FS fs.FS
+	// Pages are glob patterns matching page templates. Each page is named
+	// by its path in FS. Defaults to "*.html".
+	Pages []string
+	// Shared are glob patterns matching layouts and partials parsed
+	// alongside every page, e.g. "layouts/*.html".
+	Shared []string
+	// Layout is the shared template executed for every page. Pages define
+	// the blocks it includes, typically {{define "content"}}. Empty renders
+	// pages directly.
+	Layout string
+	// Funcs are available to every template.
+	Funcs template.FuncMap
+	// Fallback is the page rendered for payloads that are not a
+	// TemplateResult, such as error responses, with the payload as dot.
+	// Without it such payloads are left to the client's next acceptable
+	// content type.
+	Fallback string
+}
+
+// Renderer renders TemplateResult payloads as HTML.
+type Renderer struct {
+	cfg   Config
+	pages map[string]*template.Template
+}
+
+// New parses cfg's templates. Every page gets its own template set so pages
+// can define the same blocks for a shared layout.
+func New(cfg Config) (*Renderer, error) {
+	if cfg.FS == nil {
+		return nil, fmt.Errorf("html renderer: FS is required")
+	}
+	if len(cfg.Pages) == 0 {
+		cfg.Pages = []string{"*.html"}
+	}
+	shared, err := globAll(cfg.FS, cfg.Shared)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := globAll(cfg.FS, cfg.Pages)
+	if err != nil {
+		return nil, err
+	}
+	base := template.New("").Funcs(cfg.Funcs)
+	if len(shared) > 0 {
+		if base, err = base.ParseFS(cfg.FS, shared...); err != nil {
+			return nil, fmt.Errorf("html renderer: %w", err)
+		}
+	}
+	r := &Renderer{cfg: cfg, pages: make(map[string]*template.Template, len(pages))}
+	for _, name := range pages {
+		page, err := base.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("html renderer: %w", err)
+		}
+		data, err := fs.ReadFile(cfg.FS, name)
+		if err != nil {
+			return nil, fmt.Errorf("html renderer: %w", err)
+		}
+		if _, err := page.New(name).Parse(string(data)); err != nil {
+			return nil, fmt.Errorf("html renderer: %w", err)
+		}
+		r.pages[name] = page
+	}
+	if cfg.Fallback != "" && r.pages[cfg.Fallback] == nil {
+		return nil, fmt.Errorf("html renderer: fallback page %q not found", cfg.Fallback)
+	}
+	return r, nil
+}
+
+// globAll expands patterns, skipping duplicates and matches shared with
+// earlier patterns.
+func globAll(fsys fs.FS, patterns []string) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("html renderer: %w", err)
+		}
+		for _, name := range matches {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// Pages returns the names of the parsed pages.
+func (r *Renderer) Pages() []string {
+	names := make([]string, 0, len(r.pages))
+	for name := range r.pages {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Execute renders the result into buf.
+func (r *Renderer) Execute(buf *bytes.Buffer, result TemplateResult) error {
+	name := path.Clean(strings.TrimPrefix(result.Name, "/"))
+	page, ok := r.pages[name]
+	if !ok {
+		return fmt.Errorf("html renderer: page %q not found", result.Name)
+	}
+	layout := r.cfg.Layout
+	if result.Layout != "" {
+		layout = result.Layout
+	}
+	if layout != "" && layout != "-" {
+		name = layout
+	}
+	if err := page.ExecuteTemplate(buf, name, result.Data); err != nil {
+		return fmt.Errorf("html renderer: %w", err)
+	}
+	return nil
+}
+
+// Encode implements registry.BufferRenderFunc. Payloads other than a
+// TemplateResult render the Fallback page or yield
+// registry.ErrUnsupportedPayload.
+func (r *Renderer) Encode(ctx context.Context, buf *bytes.Buffer, status int, payload any) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	var result TemplateResult
+	switch v := payload.(type) {
+	case TemplateResult:
+		result = v
+	case *TemplateResult:
+		if v == nil {
+			return "", registry.ErrUnsupportedPayload
+		}
+		result = *v
+	default:
+		if r.cfg.Fallback == "" {
+			return "", registry.ErrUnsupportedPayload
+		}
+		result = TemplateResult{Name: r.cfg.Fallback, Data: payload}
+	}
+	start := buf.Len()
+	if err := r.Execute(buf, result); err != nil {
+		buf.Truncate(start)
+		return "", err
+	}
+	return ContentType, nil
+}
+
+// BufferRenderFunc returns a registry.BufferRenderFunc compatible closure.
+func (r *Renderer) BufferRenderFunc() registry.BufferRenderFunc {
+	return r.Encode
+}
+
+// RenderFunc returns a registry.RenderFunc compatible closure.
+func (r *Renderer) RenderFunc() registry.RenderFunc {
+	return func(ctx context.Context, status int, payload any) ([]byte, string, error) {
+		var buf bytes.Buffer
+		contentType, err := r.Encode(ctx, &buf, status, payload)
+		return buf.Bytes(), contentType, err
+	}
+}
@@ -0,0 +1,79 @@
+package html_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	gotemplate "text/template"
+
+	"github.com/aatuh/pureapi-framework/renderer/html"
+	"github.com/aatuh/pureapi-framework/renderer/json"
+	"github.com/aatuh/pureapi-framework/renderer/registry"
+)
+
+var templates = fstest.MapFS{
+	"layouts/base.html": {Data: []byte(`<html><title>{{block "title" .}}App{{end}}</title><body>{{template "content" .}}</body></html>`)},
+	"users.html":        {Data: []byte(`{{define "title"}}Users{{end}}{{define "content"}}{{range .}}<li>{{shout .}}</li>{{end}}{{end}}`)},
+	"error.html":        {Data: []byte(`{{define "content"}}<p>{{.}}</p>{{end}}`)},
+}
+
+func TestRenderer_ComposesLayoutsAndFuncs(t *testing.T) {
+	r, err := html.New(html.Config{
+		FS:     templates,
+		Shared: []string{"layouts/*.html"},
+		Layout: "base.html",
+		Funcs:  gotemplate.FuncMap{"shout": strings.ToUpper},
+	})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	data, contentType, err := r.RenderFunc()(context.Background(), http.StatusOK, html.TemplateResult{Name: "users.html", Data: []string{"ann", "<bob>"}})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := `<html><title>Users</title><body><li>ANN</li><li>&lt;BOB&gt;</li></body></html>`
+	if string(data) != want || contentType != html.ContentType {
+		t.Fatalf("unexpected page %q (%s)", data, contentType)
+	}
+	if _, _, err := r.RenderFunc()(context.Background(), http.StatusOK, html.TemplateResult{Name: "missing.html"}); err == nil {
+		t.Fatalf("expected error for unknown page")
+	}
+}
+
+func TestRenderer_DefersOtherPayloadsToNextContentType(t *testing.T) {
+	cfg := html.Config{
+		FS:     templates,
+		Shared: []string{"layouts/*.html"},
+		Layout: "base.html",
+		Funcs:  gotemplate.FuncMap{"shout": strings.ToUpper},
+	}
+	render := func(cfg html.Config, payload any) *httptest.ResponseRecorder {
+		r, err := html.New(cfg)
+		if err != nil {
+			t.Fatalf("new: %v", err)
+		}
+		reg := registry.New("application/json", json.Renderer{}.RenderFunc())
+		reg.Register(html.MediaType, r.RenderFunc())
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+		rec := httptest.NewRecorder()
+		if err := reg.Render(context.Background(), rec, req, http.StatusOK, payload); err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		return rec
+	}
+
+	rec := render(cfg, map[string]int{"n": 1})
+	if rec.Header().Get("Content-Type") != "application/json" || rec.Body.String() != `{"n":1}` {
+		t.Fatalf("expected JSON fallback, got %q %s", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+
+	cfg.Fallback = "error.html"
+	rec = render(cfg, "oops")
+	if rec.Header().Get("Content-Type") != html.ContentType || rec.Body.String() != `<html><title>App</title><body><p>oops</p></body></html>` {
+		t.Fatalf("expected fallback page, got %q %s", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
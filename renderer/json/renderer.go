@@ -1,6 +1,7 @@
 package json
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -45,3 +46,37 @@ func (r Renderer) RenderFunc() registry.RenderFunc {
 		return r.Render(ctx, status, payload)
 	}
 }
+
+// Encode streams payload into buf with a json.Encoder and returns the content
+// type. It implements registry.BufferRenderFunc.
+func (r Renderer) Encode(ctx context.Context, buf *bytes.Buffer, status int, payload any) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if payload == nil {
+		buf.WriteString("null")
+		return "application/json", nil
+	}
+	start := buf.Len()
+	enc := json.NewEncoder(buf)
+	if r.Pretty {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(payload); err != nil {
+		buf.Truncate(start)
+		return "", fmt.Errorf("render json: %w", err)
+	}
+	// Encoder terminates each value with a newline; Render does not.
+	buf.Truncate(buf.Len() - 1)
+	return "application/json", nil
+}
+
+// BufferRenderFunc returns a registry.BufferRenderFunc compatible closure.
+func (r Renderer) BufferRenderFunc() registry.BufferRenderFunc {
+	return func(ctx context.Context, buf *bytes.Buffer, status int, payload any) (string, error) {
+		return r.Encode(ctx, buf, status, payload)
+	}
+}
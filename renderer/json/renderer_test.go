@@ -30,3 +30,69 @@ func TestRenderer_Render(t *testing.T) {
 		t.Fatalf("unexpected body: %s", rec.Body.String())
 	}
 }
+
+func TestRenderer_EncodeMatchesRender(t *testing.T) {
+	payload := map[string]any{"ok": "<yes>", "n": []int{1, 2}}
+	for _, r := range []Renderer{{}, {Pretty: true}} {
+		want, _, err := r.Render(context.Background(), http.StatusOK, payload)
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		reg := registry.New("application/json", nil)
+		reg.RegisterBuffered("application/json", r.BufferRenderFunc())
+		rec := httptest.NewRecorder()
+		if err := reg.Render(context.Background(), rec, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusCreated, payload); err != nil {
+			t.Fatalf("buffered render: %v", err)
+		}
+		if rec.Code != http.StatusCreated || rec.Body.String() != string(want) {
+			t.Fatalf("expected %s, got %d %s", want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+type benchmarkItem struct {
+	ID   int      `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+func benchmarkPayload() any {
+	items := make([]benchmarkItem, 500)
+	for i := range items {
+		items[i] = benchmarkItem{ID: i, Name: "item", Tags: []string{"a", "b", "c"}}
+	}
+	return items
+}
+
+func BenchmarkRender_Marshal(b *testing.B) {
+	reg := registry.New("application/json", Renderer{}.RenderFunc())
+	reg.SetBufferPool(nil)
+	benchmarkRegistry(b, reg)
+}
+
+func BenchmarkRender_PooledEncoder(b *testing.B) {
+	reg := registry.New("application/json", nil)
+	reg.RegisterBuffered("application/json", Renderer{}.BufferRenderFunc())
+	benchmarkRegistry(b, reg)
+}
+
+func benchmarkRegistry(b *testing.B, reg *registry.Registry) {
+	payload := benchmarkPayload()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := discardWriter{header: make(http.Header)}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := reg.Render(context.Background(), w, req, http.StatusOK, payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type discardWriter struct {
+	header http.Header
+}
+
+func (w discardWriter) Header() http.Header         { return w.header }
+func (w discardWriter) WriteHeader(int)             {}
+func (w discardWriter) Write(p []byte) (int, error) { return len(p), nil }
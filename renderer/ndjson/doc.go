@@ -0,0 +1,2 @@
+// Package ndjson renders list payloads as newline-delimited JSON.
+package ndjson
@@ -0,0 +1,50 @@
+package ndjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/aatuh/pureapi-framework/renderer/registry"
+)
+
+// ContentType is the media type of newline-delimited JSON.
+const ContentType = "application/x-ndjson"
+
+// Renderer renders slice payloads as one JSON object per line. Other
+// payloads, such as error responses, yield registry.ErrUnsupportedPayload so
+// the client's next acceptable content type is used.
+type Renderer struct{}
+
+// Encode implements registry.BufferRenderFunc.
+func (Renderer) Encode(ctx context.Context, buf *bytes.Buffer, status int, payload any) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	v := reflect.ValueOf(payload)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || v.Type().Elem().Kind() == reflect.Uint8 {
+		return "", registry.ErrUnsupportedPayload
+	}
+	start := buf.Len()
+	enc := json.NewEncoder(buf)
+	for i := 0; i < v.Len(); i++ {
+		if err := enc.Encode(v.Index(i).Interface()); err != nil {
+			buf.Truncate(start)
+			return "", fmt.Errorf("render ndjson: %w", err)
+		}
+	}
+	return ContentType, nil
+}
+
+// BufferRenderFunc returns a registry.BufferRenderFunc compatible closure.
+func (r Renderer) BufferRenderFunc() registry.BufferRenderFunc {
+	return r.Encode
+}
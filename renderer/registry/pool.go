@@ -0,0 +1,46 @@
+package registry
+
+import (
+	"bytes"
+	"sync"
+)
+
+// BufferPool recycles render buffers.
+type BufferPool interface {
+	Get() *bytes.Buffer
+	Put(buf *bytes.Buffer)
+}
+
+// defaultMaxPooledBytes bounds buffers returned to the pool so one large
+// response does not pin its memory.
+const defaultMaxPooledBytes = 1 << 20
+
+// DefaultBufferPool is shared by registries that do not set their own.
+var DefaultBufferPool BufferPool = NewBufferPool(defaultMaxPooledBytes)
+
+type syncBufferPool struct {
+	pool     sync.Pool
+	maxBytes int
+}
+
+// NewBufferPool returns a sync.Pool-backed BufferPool that discards buffers
+// whose capacity exceeds maxBytes. A non-positive maxBytes keeps all buffers.
+func NewBufferPool(maxBytes int) BufferPool {
+	return &syncBufferPool{
+		pool:     sync.Pool{New: func() any { return new(bytes.Buffer) }},
+		maxBytes: maxBytes,
+	}
+}
+
+func (p *syncBufferPool) Get() *bytes.Buffer {
+	buf := p.pool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func (p *syncBufferPool) Put(buf *bytes.Buffer) {
+	if buf == nil || (p.maxBytes > 0 && buf.Cap() > p.maxBytes) {
+		return
+	}
+	p.pool.Put(buf)
+}
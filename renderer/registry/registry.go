@@ -1,29 +1,51 @@
 package registry
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 // RenderFunc renders the payload and returns the body bytes and content type.
 type RenderFunc func(ctx context.Context, status int, payload any) ([]byte, string, error)
 
+// BufferRenderFunc encodes the payload into buf and returns the content type.
+// The registry supplies buf from its BufferPool and writes it to the client,
+// avoiding an intermediate body slice per response.
+type BufferRenderFunc func(ctx context.Context, buf *bytes.Buffer, status int, payload any) (string, error)
+
+// ErrNotAcceptable is returned when no registered renderer satisfies the Accept header.
+var ErrNotAcceptable = errors.New("no renderer matches the Accept header")
+
+// ErrUnsupportedPayload is returned by renderers that only handle some
+// payloads, such as HTML templates. Render then tries the client's next
+// acceptable content type.
+var ErrUnsupportedPayload = errors.New("renderer does not support payload")
+
 // Registry stores renderers keyed by content type.
 type Registry struct {
 	renderers map[string]RenderFunc
+	buffered  map[string]BufferRenderFunc
+	order     []string
 	defaultCT string
+	pool      BufferPool
 }
 
 // New creates a registry with the provided default renderer.
 func New(defaultType string, defaultRenderer RenderFunc) *Registry {
 	r := &Registry{
 		renderers: make(map[string]RenderFunc),
+		buffered:  make(map[string]BufferRenderFunc),
 		defaultCT: canonicalContentType(defaultType),
+		pool:      DefaultBufferPool,
 	}
 	if defaultRenderer != nil {
-		r.renderers[r.defaultCT] = defaultRenderer
+		r.Register(r.defaultCT, defaultRenderer)
 	}
 	return r
 }
@@ -33,7 +55,58 @@ func (r *Registry) Register(contentType string, renderer RenderFunc) {
 	if r == nil || renderer == nil {
 		return
 	}
-	r.renderers[canonicalContentType(contentType)] = renderer
+	ct := canonicalContentType(contentType)
+	if _, exists := r.renderers[ct]; !exists {
+		r.order = append(r.order, ct)
+	}
+	r.renderers[ct] = renderer
+	delete(r.buffered, ct)
+}
+
+// RegisterBuffered adds or overrides a renderer that encodes into a pooled
+// buffer.
+func (r *Registry) RegisterBuffered(contentType string, renderer BufferRenderFunc) {
+	if r == nil || renderer == nil {
+		return
+	}
+	ct := canonicalContentType(contentType)
+	r.Register(ct, func(ctx context.Context, status int, payload any) ([]byte, string, error) {
+		var buf bytes.Buffer
+		contentType, err := renderer(ctx, &buf, status, payload)
+		return buf.Bytes(), contentType, err
+	})
+	r.buffered[ct] = renderer
+}
+
+// SetBufferPool replaces the pool used by buffered renderers. A nil pool
+// allocates a fresh buffer per response.
+func (r *Registry) SetBufferPool(pool BufferPool) {
+	if r == nil {
+		return
+	}
+	r.pool = pool
+}
+
+// SetDefault selects the registered content type used when the client expresses
+// no preference or accepts anything.
+func (r *Registry) SetDefault(contentType string) error {
+	if r == nil {
+		return fmt.Errorf("renderer registry is nil")
+	}
+	ct := canonicalContentType(contentType)
+	if _, ok := r.renderers[ct]; !ok {
+		return fmt.Errorf("no renderer registered for %s", contentType)
+	}
+	r.defaultCT = ct
+	return nil
+}
+
+// DefaultContentType returns the fallback content type.
+func (r *Registry) DefaultContentType() string {
+	if r == nil {
+		return ""
+	}
+	return r.defaultCT
 }
 
 // Clone returns a shallow copy of the registry.
@@ -43,14 +116,89 @@ func (r *Registry) Clone() *Registry {
 	}
 	clone := &Registry{
 		renderers: make(map[string]RenderFunc, len(r.renderers)),
+		buffered:  make(map[string]BufferRenderFunc, len(r.buffered)),
+		order:     append([]string(nil), r.order...),
 		defaultCT: r.defaultCT,
+		pool:      r.pool,
 	}
 	for k, v := range r.renderers {
 		clone.renderers[k] = v
 	}
+	for k, v := range r.buffered {
+		clone.buffered[k] = v
+	}
 	return clone
 }
 
+// Negotiate selects the renderer that best satisfies the Accept header. An
+// empty header selects the default renderer; a header that matches nothing
+// yields ErrNotAcceptable.
+func (r *Registry) Negotiate(acceptHeader string) (string, RenderFunc, error) {
+	if r == nil {
+		return "", nil, fmt.Errorf("renderer registry is nil")
+	}
+	if strings.TrimSpace(acceptHeader) == "" {
+		if renderer, ok := r.renderers[r.defaultCT]; ok {
+			return r.defaultCT, renderer, nil
+		}
+		return "", nil, fmt.Errorf("no renderer registered")
+	}
+	ranges, refused := parseAccept(acceptHeader)
+	for _, ct := range ranges {
+		if match := r.match(ct, refused); match != "" {
+			return match, r.renderers[match], nil
+		}
+	}
+	return "", nil, ErrNotAcceptable
+}
+
+// match returns the registered content type mediaRange selects, preferring
+// the default for wildcards and skipping types refused with q=0.
+func (r *Registry) match(mediaRange string, refused []string) string {
+	if _, ok := r.renderers[mediaRange]; ok {
+		if refuses(refused, mediaRange, mediaRange) {
+			return ""
+		}
+		return mediaRange
+	}
+	if !strings.HasSuffix(mediaRange, "/*") {
+		return ""
+	}
+	candidates := r.order
+	if _, ok := r.renderers[r.defaultCT]; ok {
+		candidates = append([]string{r.defaultCT}, r.order...)
+	}
+	for _, ct := range candidates {
+		if covers(mediaRange, ct) && !refuses(refused, mediaRange, ct) {
+			return ct
+		}
+	}
+	return ""
+}
+
+// refuses reports whether a q=0 range at least as specific as mediaRange
+// covers ct, so "text/html;q=0, */*" never selects text/html while
+// "text/*;q=0, text/csv" still selects text/csv.
+func refuses(refused []string, mediaRange, ct string) bool {
+	for _, excluded := range refused {
+		if specificity(excluded) >= specificity(mediaRange) && covers(excluded, ct) {
+			return true
+		}
+	}
+	return false
+}
+
+// covers reports whether mediaRange includes the content type ct.
+func covers(mediaRange, ct string) bool {
+	mediaType, _, _ := strings.Cut(ct, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	if mediaRange == "*/*" || mediaRange == mediaType || mediaRange == ct {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(mediaRange, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
+
 // Render picks a renderer based on Accept header. Returns content type and body.
 func (r *Registry) Render(ctx context.Context, w http.ResponseWriter, req *http.Request, status int, payload any) error {
 	if r == nil {
@@ -60,19 +208,41 @@ func (r *Registry) Render(ctx context.Context, w http.ResponseWriter, req *http.
 	if req != nil {
 		acceptHeader = req.Header.Get("Accept")
 	}
-	accepted := parseAccept(acceptHeader)
-	for _, ct := range accepted {
-		if renderer, ok := r.renderers[ct]; ok {
-			return r.render(ctx, w, status, payload, ct, renderer)
+	if strings.TrimSpace(acceptHeader) == "" {
+		return r.RenderDefault(ctx, w, status, payload)
+	}
+	tried := map[string]bool{}
+	ranges, refused := parseAccept(acceptHeader)
+	for _, mediaRange := range ranges {
+		ct := r.match(mediaRange, refused)
+		if ct == "" || tried[ct] {
+			continue
+		}
+		tried[ct] = true
+		err := r.render(ctx, w, status, payload, ct, r.renderers[ct])
+		if !errors.Is(err, ErrUnsupportedPayload) {
+			return err
 		}
 	}
-	if renderer, ok := r.renderers[r.defaultCT]; ok {
-		return r.render(ctx, w, status, payload, r.defaultCT, renderer)
+	return ErrNotAcceptable
+}
+
+// RenderDefault renders payload with the default renderer, ignoring Accept.
+func (r *Registry) RenderDefault(ctx context.Context, w http.ResponseWriter, status int, payload any) error {
+	if r == nil {
+		return fmt.Errorf("renderer registry is nil")
+	}
+	renderer, ok := r.renderers[r.defaultCT]
+	if !ok {
+		return fmt.Errorf("no renderer registered")
 	}
-	return fmt.Errorf("no renderer registered")
+	return r.render(ctx, w, status, payload, r.defaultCT, renderer)
 }
 
 func (r *Registry) render(ctx context.Context, w http.ResponseWriter, status int, payload any, ct string, renderFn RenderFunc) error {
+	if buffered, ok := r.buffered[ct]; ok {
+		return r.renderBuffered(ctx, w, status, payload, ct, buffered)
+	}
 	data, contentType, err := renderFn(ctx, status, payload)
 	if err != nil {
 		return err
@@ -80,37 +250,113 @@ func (r *Registry) render(ctx context.Context, w http.ResponseWriter, status int
 	if status == 0 {
 		status = http.StatusOK
 	}
+	if contentType == "" {
+		contentType = ct
+	}
 	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
-	if len(data) == 0 {
+	if len(data) == 0 && isJSON(contentType) {
 		data = []byte("null")
 	}
 	_, err = w.Write(data)
 	return err
 }
 
-func parseAccept(header string) []string {
+func (r *Registry) renderBuffered(ctx context.Context, w http.ResponseWriter, status int, payload any, ct string, renderFn BufferRenderFunc) error {
+	var buf *bytes.Buffer
+	if r.pool != nil {
+		buf = r.pool.Get()
+		defer r.pool.Put(buf)
+	} else {
+		buf = new(bytes.Buffer)
+	}
+	contentType, err := renderFn(ctx, buf, status, payload)
+	if err != nil {
+		return err
+	}
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if contentType == "" {
+		contentType = ct
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if buf.Len() == 0 && isJSON(contentType) {
+		buf.WriteString("null")
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+type acceptedType struct {
+	mediaRange string
+	q          float64
+}
+
+// parseAccept returns acceptable media ranges ordered by preference, and
+// the ranges refused with q=0.
+func parseAccept(header string) (ranges, refused []string) {
 	if header == "" {
-		return nil
+		return nil, nil
 	}
 	parts := strings.Split(header, ",")
-	out := make([]string, 0, len(parts))
-	wildcard := false
+	accepted := make([]acceptedType, 0, len(parts))
 	for _, part := range parts {
-		ct := strings.TrimSpace(strings.Split(part, ";")[0])
-		ct = canonicalContentType(ct)
-		if ct == "*/*" {
-			wildcard = true
+		segments := strings.Split(part, ";")
+		ct := canonicalContentType(segments[0])
+		if ct == "" {
+			continue
+		}
+		if ct == "*" {
+			ct = "*/*"
+		}
+		q := 1.0
+		for _, param := range segments[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.ToLower(strings.TrimSpace(key)) != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			refused = append(refused, ct)
 			continue
 		}
-		if ct != "" {
-			out = append(out, ct)
+		accepted = append(accepted, acceptedType{mediaRange: ct, q: q})
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		if accepted[i].q != accepted[j].q {
+			return accepted[i].q > accepted[j].q
 		}
+		return specificity(accepted[i].mediaRange) > specificity(accepted[j].mediaRange)
+	})
+	ranges = make([]string, 0, len(accepted))
+	for _, ar := range accepted {
+		ranges = append(ranges, ar.mediaRange)
 	}
-	if wildcard {
-		out = append(out, "*/*")
+	return ranges, refused
+}
+
+func specificity(mediaRange string) int {
+	switch {
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*"):
+		return 1
+	default:
+		return 2
 	}
-	return out
+}
+
+// isJSON reports whether contentType is a JSON document, whose empty body
+// is written as null.
+func isJSON(contentType string) bool {
+	mediaType, _, _ := strings.Cut(canonicalContentType(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
 }
 
 func canonicalContentType(ct string) string {
@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func stubRenderer(ct string) RenderFunc {
+	return func(ctx context.Context, status int, payload any) ([]byte, string, error) {
+		return []byte(ct), ct, nil
+	}
+}
+
+func TestRegistry_Negotiate(t *testing.T) {
+	reg := New("application/json", stubRenderer("application/json"))
+	reg.Register("application/xml", stubRenderer("application/xml"))
+	reg.Register("text/plain", stubRenderer("text/plain"))
+
+	cases := []struct {
+		accept string
+		want   string
+		err    error
+	}{
+		{accept: "", want: "application/json"},
+		{accept: "*/*", want: "application/json"},
+		{accept: "application/xml", want: "application/xml"},
+		{accept: "application/json;q=0.5, application/xml;q=0.9", want: "application/xml"},
+		{accept: "text/*", want: "text/plain"},
+		{accept: "image/png, */*;q=0.1", want: "application/json"},
+		{accept: "application/xml;q=0, text/plain", want: "text/plain"},
+		{accept: "image/png", err: ErrNotAcceptable},
+		{accept: "application/json;q=0", err: ErrNotAcceptable},
+		{accept: "application/json;q=0, */*", want: "application/xml"},
+		{accept: "text/*;q=0, */*;q=0.5", want: "application/json"},
+		{accept: "text/plain;q=0, text/*", err: ErrNotAcceptable},
+		{accept: "text/*;q=0, text/plain", want: "text/plain"},
+		{accept: "*/*;q=0, application/xml", want: "application/xml"},
+	}
+	for _, tc := range cases {
+		ct, _, err := reg.Negotiate(tc.accept)
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("accept %q: expected %v, got %v", tc.accept, tc.err, err)
+			}
+			continue
+		}
+		if err != nil || ct != tc.want {
+			t.Fatalf("accept %q: expected %s, got %s (%v)", tc.accept, tc.want, ct, err)
+		}
+	}
+}
+
+func TestRegistry_SetDefault(t *testing.T) {
+	reg := New("application/json", stubRenderer("application/json"))
+	if err := reg.SetDefault("application/xml"); err == nil {
+		t.Fatalf("expected error for unregistered default")
+	}
+	reg.Register("application/xml", stubRenderer("application/xml"))
+	if err := reg.SetDefault("application/xml"); err != nil {
+		t.Fatalf("set default: %v", err)
+	}
+	if ct, _, _ := reg.Negotiate("*/*"); ct != "application/xml" {
+		t.Fatalf("expected xml default, got %s", ct)
+	}
+}
@@ -0,0 +1,102 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aatuh/pureapi-framework/hooks"
+)
+
+// DefaultHeader is read when Config.Header and Config.QueryParam are empty.
+const DefaultHeader = "X-API-Key"
+
+type contextKey struct{}
+
+// Config controls where keys are read from and how they are resolved.
+type Config struct {
+	// Header carries the key. Defaults to X-API-Key when QueryParam is empty.
+	Header string
+	// QueryParam optionally carries the key; the header wins when both are set.
+	QueryParam string
+	// Store resolves keys. Required.
+	Store KeyStore
+	// Optional marks requests without a key as anonymous instead of rejecting them.
+	Optional bool
+}
+
+// Enricher authenticates the request and attaches the resolved Key to the
+// context. Missing or unknown keys map to the "unauthorized" catalog entry.
+func Enricher(cfg Config) hooks.ContextEnricher {
+	header := cfg.Header
+	if header == "" && cfg.QueryParam == "" {
+		header = DefaultHeader
+	}
+	return hooks.ContextEnricherFunc(func(ctx context.Context, r *http.Request) (context.Context, error) {
+		secret := extract(r, header, cfg.QueryParam)
+		if secret == "" {
+			if cfg.Optional {
+				return ctx, nil
+			}
+			return ctx, hooks.ErrUnauthorized("missing API key")
+		}
+		if cfg.Store == nil {
+			return ctx, hooks.ErrUnauthorized("invalid API key")
+		}
+		key, err := cfg.Store.Lookup(ctx, secret)
+		if err != nil {
+			if errors.Is(err, ErrKeyNotFound) {
+				return ctx, hooks.ErrUnauthorized("invalid API key")
+			}
+			return ctx, err
+		}
+		return WithKey(ctx, key), nil
+	})
+}
+
+// RequireScopes rejects requests whose key lacks any of the scopes. Requests
+// without a key are unauthorized; requests with insufficient scopes are forbidden.
+func RequireScopes(scopes ...string) hooks.AuthorizationPolicy {
+	return hooks.AuthorizationPolicyFunc(func(ctx context.Context, _ any) error {
+		key, ok := FromContext(ctx)
+		if !ok {
+			return hooks.ErrUnauthorized("missing API key")
+		}
+		for _, scope := range scopes {
+			if !key.HasScope(scope) {
+				return hooks.ErrForbidden("API key lacks scope " + scope)
+			}
+		}
+		return nil
+	})
+}
+
+// WithKey stores key in ctx.
+func WithKey(ctx context.Context, key Key) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext returns the key attached by Enricher.
+func FromContext(ctx context.Context) (Key, bool) {
+	if ctx == nil {
+		return Key{}, false
+	}
+	key, ok := ctx.Value(contextKey{}).(Key)
+	return key, ok
+}
+
+func extract(r *http.Request, header, queryParam string) string {
+	if r == nil {
+		return ""
+	}
+	if header != "" {
+		if value := strings.TrimSpace(r.Header.Get(header)); value != "" {
+			return value
+		}
+	}
+	if queryParam != "" && r.URL != nil {
+		return strings.TrimSpace(r.URL.Query().Get(queryParam))
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package apikey_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/hooks"
+	"github.com/aatuh/pureapi-framework/security/apikey"
+)
+
+func catalogID(err error) string {
+	var authErr hooks.AuthorizationError
+	if errors.As(err, &authErr) {
+		return authErr.CatalogID()
+	}
+	return ""
+}
+
+func TestEnricherResolvesKeysFromHeaderAndQuery(t *testing.T) {
+	store := apikey.NewMemoryStore(map[string]apikey.Key{
+		"s3cret": {ID: "k1", Owner: "alice", Scopes: []string{"read"}},
+	})
+	enricher := apikey.Enricher(apikey.Config{Header: "X-API-Key", QueryParam: "api_key", Store: store})
+
+	req := httptest.NewRequest(http.MethodGet, "/?api_key=s3cret", nil)
+	ctx, err := enricher.Enrich(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	key, ok := apikey.FromContext(ctx)
+	if !ok || key.Owner != "alice" {
+		t.Fatalf("expected key in context, got %+v", key)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := enricher.Enrich(context.Background(), req); catalogID(err) != "unauthorized" {
+		t.Fatalf("expected unauthorized for missing key, got %v", err)
+	}
+	req.Header.Set("X-API-Key", "wrong")
+	if _, err := enricher.Enrich(context.Background(), req); catalogID(err) != "unauthorized" {
+		t.Fatalf("expected unauthorized for invalid key, got %v", err)
+	}
+
+	storeErr := errors.New("store down")
+	failing := apikey.Enricher(apikey.Config{Store: apikey.KeyStoreFunc(func(context.Context, string) (apikey.Key, error) {
+		return apikey.Key{}, storeErr
+	})})
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(apikey.DefaultHeader, "any")
+	if _, err := failing.Enrich(context.Background(), req); !errors.Is(err, storeErr) {
+		t.Fatalf("expected store error to propagate, got %v", err)
+	}
+}
+
+func TestRequireScopes(t *testing.T) {
+	policy := apikey.RequireScopes("write")
+	if err := policy.Authorize(context.Background(), nil); catalogID(err) != "unauthorized" {
+		t.Fatalf("expected unauthorized without key, got %v", err)
+	}
+	ctx := apikey.WithKey(context.Background(), apikey.Key{Scopes: []string{"read"}})
+	if err := policy.Authorize(ctx, nil); catalogID(err) != "forbidden" {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	ctx = apikey.WithKey(context.Background(), apikey.Key{Scopes: []string{"read", "write"}})
+	if err := policy.Authorize(ctx, nil); err != nil {
+		t.Fatalf("expected authorized, got %v", err)
+	}
+}
+
+func TestEnvStoreParsesEntries(t *testing.T) {
+	t.Setenv("TEST_API_KEYS", "alice:abc:read|write, bare")
+	store, err := apikey.NewEnvStore("TEST_API_KEYS")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	key, err := store.Lookup(context.Background(), "abc")
+	if err != nil || key.Owner != "alice" || !key.HasScope("write") {
+		t.Fatalf("unexpected key %+v (%v)", key, err)
+	}
+	if _, err := store.Lookup(context.Background(), "bare"); err != nil {
+		t.Fatalf("expected bare secret to resolve: %v", err)
+	}
+	if _, err := apikey.NewEnvStore("TEST_API_KEYS_MISSING"); err == nil {
+		t.Fatalf("expected error for unset variable")
+	}
+}
@@ -0,0 +1,2 @@
+// Package apikey authenticates requests with API keys resolved from a pluggable key store.
+package apikey
@@ -0,0 +1,123 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrKeyNotFound is returned by stores when the presented key is unknown.
+var ErrKeyNotFound = errors.New("api key not found")
+
+// Key describes the identity bound to an API key.
+type Key struct {
+	ID       string
+	Owner    string
+	Scopes   []string
+	Metadata map[string]string
+}
+
+// HasScope reports whether the key grants scope.
+func (k Key) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyStore resolves presented secrets to keys.
+type KeyStore interface {
+	Lookup(ctx context.Context, secret string) (Key, error)
+}
+
+// KeyStoreFunc lifts a function into a KeyStore.
+type KeyStoreFunc func(ctx context.Context, secret string) (Key, error)
+
+// Lookup implements KeyStore.
+func (f KeyStoreFunc) Lookup(ctx context.Context, secret string) (Key, error) {
+	return f(ctx, secret)
+}
+
+// MemoryStore keeps keys in memory, indexed by a digest of the secret.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	keys map[[sha256.Size]byte]Key
+}
+
+// NewMemoryStore creates a store seeded with secret → key pairs.
+func NewMemoryStore(keys map[string]Key) *MemoryStore {
+	store := &MemoryStore{keys: make(map[[sha256.Size]byte]Key, len(keys))}
+	for secret, key := range keys {
+		store.Add(secret, key)
+	}
+	return store
+}
+
+// Add registers or replaces the key for secret.
+func (s *MemoryStore) Add(secret string, key Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[sha256.Sum256([]byte(secret))] = key
+}
+
+// Remove revokes secret.
+func (s *MemoryStore) Remove(secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, sha256.Sum256([]byte(secret)))
+}
+
+// Lookup implements KeyStore.
+func (s *MemoryStore) Lookup(_ context.Context, secret string) (Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[sha256.Sum256([]byte(secret))]
+	if !ok {
+		return Key{}, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// NewEnvStore loads keys from the named environment variable. The value is a
+// comma-separated list of "owner:secret[:scope|scope]" entries; a bare secret
+// is also accepted.
+func NewEnvStore(variable string) (*MemoryStore, error) {
+	value, ok := os.LookupEnv(variable)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", variable)
+	}
+	store := NewMemoryStore(nil)
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		var key Key
+		var secret string
+		switch len(parts) {
+		case 1:
+			secret = parts[0]
+		default:
+			key.Owner, secret = parts[0], parts[1]
+			if len(parts) == 3 && parts[2] != "" {
+				key.Scopes = strings.Split(parts[2], "|")
+			}
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("%s entry %d has an empty secret", variable, i)
+		}
+		key.ID = key.Owner
+		if key.ID == "" {
+			key.ID = fmt.Sprintf("%s[%d]", variable, i)
+		}
+		store.Add(secret, key)
+	}
+	return store, nil
+}
@@ -0,0 +1,304 @@
+package cookies
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
+	"github.com/aatuh/pureapi-framework/engine"
+)
+
+// MaxSize is the largest encoded cookie value browsers are expected to keep.
+const MaxSize = 4096
+
+var (
+	// ErrNoCookie is reported when the request carries no cookie of the name.
+	ErrNoCookie = http.ErrNoCookie
+	// ErrInvalid is reported for values that fail verification or decryption.
+	ErrInvalid = errors.New("cookies: invalid value")
+	// ErrExpired is reported for protected values older than Config.MaxAge.
+	ErrExpired = errors.New("cookies: expired value")
+	// ErrTooLarge is reported when an encoded value exceeds MaxSize.
+	ErrTooLarge = errors.New("cookies: value too large")
+	// ErrNoRequest is reported by Get outside an engine endpoint.
+	ErrNoRequest = errors.New("cookies: no request in context")
+)
+
+// Mode selects how values are protected.
+type Mode int
+
+const (
+	// Plain stores values base64url encoded without protection.
+	Plain Mode = iota
+	// Signed appends an HMAC-SHA256 so clients can read but not alter values.
+	Signed
+	// Encrypted seals values with AES-GCM so clients can neither read nor
+	// alter them.
+	Encrypted
+)
+
+// Config controls a Jar.
+type Config struct {
+	Mode Mode
+	// Keys sign or encrypt values. The first key protects new values and
+	// every key is tried when reading, so keys rotate by prepending. Encrypted
+	// needs 16, 24, or 32 byte keys.
+	Keys [][]byte
+	// Path defaults to "/".
+	Path   string
+	Domain string
+	// MaxAge sets the cookie lifetime. Signed and encrypted values older than
+	// MaxAge are rejected even if the client keeps them. Zero makes session
+	// cookies.
+	MaxAge time.Duration
+	// SameSite defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+	// Insecure omits the Secure attribute, e.g. for local HTTP development.
+	Insecure bool
+	// ScriptAccessible omits the HttpOnly attribute.
+	ScriptAccessible bool
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Jar encodes, decodes, reads, and writes cookies with one configuration.
+type Jar struct {
+	cfg   Config
+	aeads []cipher.AEAD
+}
+
+// New validates cfg and returns a Jar.
+func New(cfg Config) (*Jar, error) {
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	j := &Jar{cfg: cfg}
+	switch cfg.Mode {
+	case Plain:
+	case Signed, Encrypted:
+		if len(cfg.Keys) == 0 {
+			return nil, errors.New("cookies: signed and encrypted modes need at least one key")
+		}
+		for i, key := range cfg.Keys {
+			if len(key) == 0 {
+				return nil, fmt.Errorf("cookies: key %d is empty", i)
+			}
+			if cfg.Mode != Encrypted {
+				continue
+			}
+			block, err := aes.NewCipher(key)
+			if err != nil {
+				return nil, fmt.Errorf("cookies: key %d: %w", i, err)
+			}
+			aead, err := cipher.NewGCM(block)
+			if err != nil {
+				return nil, fmt.Errorf("cookies: key %d: %w", i, err)
+			}
+			j.aeads = append(j.aeads, aead)
+		}
+	default:
+		return nil, fmt.Errorf("cookies: unknown mode %d", cfg.Mode)
+	}
+	return j, nil
+}
+
+// Encode protects value for the cookie name according to the jar's mode.
+// The name is bound to the result, so values cannot be moved between
+// cookies.
+func (j *Jar) Encode(name string, value []byte) (string, error) {
+	var encoded string
+	switch j.cfg.Mode {
+	case Signed:
+		payload := base64.RawURLEncoding.EncodeToString(j.stamp(value))
+		encoded = payload + "." + base64.RawURLEncoding.EncodeToString(sign(j.cfg.Keys[0], name, payload))
+	case Encrypted:
+		aead := j.aeads[0]
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+8+aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		encoded = base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, j.stamp(value), []byte(name)))
+	default:
+		encoded = base64.RawURLEncoding.EncodeToString(value)
+	}
+	if len(name)+len(encoded)+1 > MaxSize {
+		return "", ErrTooLarge
+	}
+	return encoded, nil
+}
+
+// Decode verifies or decrypts a value produced by Encode for the same name.
+func (j *Jar) Decode(name, encoded string) ([]byte, error) {
+	switch j.cfg.Mode {
+	case Signed:
+		payload, mac, ok := strings.Cut(encoded, ".")
+		if !ok {
+			return nil, ErrInvalid
+		}
+		sum, err := base64.RawURLEncoding.DecodeString(mac)
+		if err != nil {
+			return nil, ErrInvalid
+		}
+		for _, key := range j.cfg.Keys {
+			if hmac.Equal(sum, sign(key, name, payload)) {
+				stamped, err := base64.RawURLEncoding.DecodeString(payload)
+				if err != nil {
+					return nil, ErrInvalid
+				}
+				return j.unstamp(stamped)
+			}
+		}
+		return nil, ErrInvalid
+	case Encrypted:
+		sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, ErrInvalid
+		}
+		for _, aead := range j.aeads {
+			if len(sealed) < aead.NonceSize() {
+				continue
+			}
+			nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+			if stamped, err := aead.Open(nil, nonce, ciphertext, []byte(name)); err == nil {
+				return j.unstamp(stamped)
+			}
+		}
+		return nil, ErrInvalid
+	default:
+		value, err := base64.RawURLEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, ErrInvalid
+		}
+		return value, nil
+	}
+}
+
+// Cookie returns the cookie carrying value under name with the jar's
+// attributes.
+func (j *Jar) Cookie(name string, value []byte) (*http.Cookie, error) {
+	encoded, err := j.Encode(name, value)
+	if err != nil {
+		return nil, err
+	}
+	c := j.base(name)
+	c.Value = encoded
+	if j.cfg.MaxAge > 0 {
+		c.MaxAge = int(j.cfg.MaxAge / time.Second)
+		c.Expires = j.cfg.Now().Add(j.cfg.MaxAge).UTC()
+	}
+	return c, nil
+}
+
+// Set adds the cookie to the response of the request served by ctx through
+// engine.ResponseMeta.
+func (j *Jar) Set(ctx context.Context, name string, value []byte) error {
+	c, err := j.Cookie(name, value)
+	if err != nil {
+		return err
+	}
+	engine.ResponseMeta(ctx).AddCookie(c)
+	return nil
+}
+
+// Get reads and decodes the cookie name from the request served by ctx.
+func (j *Jar) Get(ctx context.Context, name string) ([]byte, error) {
+	r := frameworkcontext.RequestFromContext(ctx)
+	if r == nil {
+		return nil, ErrNoRequest
+	}
+	return j.Read(r, name)
+}
+
+// Read reads and decodes the cookie name from r.
+func (j *Jar) Read(r *http.Request, name string) ([]byte, error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return nil, err
+	}
+	return j.Decode(name, c.Value)
+}
+
+// Delete instructs the client to drop the cookie name.
+func (j *Jar) Delete(ctx context.Context, name string) {
+	c := j.base(name)
+	c.MaxAge = -1
+	c.Expires = time.Unix(0, 0).UTC()
+	engine.ResponseMeta(ctx).AddCookie(c)
+}
+
+// SetValue stores value as JSON in the cookie name.
+func SetValue[T any](ctx context.Context, j *Jar, name string, value T) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return j.Set(ctx, name, data)
+}
+
+// GetValue reads the JSON value stored by SetValue in the cookie name.
+func GetValue[T any](ctx context.Context, j *Jar, name string) (T, error) {
+	var value T
+	data, err := j.Get(ctx, name)
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, ErrInvalid
+	}
+	return value, nil
+}
+
+func (j *Jar) base(name string) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Path:     j.cfg.Path,
+		Domain:   j.cfg.Domain,
+		SameSite: j.cfg.SameSite,
+		Secure:   !j.cfg.Insecure,
+		HttpOnly: !j.cfg.ScriptAccessible,
+	}
+}
+
+// stamp prefixes value with the issue time in Unix seconds.
+func (j *Jar) stamp(value []byte) []byte {
+	out := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(value)), uint64(j.cfg.Now().Unix()))
+	return append(out, value...)
+}
+
+// unstamp strips the issue time, rejecting values older than MaxAge.
+func (j *Jar) unstamp(stamped []byte) ([]byte, error) {
+	if len(stamped) < 8 {
+		return nil, ErrInvalid
+	}
+	issued := time.Unix(int64(binary.BigEndian.Uint64(stamped[:8])), 0)
+	if j.cfg.MaxAge > 0 && j.cfg.Now().Sub(issued) > j.cfg.MaxAge {
+		return nil, ErrExpired
+	}
+	return stamped[8:], nil
+}
+
+func sign(key []byte, name, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{'|'})
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
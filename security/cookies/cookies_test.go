@@ -0,0 +1,114 @@
+package cookies_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
+	"github.com/aatuh/pureapi-framework/security/cookies"
+)
+
+var (
+	oldKey = []byte("0123456789abcdef0123456789abcdef")
+	newKey = []byte("fedcba9876543210fedcba9876543210")
+)
+
+func TestJarRoundTripsEveryMode(t *testing.T) {
+	for _, mode := range []cookies.Mode{cookies.Plain, cookies.Signed, cookies.Encrypted} {
+		jar, err := cookies.New(cookies.Config{Mode: mode, Keys: [][]byte{newKey}})
+		if err != nil {
+			t.Fatalf("mode %d: %v", mode, err)
+		}
+		encoded, err := jar.Encode("session", []byte("alice"))
+		if err != nil {
+			t.Fatalf("mode %d: %v", mode, err)
+		}
+		if mode == cookies.Encrypted && strings.Contains(encoded, "YWxpY2U") {
+			t.Fatalf("expected encrypted value to hide the plaintext: %q", encoded)
+		}
+		value, err := jar.Decode("session", encoded)
+		if err != nil || string(value) != "alice" {
+			t.Fatalf("mode %d: expected alice, got %q %v", mode, value, err)
+		}
+	}
+}
+
+func TestJarRejectsTamperingAndMovedValues(t *testing.T) {
+	for _, mode := range []cookies.Mode{cookies.Signed, cookies.Encrypted} {
+		jar, _ := cookies.New(cookies.Config{Mode: mode, Keys: [][]byte{newKey}})
+		encoded, _ := jar.Encode("session", []byte("alice"))
+		if _, err := jar.Decode("other", encoded); !errors.Is(err, cookies.ErrInvalid) {
+			t.Fatalf("mode %d: expected value bound to its name, got %v", mode, err)
+		}
+		tampered := "A" + encoded[1:]
+		if tampered == encoded {
+			tampered = "B" + encoded[1:]
+		}
+		if _, err := jar.Decode("session", tampered); !errors.Is(err, cookies.ErrInvalid) {
+			t.Fatalf("mode %d: expected tampering detected, got %v", mode, err)
+		}
+	}
+}
+
+func TestJarRotatesKeysAndExpiresValues(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	clock := func() time.Time { return now }
+	old, _ := cookies.New(cookies.Config{Mode: cookies.Encrypted, Keys: [][]byte{oldKey}, Now: clock})
+	rotated, _ := cookies.New(cookies.Config{Mode: cookies.Encrypted, Keys: [][]byte{newKey, oldKey}, MaxAge: time.Hour, Now: clock})
+
+	encoded, _ := old.Encode("session", []byte("alice"))
+	if value, err := rotated.Decode("session", encoded); err != nil || string(value) != "alice" {
+		t.Fatalf("expected old key accepted after rotation, got %q %v", value, err)
+	}
+	fresh, _ := rotated.Encode("session", []byte("bob"))
+	if _, err := old.Decode("session", fresh); !errors.Is(err, cookies.ErrInvalid) {
+		t.Fatalf("expected new values sealed with the new key, got %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := rotated.Decode("session", fresh); !errors.Is(err, cookies.ErrExpired) {
+		t.Fatalf("expected expired value, got %v", err)
+	}
+}
+
+func TestJarCookieDefaultsAndTypedValues(t *testing.T) {
+	type prefs struct {
+		Theme string `json:"theme"`
+	}
+	jar, _ := cookies.New(cookies.Config{Mode: cookies.Signed, Keys: [][]byte{newKey}, MaxAge: time.Minute})
+	c, err := jar.Cookie("prefs", []byte(`{"theme":"dark"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c.Secure || !c.HttpOnly || c.SameSite != http.SameSiteLaxMode || c.Path != "/" || c.MaxAge != 60 {
+		t.Fatalf("unexpected cookie attributes: %+v", c)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(c)
+	ctx := frameworkcontext.WithRequest(context.Background(), req)
+	value, err := cookies.GetValue[prefs](ctx, jar, "prefs")
+	if err != nil || value.Theme != "dark" {
+		t.Fatalf("expected typed value, got %+v %v", value, err)
+	}
+	if _, err := jar.Get(ctx, "missing"); !errors.Is(err, cookies.ErrNoCookie) {
+		t.Fatalf("expected ErrNoCookie, got %v", err)
+	}
+	if _, err := jar.Get(context.Background(), "prefs"); !errors.Is(err, cookies.ErrNoRequest) {
+		t.Fatalf("expected ErrNoRequest, got %v", err)
+	}
+}
+
+func TestNewValidatesKeys(t *testing.T) {
+	if _, err := cookies.New(cookies.Config{Mode: cookies.Signed}); err == nil {
+		t.Fatalf("expected error without keys")
+	}
+	if _, err := cookies.New(cookies.Config{Mode: cookies.Encrypted, Keys: [][]byte{[]byte("short")}}); err == nil {
+		t.Fatalf("expected error for invalid AES key")
+	}
+}
@@ -0,0 +1,3 @@
+// Package cookies reads and writes cookies from handlers, optionally signed
+// with HMAC-SHA256 or encrypted with AES-GCM, with rotating keys.
+package cookies
@@ -0,0 +1,2 @@
+// Package serverutil runs HTTP servers with signal-driven graceful shutdown.
+package serverutil
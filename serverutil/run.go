@@ -0,0 +1,149 @@
+package serverutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// Lifecycle event types emitted by Run.
+const (
+	EventStarting     event.EventType = "event_server_starting"
+	EventStarted      event.EventType = "event_server_started"
+	EventShuttingDown event.EventType = "event_server_shutting_down"
+	EventStopped      event.EventType = "event_server_stopped"
+	EventError        event.EventType = "event_server_error"
+)
+
+const defaultDrainTimeout = 15 * time.Second
+
+// ShutdownHook runs once shutdown begins, before connections are drained.
+// Typical uses flush access loggers or close database pools.
+type ShutdownHook func(ctx context.Context) error
+
+// Config controls Run.
+type Config struct {
+	// Addr is the listen address. Ignored when Listener is set.
+	Addr string
+	// Handler serves requests.
+	Handler http.Handler
+	// Server optionally provides a preconfigured server; Addr and Handler
+	// fill in its empty fields.
+	Server *http.Server
+	// Listener optionally provides an already bound listener.
+	Listener net.Listener
+	// DrainTimeout bounds hooks and connection draining. Defaults to 15s.
+	DrainTimeout time.Duration
+	// Signals trigger shutdown. Defaults to SIGINT and SIGTERM.
+	Signals []os.Signal
+	// PreShutdown hooks run in order once shutdown begins.
+	PreShutdown []ShutdownHook
+	// Emitter receives lifecycle events. Optional.
+	Emitter event.EventEmitter
+}
+
+// Run serves until ctx is cancelled, a configured signal arrives, or the server
+// fails. On shutdown it runs the pre-shutdown hooks and drains in-flight
+// requests within DrainTimeout.
+func Run(ctx context.Context, cfg Config) error {
+	srv := cfg.Server
+	if srv == nil {
+		srv = &http.Server{ReadHeaderTimeout: 10 * time.Second}
+	}
+	if srv.Addr == "" {
+		srv.Addr = cfg.Addr
+	}
+	if srv.Handler == nil {
+		srv.Handler = cfg.Handler
+	}
+	drain := cfg.DrainTimeout
+	if drain <= 0 {
+		drain = defaultDrainTimeout
+	}
+	signals := cfg.Signals
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	emit(cfg.Emitter, EventStarting, "Server starting", map[string]any{"addr": srv.Addr})
+	listener := cfg.Listener
+	if listener == nil {
+		addr := srv.Addr
+		if addr == "" {
+			addr = ":http"
+		}
+		var err error
+		listener, err = net.Listen("tcp", addr)
+		if err != nil {
+			emit(cfg.Emitter, EventError, "Server failed to listen", map[string]any{"error": err.Error()})
+			return fmt.Errorf("listen: %w", err)
+		}
+	}
+
+	sigCtx, stop := signal.NotifyContext(ctx, signals...)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+	emit(cfg.Emitter, EventStarted, "Server started", map[string]any{"addr": listener.Addr().String()})
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		if err != nil {
+			emit(cfg.Emitter, EventError, "Server failed", map[string]any{"error": err.Error()})
+		}
+		emit(cfg.Emitter, EventStopped, "Server stopped", nil)
+		return err
+	case <-sigCtx.Done():
+	}
+
+	emit(cfg.Emitter, EventShuttingDown, "Server shutting down", map[string]any{"drain_timeout": drain.String()})
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), drain)
+	defer cancel()
+
+	var errs []error
+	for _, hook := range cfg.PreShutdown {
+		if hook == nil {
+			continue
+		}
+		if err := hook(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("pre-shutdown hook: %w", err))
+		}
+	}
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("shutdown: %w", err))
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		errs = append(errs, err)
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		emit(cfg.Emitter, EventError, "Server shutdown failed", map[string]any{"error": err.Error()})
+	}
+	emit(cfg.Emitter, EventStopped, "Server stopped", nil)
+	return err
+}
+
+func emit(emitter event.EventEmitter, typ event.EventType, message string, data map[string]any) {
+	if emitter == nil {
+		return
+	}
+	evt := event.NewEvent(typ, message)
+	if data != nil {
+		evt = evt.WithData(data)
+	}
+	emitter.Emit(evt)
+}
@@ -0,0 +1,60 @@
+package serverutil_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/serverutil"
+)
+
+func TestRunServesAndDrainsOnCancel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	hookCalled := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- serverutil.Run(ctx, serverutil.Config{
+			Listener: listener,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "ok")
+			}),
+			DrainTimeout: time.Second,
+			PreShutdown: []serverutil.ShutdownHook{func(context.Context) error {
+				close(hookCalled)
+				return nil
+			}},
+		})
+	}()
+
+	resp, err := http.Get("http://" + listener.Addr().String())
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Fatalf("unexpected body %q", body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("server did not stop")
+	}
+	select {
+	case <-hookCalled:
+	default:
+		t.Fatalf("expected pre-shutdown hook to run")
+	}
+}
@@ -0,0 +1,2 @@
+// Package settings holds runtime-adjustable knobs that can be reloaded without a restart.
+package settings
@@ -0,0 +1,205 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Settings are the knobs that may change while the service runs. Treat a
+// Settings value obtained from a Store as read-only.
+type Settings struct {
+	// LogLevel is debug, info, warn, or error. Empty means info.
+	LogLevel string `json:"log_level" yaml:"log_level" toml:"log_level"`
+	// RateLimits are keyed by limiter name.
+	RateLimits map[string]RateLimit `json:"rate_limits" yaml:"rate_limits" toml:"rate_limits"`
+	// Maintenance drains traffic when enabled.
+	Maintenance Maintenance `json:"maintenance" yaml:"maintenance" toml:"maintenance"`
+	// Flags are feature flags keyed by name; values are bool, string, or float64.
+	Flags map[string]any `json:"flags" yaml:"flags" toml:"flags"`
+}
+
+// RateLimit is a token bucket: Rate requests per second with Burst capacity.
+type RateLimit struct {
+	Rate  float64 `json:"rate" yaml:"rate" toml:"rate"`
+	Burst int     `json:"burst" yaml:"burst" toml:"burst"`
+}
+
+// Maintenance describes maintenance mode.
+type Maintenance struct {
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// RetryAfter is advertised to clients, e.g. "2m".
+	RetryAfter string `json:"retry_after" yaml:"retry_after" toml:"retry_after"`
+	// Message replaces the catalog message in responses.
+	Message string `json:"message" yaml:"message" toml:"message"`
+}
+
+// Level parses LogLevel.
+func (s Settings) Level() (slog.Level, error) {
+	var level slog.Level
+	if s.LogLevel == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(strings.ToUpper(s.LogLevel))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", s.LogLevel)
+	}
+	return level, nil
+}
+
+// RetryAfterDuration parses Maintenance.RetryAfter; empty means zero.
+func (m Maintenance) RetryAfterDuration() (time.Duration, error) {
+	if m.RetryAfter == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(m.RetryAfter)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid maintenance retry_after %q", m.RetryAfter)
+	}
+	return d, nil
+}
+
+// Validate reports the first invalid setting.
+func (s Settings) Validate() error {
+	if _, err := s.Level(); err != nil {
+		return err
+	}
+	if _, err := s.Maintenance.RetryAfterDuration(); err != nil {
+		return err
+	}
+	for name, limit := range s.RateLimits {
+		if limit.Rate < 0 || limit.Burst < 0 {
+			return fmt.Errorf("rate limit %s must not be negative", name)
+		}
+	}
+	for name, value := range s.Flags {
+		switch value.(type) {
+		case bool, string, float64:
+		default:
+			return fmt.Errorf("flag %s has unsupported type %T", name, value)
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy of s.
+func (s Settings) Clone() Settings {
+	s.RateLimits = maps.Clone(s.RateLimits)
+	s.Flags = maps.Clone(s.Flags)
+	return s
+}
+
+// Store holds the current settings behind an atomic pointer so readers never
+// block writers.
+type Store struct {
+	current atomic.Pointer[Settings]
+	level   slog.LevelVar
+
+	mu          sync.Mutex
+	subscribers map[int]func(old, new Settings)
+	nextID      int
+}
+
+// NewStore returns a store holding initial, which must be valid.
+func NewStore(initial Settings) (*Store, error) {
+	s := &Store{}
+	if err := s.Set(initial); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Snapshot returns the current settings. Successive calls may observe
+// different snapshots; read once per request for a consistent view.
+func (s *Store) Snapshot() Settings {
+	if current := s.current.Load(); current != nil {
+		return *current
+	}
+	return Settings{}
+}
+
+// Set validates and publishes next, then notifies subscribers.
+func (s *Store) Set(next Settings) error {
+	if err := next.Validate(); err != nil {
+		return err
+	}
+	level, _ := next.Level()
+	next = next.Clone()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.Snapshot()
+	s.current.Store(&next)
+	s.level.Set(level)
+	for _, fn := range s.subscribers {
+		fn(old, next)
+	}
+	return nil
+}
+
+// Update applies fn to a copy of the current settings and publishes it.
+func (s *Store) Update(fn func(*Settings)) error {
+	next := s.Snapshot().Clone()
+	fn(&next)
+	return s.Set(next)
+}
+
+// LevelVar tracks LogLevel; pass it as slog.HandlerOptions.Level so log
+// output follows reloads.
+func (s *Store) LevelVar() *slog.LevelVar {
+	return &s.level
+}
+
+// Subscribe calls fn after every change. It returns an unsubscribe function.
+func (s *Store) Subscribe(fn func(old, new Settings)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[int]func(old, new Settings))
+	}
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = fn
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers, id)
+	}
+}
+
+type contextKey struct{}
+
+// WithSettings stores a settings snapshot in ctx.
+func WithSettings(ctx context.Context, snapshot Settings) context.Context {
+	return context.WithValue(ctx, contextKey{}, snapshot)
+}
+
+// FromContext returns the snapshot taken for the current request.
+func FromContext(ctx context.Context) (Settings, bool) {
+	if ctx == nil {
+		return Settings{}, false
+	}
+	snapshot, ok := ctx.Value(contextKey{}).(Settings)
+	return snapshot, ok
+}
+
+// Middleware snapshots the store once per request so middlewares, hooks,
+// and handlers downstream see consistent settings through FromContext.
+func Middleware(store *Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithSettings(r.Context(), store.Snapshot())))
+		})
+	}
+}
+
+// changed reports whether next differs from the current settings.
+func (s *Store) changed(next Settings) bool {
+	return !reflect.DeepEqual(s.Snapshot(), next)
+}
@@ -0,0 +1,112 @@
+package settings_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/settings"
+)
+
+func TestStore_SetValidatesAndNotifies(t *testing.T) {
+	store, err := settings.NewStore(settings.Settings{LogLevel: "info"})
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	var changes []string
+	unsubscribe := store.Subscribe(func(old, new settings.Settings) {
+		changes = append(changes, old.LogLevel+"->"+new.LogLevel)
+	})
+
+	if err := store.Update(func(s *settings.Settings) { s.LogLevel = "debug" }); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if store.Snapshot().LogLevel != "debug" || store.LevelVar().Level() != slog.LevelDebug {
+		t.Fatalf("unexpected level: %+v %v", store.Snapshot(), store.LevelVar().Level())
+	}
+	if err := store.Set(settings.Settings{LogLevel: "loud"}); err == nil {
+		t.Fatalf("expected invalid log level to be rejected")
+	}
+	if err := store.Set(settings.Settings{Flags: map[string]any{"beta": 1}}); err == nil {
+		t.Fatalf("expected unsupported flag type to be rejected")
+	}
+	if store.Snapshot().LogLevel != "debug" {
+		t.Fatalf("rejected settings must not be published: %+v", store.Snapshot())
+	}
+
+	unsubscribe()
+	_ = store.Update(func(s *settings.Settings) { s.LogLevel = "warn" })
+	if len(changes) != 1 || changes[0] != "info->debug" {
+		t.Fatalf("unexpected notifications: %v", changes)
+	}
+}
+
+func TestStore_WatchReloadsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write(`{"maintenance":{"enabled":true,"retry_after":"30s"}}`)
+
+	store, _ := settings.NewStore(settings.Settings{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make(chan error, 10)
+	go store.Watch(ctx, settings.WatchConfig{
+		Source:   settings.FileSource(path, nil),
+		Interval: 5 * time.Millisecond,
+		OnError:  func(err error) { errs <- err },
+	})
+
+	waitFor(t, func() bool { return store.Snapshot().Maintenance.Enabled })
+	write(`{"flags":{"beta":true}}`)
+	waitFor(t, func() bool { return store.Snapshot().Flags["beta"] == true })
+	if store.Snapshot().Maintenance.Enabled {
+		t.Fatalf("expected maintenance to be disabled: %+v", store.Snapshot())
+	}
+
+	write(`{"log_level":"loud"}`)
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatalf("expected reload error")
+	}
+	if store.Snapshot().Flags["beta"] != true {
+		t.Fatalf("invalid reload must keep previous settings: %+v", store.Snapshot())
+	}
+}
+
+func TestMiddleware_PinsSnapshotPerRequest(t *testing.T) {
+	store, _ := settings.NewStore(settings.Settings{LogLevel: "info"})
+	handler := settings.Middleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = store.Update(func(s *settings.Settings) { s.LogLevel = "error" })
+		snapshot, ok := settings.FromContext(r.Context())
+		if !ok {
+			t.Fatalf("expected settings in context")
+		}
+		_, _ = w.Write([]byte(snapshot.LogLevel))
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Body.String() != "info" {
+		t.Fatalf("expected snapshot taken at request start, got %q", rec.Body.String())
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,72 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Source loads the latest settings, e.g. from a file or a remote store.
+type Source func(ctx context.Context) (Settings, error)
+
+// FileSource reads settings from path with unmarshal (JSON when nil).
+func FileSource(path string, unmarshal func(data []byte, v any) error) Source {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	return func(context.Context) (Settings, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Settings{}, err
+		}
+		var s Settings
+		if err := unmarshal(data, &s); err != nil {
+			return Settings{}, fmt.Errorf("settings: %s: %w", path, err)
+		}
+		return s, nil
+	}
+}
+
+// WatchConfig controls Watch.
+type WatchConfig struct {
+	// Source is polled for new settings. Required.
+	Source Source
+	// Interval between polls. Defaults to 10s.
+	Interval time.Duration
+	// OnError receives load and validation failures; the previous settings
+	// stay in effect.
+	OnError func(error)
+}
+
+const defaultWatchInterval = 10 * time.Second
+
+// Watch polls cfg.Source until ctx is done, publishing settings that differ
+// from the current ones. It loads once immediately.
+func (s *Store) Watch(ctx context.Context, cfg WatchConfig) {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	reload := func() {
+		next, err := cfg.Source(ctx)
+		if err == nil && s.changed(next) {
+			err = s.Set(next)
+		}
+		if err != nil && cfg.OnError != nil && ctx.Err() == nil {
+			cfg.OnError(err)
+		}
+	}
+	reload()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reload()
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ConnResolver lazily opens and caches one connection (or pool) per tenant.
+// Its Conn method can back a connection function such as db.ConnFn.
+type ConnResolver[C any] struct {
+	mu    sync.Mutex
+	open  func(ctx context.Context, tenantID string) (C, error)
+	conns map[string]C
+}
+
+// NewConnResolver builds a resolver that calls open the first time a tenant
+// is seen.
+func NewConnResolver[C any](open func(ctx context.Context, tenantID string) (C, error)) *ConnResolver[C] {
+	return &ConnResolver[C]{open: open, conns: make(map[string]C)}
+}
+
+// Conn returns the connection for the tenant in ctx.
+func (r *ConnResolver[C]) Conn(ctx context.Context) (C, error) {
+	var zero C
+	id := IDFromContext(ctx)
+	if id == "" {
+		return zero, fmt.Errorf("tenancy: no tenant in context")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if conn, ok := r.conns[id]; ok {
+		return conn, nil
+	}
+	if r.open == nil {
+		return zero, fmt.Errorf("tenancy: no connection opener configured")
+	}
+	conn, err := r.open(ctx, id)
+	if err != nil {
+		return zero, fmt.Errorf("tenancy: open connection for %s: %w", id, err)
+	}
+	r.conns[id] = conn
+	return conn, nil
+}
+
+// Each calls fn for every open connection, e.g. to close them on shutdown.
+func (r *ConnResolver[C]) Each(fn func(tenantID string, conn C) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, conn := range r.conns {
+		if err := fn(id, conn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
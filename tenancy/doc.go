@@ -0,0 +1,2 @@
+// Package tenancy resolves the tenant of each request and routes per-tenant resources.
+package tenancy
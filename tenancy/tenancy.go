@@ -0,0 +1,137 @@
+package tenancy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/aatuh/pureapi-framework/binder"
+	"github.com/aatuh/pureapi-framework/hooks"
+	"github.com/aatuh/pureapi-framework/obs/accesslog"
+)
+
+// AccessLogField is the access log field carrying the resolved tenant ID.
+const AccessLogField = "tenant_id"
+
+type contextKey struct{}
+
+// Tenant identifies the tenant a request acts on behalf of.
+type Tenant struct {
+	ID string
+}
+
+// Resolver extracts a tenant ID from the request. An empty ID means the
+// resolver did not find one.
+type Resolver interface {
+	Resolve(ctx context.Context, r *http.Request) (string, error)
+}
+
+// ResolverFunc lifts a function into a Resolver.
+type ResolverFunc func(ctx context.Context, r *http.Request) (string, error)
+
+// Resolve implements Resolver.
+func (f ResolverFunc) Resolve(ctx context.Context, r *http.Request) (string, error) {
+	return f(ctx, r)
+}
+
+// FromHeader resolves the tenant from a request header.
+func FromHeader(name string) Resolver {
+	return ResolverFunc(func(_ context.Context, r *http.Request) (string, error) {
+		return strings.TrimSpace(r.Header.Get(name)), nil
+	})
+}
+
+// FromSubdomain resolves the tenant from the left-most label of hosts under
+// baseDomain, e.g. "acme" for acme.example.com with baseDomain example.com.
+func FromSubdomain(baseDomain string) Resolver {
+	suffix := "." + strings.Trim(strings.ToLower(baseDomain), ".")
+	return ResolverFunc(func(_ context.Context, r *http.Request) (string, error) {
+		host := strings.ToLower(r.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		sub, ok := strings.CutSuffix(host, suffix)
+		if !ok || sub == "" {
+			return "", nil
+		}
+		if i := strings.LastIndex(sub, "."); i >= 0 {
+			sub = sub[i+1:]
+		}
+		return sub, nil
+	})
+}
+
+// FromClaim resolves the tenant from a claim of an already verified token.
+// claims returns the claims attached to ctx by the authentication enricher.
+func FromClaim(claim string, claims func(ctx context.Context) map[string]any) Resolver {
+	return ResolverFunc(func(ctx context.Context, _ *http.Request) (string, error) {
+		if claims == nil {
+			return "", nil
+		}
+		value, _ := claims(ctx)[claim].(string)
+		return value, nil
+	})
+}
+
+// Config controls tenant resolution.
+type Config struct {
+	// Resolvers are tried in order; the first non-empty ID wins.
+	Resolvers []Resolver
+	// Optional lets requests without a tenant through.
+	Optional bool
+	// Validate rejects malformed tenant IDs. Defaults to accepting any ID.
+	Validate func(id string) bool
+}
+
+// Enricher resolves the tenant, stores it in the context, and records it on
+// the access log entry. Unresolvable or invalid tenants render invalid_request.
+func Enricher(cfg Config) hooks.ContextEnricher {
+	return hooks.ContextEnricherFunc(func(ctx context.Context, r *http.Request) (context.Context, error) {
+		for _, resolver := range cfg.Resolvers {
+			if resolver == nil {
+				continue
+			}
+			id, err := resolver.Resolve(ctx, r)
+			if err != nil {
+				return ctx, err
+			}
+			if id == "" {
+				continue
+			}
+			if cfg.Validate != nil && !cfg.Validate(id) {
+				return ctx, binder.NewBindError("Invalid tenant", []binder.FieldError{
+					binder.NewFieldError("tenant", binder.SourceHeader, "tenant identifier is not valid"),
+				})
+			}
+			accesslog.AddField(ctx, AccessLogField, id)
+			return WithTenant(ctx, Tenant{ID: id}), nil
+		}
+		if cfg.Optional {
+			return ctx, nil
+		}
+		return ctx, binder.NewBindError("Tenant could not be resolved", []binder.FieldError{
+			binder.NewFieldError("tenant", binder.SourceHeader, "missing tenant"),
+		})
+	})
+}
+
+// WithTenant stores tenant in ctx.
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenant)
+}
+
+// FromContext returns the tenant resolved for the request.
+func FromContext(ctx context.Context) (Tenant, bool) {
+	if ctx == nil {
+		return Tenant{}, false
+	}
+	tenant, ok := ctx.Value(contextKey{}).(Tenant)
+	return tenant, ok
+}
+
+// IDFromContext returns the tenant ID, or "" when none was resolved.
+func IDFromContext(ctx context.Context) string {
+	tenant, _ := FromContext(ctx)
+	return tenant.ID
+}
@@ -0,0 +1,89 @@
+package tenancy_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/binder"
+	"github.com/aatuh/pureapi-framework/obs/accesslog"
+	"github.com/aatuh/pureapi-framework/tenancy"
+)
+
+func TestEnricherResolvesInOrder(t *testing.T) {
+	enricher := tenancy.Enricher(tenancy.Config{
+		Resolvers: []tenancy.Resolver{
+			tenancy.FromHeader("X-Tenant-ID"),
+			tenancy.FromSubdomain("example.com"),
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://acme.example.com:8080/", nil)
+	ctx, err := enricher.Enrich(accesslog.WithFields(context.Background()), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenancy.IDFromContext(ctx) != "acme" {
+		t.Fatalf("expected subdomain tenant, got %q", tenancy.IDFromContext(ctx))
+	}
+	if accesslog.FieldsFromContext(ctx)[tenancy.AccessLogField] != "acme" {
+		t.Fatalf("expected tenant in access log fields")
+	}
+
+	req.Header.Set("X-Tenant-ID", "globex")
+	ctx, _ = enricher.Enrich(context.Background(), req)
+	if tenancy.IDFromContext(ctx) != "globex" {
+		t.Fatalf("expected header to win, got %q", tenancy.IDFromContext(ctx))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	var bindErr *binder.BindError
+	if _, err := enricher.Enrich(context.Background(), req); !errors.As(err, &bindErr) {
+		t.Fatalf("expected bind error for missing tenant, got %v", err)
+	}
+}
+
+func TestFromClaimAndValidation(t *testing.T) {
+	type claimsKey struct{}
+	claims := func(ctx context.Context) map[string]any {
+		m, _ := ctx.Value(claimsKey{}).(map[string]any)
+		return m
+	}
+	enricher := tenancy.Enricher(tenancy.Config{
+		Resolvers: []tenancy.Resolver{tenancy.FromClaim("tid", claims)},
+		Validate:  func(id string) bool { return id != "bad" },
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	ctx := context.WithValue(context.Background(), claimsKey{}, map[string]any{"tid": "initech"})
+	if ctx, err := enricher.Enrich(ctx, req); err != nil || tenancy.IDFromContext(ctx) != "initech" {
+		t.Fatalf("expected claim tenant, got %q (%v)", tenancy.IDFromContext(ctx), err)
+	}
+	ctx = context.WithValue(context.Background(), claimsKey{}, map[string]any{"tid": "bad"})
+	if _, err := enricher.Enrich(ctx, req); err == nil {
+		t.Fatalf("expected invalid tenant to be rejected")
+	}
+}
+
+func TestConnResolverCachesPerTenant(t *testing.T) {
+	opened := 0
+	resolver := tenancy.NewConnResolver(func(_ context.Context, id string) (string, error) {
+		opened++
+		return "conn-" + id, nil
+	})
+	acme := tenancy.WithTenant(context.Background(), tenancy.Tenant{ID: "acme"})
+	for i := 0; i < 2; i++ {
+		conn, err := resolver.Conn(acme)
+		if err != nil || conn != "conn-acme" {
+			t.Fatalf("unexpected conn %q (%v)", conn, err)
+		}
+	}
+	if opened != 1 {
+		t.Fatalf("expected one open per tenant, got %d", opened)
+	}
+	if _, err := resolver.Conn(context.Background()); err == nil {
+		t.Fatalf("expected error without tenant")
+	}
+}
@@ -0,0 +1,140 @@
+package testkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/aatuh/pureapi-core/server"
+
+	"github.com/aatuh/pureapi-framework/binder"
+	"github.com/aatuh/pureapi-framework/engine"
+)
+
+// Client serves requests against a set of registered endpoints in memory.
+type Client struct {
+	handler http.Handler
+}
+
+// NewClient registers specs on a fresh handler.
+func NewClient(specs ...endpoint.EndpointSpec) *Client {
+	h := server.NewHandler(event.NewNoopEventEmitter())
+	h.Register(endpoint.ToEndpoints(specs...))
+	return &Client{handler: h}
+}
+
+// NewHandlerClient wraps an existing handler, e.g. one with global
+// middleware applied.
+func NewHandlerClient(handler http.Handler) *Client {
+	return &Client{handler: handler}
+}
+
+// Serve executes req and returns the recorded response.
+func (c *Client) Serve(req *http.Request) *Response {
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+	return &Response{
+		Status:      rec.Code,
+		Header:      rec.Header(),
+		Body:        rec.Body.Bytes(),
+		Request:     req,
+		RequestBody: reqBody,
+	}
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// Request and RequestBody describe the request that was served.
+	Request     *http.Request
+	RequestBody []byte
+}
+
+// APIError is a catalog error rendered by the endpoint.
+type APIError struct {
+	Status  int             `json:"-"`
+	ID      string          `json:"id"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Origin  string          `json:"origin,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.ID, e.Message)
+}
+
+// Option adjusts a request before it is served.
+type Option func(*http.Request)
+
+// WithHeader sets a request header.
+func WithHeader(key, value string) Option {
+	return func(r *http.Request) {
+		r.Header.Set(key, value)
+	}
+}
+
+// WithRequest applies fn to the request.
+func WithRequest(fn func(*http.Request)) Option {
+	return fn
+}
+
+// Call builds a request from in using its binding tags, serves it through
+// c (or a client holding only ep when c is nil), and decodes the output.
+// Error responses are returned as *APIError alongside the raw Response.
+func Call[TIn any, TOut any](
+	ctx context.Context,
+	c *Client,
+	ep *engine.DeclarativeEndpoint[TIn, TOut],
+	in TIn,
+	opts ...Option,
+) (TOut, *Response, error) {
+	var out TOut
+	if c == nil {
+		c = NewClient(ep)
+	}
+	req, err := binder.NewRequest(ctx, ep.Method, ep.Path, in)
+	if err != nil {
+		return out, nil, err
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+	resp := c.Serve(req)
+	if resp.Status >= 400 {
+		apiErr := &APIError{Status: resp.Status}
+		if err := json.Unmarshal(resp.Body, apiErr); err != nil || apiErr.ID == "" {
+			apiErr.Message = string(bytes.TrimSpace(resp.Body))
+		}
+		return out, resp, apiErr
+	}
+	if len(bytes.TrimSpace(resp.Body)) == 0 {
+		return out, resp, nil
+	}
+	body := resp.Body
+	if ep.Describe().Envelope {
+		var wrapped struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(body, &wrapped); err != nil {
+			return out, resp, fmt.Errorf("decode response envelope: %w", err)
+		}
+		body = wrapped.Data
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return out, resp, fmt.Errorf("decode response: %w", err)
+	}
+	return out, resp, nil
+}
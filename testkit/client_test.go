@@ -0,0 +1,59 @@
+package testkit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aatuh/pureapi-framework/engine"
+	frameworkerrors "github.com/aatuh/pureapi-framework/errors"
+	"github.com/aatuh/pureapi-framework/testkit"
+)
+
+type widgetInput struct {
+	ID     string   `path:"id"`
+	Tags   []string `query:"tag"`
+	Client string   `header:"X-Client"`
+	Body   struct {
+		Name string `json:"name"`
+	} `body:""`
+}
+
+type widget struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Tags   []string `json:"tags"`
+	Client string   `json:"client"`
+}
+
+func TestCallBindsInputAndDecodesOutput(t *testing.T) {
+	e := engine.NewEngine(engine.WithEnvelope())
+	ep := engine.Endpoint[widgetInput, widget](e, http.MethodPut, "/widgets/{id}",
+		func(ctx context.Context, in widgetInput) (widget, error) {
+			if in.ID == "missing" {
+				return widget{}, frameworkerrors.NotFound("widget missing")
+			}
+			return widget{ID: in.ID, Name: in.Body.Name, Tags: in.Tags, Client: in.Client}, nil
+		})
+
+	in := widgetInput{ID: "w 1", Tags: []string{"a", "b"}, Client: "cli"}
+	in.Body.Name = "gear"
+	out, resp, err := testkit.Call(context.Background(), nil, ep, in)
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if resp.Status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Status)
+	}
+	if out.ID != "w 1" || out.Name != "gear" || len(out.Tags) != 2 || out.Client != "cli" {
+		t.Fatalf("unexpected output %+v", out)
+	}
+
+	in.ID = "missing"
+	_, resp, err = testkit.Call(context.Background(), testkit.NewClient(ep), ep, in)
+	var apiErr *testkit.APIError
+	if !errors.As(err, &apiErr) || apiErr.ID != "not_found" || resp.Status != http.StatusNotFound {
+		t.Fatalf("expected not_found error, got %v", err)
+	}
+}
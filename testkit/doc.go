@@ -0,0 +1,2 @@
+// Package testkit exercises declarative endpoints in tests through the full middleware and hook chain.
+package testkit
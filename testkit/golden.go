@@ -0,0 +1,190 @@
+package testkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// RedactedValue replaces volatile values in snapshots.
+const RedactedValue = "<redacted>"
+
+// UpdateEnv names the environment variable that rewrites golden files when
+// set to a non-empty value.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// SnapshotConfig controls golden file recording.
+type SnapshotConfig struct {
+	// Dir holds golden files. Defaults to testdata/golden.
+	Dir string
+	// RedactHeaders are replaced with RedactedValue. Defaults to Date and
+	// X-Request-ID.
+	RedactHeaders []string
+	// RedactFields are JSON object keys replaced at any depth in request and
+	// response bodies, e.g. "created_at" or "origin".
+	RedactFields []string
+	// Update rewrites golden files instead of comparing. It is also enabled
+	// by the UPDATE_GOLDEN environment variable.
+	Update bool
+}
+
+// Snapshot is the recorded form of one request/response pair.
+type Snapshot struct {
+	Request  SnapshotRequest  `json:"request"`
+	Response SnapshotResponse `json:"response"`
+}
+
+// SnapshotRequest is the recorded request.
+type SnapshotRequest struct {
+	Method string            `json:"method"`
+	URL    string            `json:"url"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+}
+
+// SnapshotResponse is the recorded response.
+type SnapshotResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+}
+
+// MatchSnapshot compares resp with the golden file for name, writing it
+// when missing or when updating is enabled.
+func MatchSnapshot(t testing.TB, name string, resp *Response, cfg SnapshotConfig) {
+	t.Helper()
+	data, err := cfg.render(resp)
+	if err != nil {
+		t.Fatalf("snapshot %s: %v", name, err)
+	}
+	dir := cfg.Dir
+	if dir == "" {
+		dir = filepath.Join("testdata", "golden")
+	}
+	path := filepath.Join(dir, goldenFileName(name))
+	want, err := os.ReadFile(path)
+	if cfg.Update || os.Getenv(UpdateEnv) != "" || os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("snapshot %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("snapshot %s: %v", name, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("snapshot %s: %v", name, err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(want), bytes.TrimSpace(data)) {
+		t.Fatalf("snapshot %s does not match %s (set %s=1 to update)\n--- want\n%s\n--- got\n%s",
+			name, path, UpdateEnv, want, data)
+	}
+}
+
+func (cfg SnapshotConfig) render(resp *Response) ([]byte, error) {
+	redactHeaders := cfg.RedactHeaders
+	if redactHeaders == nil {
+		redactHeaders = []string{"Date", "X-Request-ID"}
+	}
+	fields := make(map[string]bool, len(cfg.RedactFields))
+	for _, f := range cfg.RedactFields {
+		fields[f] = true
+	}
+	var snap Snapshot
+	if req := resp.Request; req != nil {
+		snap.Request.Method = req.Method
+		snap.Request.URL = req.URL.RequestURI()
+		snap.Request.Header = flattenHeader(req.Header, redactHeaders)
+	}
+	body, err := redactBody(resp.RequestBody, fields)
+	if err != nil {
+		return nil, err
+	}
+	snap.Request.Body = body
+	snap.Response.Status = resp.Status
+	snap.Response.Header = flattenHeader(resp.Header, redactHeaders)
+	if snap.Response.Body, err = redactBody(resp.Body, fields); err != nil {
+		return nil, err
+	}
+	return marshal(snap, "  ")
+}
+
+// marshal encodes v without HTML escaping so placeholders stay readable.
+func marshal(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func flattenHeader(header http.Header, redact []string) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(header))
+	for k, values := range header {
+		out[k] = strings.Join(values, ", ")
+	}
+	for _, k := range redact {
+		k = http.CanonicalHeaderKey(k)
+		if _, ok := out[k]; ok {
+			out[k] = RedactedValue
+		}
+	}
+	return out
+}
+
+// redactBody normalizes JSON bodies and redacts fields; other bodies are
+// recorded as JSON strings.
+func redactBody(body []byte, fields map[string]bool) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		doc = string(body)
+	}
+	data, err := marshal(redactValue(doc, fields), "")
+	return bytes.TrimSpace(data), err
+}
+
+func redactValue(v any, fields map[string]bool) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if fields[k] {
+				val[k] = RedactedValue
+				continue
+			}
+			val[k] = redactValue(child, fields)
+		}
+		return val
+	case []any:
+		for i := range val {
+			val[i] = redactValue(val[i], fields)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func goldenFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return name + ".golden.json"
+}
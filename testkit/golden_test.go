@@ -0,0 +1,72 @@
+package testkit_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-framework/engine"
+	"github.com/aatuh/pureapi-framework/testkit"
+)
+
+type fatalRecorder struct {
+	testing.TB
+	failed string
+}
+
+func (f *fatalRecorder) Helper() {}
+
+func (f *fatalRecorder) Fatalf(format string, args ...any) {
+	f.failed = fmt.Sprintf(format, args...)
+}
+
+func TestMatchSnapshotRecordsAndVerifies(t *testing.T) {
+	type in struct {
+		ID string `path:"id"`
+	}
+	type out struct {
+		ID        string    `json:"id"`
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	name := "first"
+	e := engine.NewEngine()
+	ep := engine.Endpoint[in, out](e, http.MethodGet, "/items/{id}",
+		func(ctx context.Context, input in) (out, error) {
+			return out{ID: input.ID, Name: name, CreatedAt: time.Now()}, nil
+		})
+
+	dir := t.TempDir()
+	cfg := testkit.SnapshotConfig{Dir: dir, RedactFields: []string{"created_at"}}
+	_, resp, err := testkit.Call(context.Background(), nil, ep, in{ID: "7"})
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	testkit.MatchSnapshot(t, "get item", resp, cfg)
+
+	golden, err := os.ReadFile(filepath.Join(dir, "get_item.golden.json"))
+	if err != nil {
+		t.Fatalf("expected golden file: %v", err)
+	}
+	for _, want := range []string{`"url": "/items/7"`, `"created_at": "<redacted>"`, `"X-Request-Id": "<redacted>"`} {
+		if !strings.Contains(string(golden), want) {
+			t.Fatalf("golden file missing %s:\n%s", want, golden)
+		}
+	}
+
+	_, resp, _ = testkit.Call(context.Background(), nil, ep, in{ID: "7"})
+	testkit.MatchSnapshot(t, "get item", resp, cfg)
+
+	name = "changed"
+	_, resp, _ = testkit.Call(context.Background(), nil, ep, in{ID: "7"})
+	rec := &fatalRecorder{TB: t}
+	testkit.MatchSnapshot(rec, "get item", resp, cfg)
+	if !strings.Contains(rec.failed, "does not match") {
+		t.Fatalf("expected snapshot mismatch, got %q", rec.failed)
+	}
+}
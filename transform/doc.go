@@ -0,0 +1,2 @@
+// Package transform rewrites rendered output payloads to keep older clients working.
+package transform
@@ -0,0 +1,133 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
+)
+
+// Op selects how a Rule rewrites the payload.
+type Op int
+
+const (
+	// OpRename moves a field to the sibling key To.
+	OpRename Op = iota
+	// OpDrop removes a field.
+	OpDrop
+	// OpWrap replaces a value with an object holding it under To, e.g. to
+	// turn a bare array into {"items": [...]}.
+	OpWrap
+)
+
+// Rule rewrites one location of the JSON payload. Field is a dotted path of
+// JSON names from the payload root ("" addresses the root for OpWrap); arrays
+// along the path are traversed element by element.
+type Rule struct {
+	Op    Op
+	Field string
+	To    string
+	// When restricts the rule, e.g. to a client; nil applies it always.
+	When func(ctx context.Context) bool
+}
+
+// Rules are applied in order, each seeing the result of the previous one.
+type Rules []Rule
+
+// Rename returns a rule renaming field to the sibling key to.
+func Rename(field, to string) Rule { return Rule{Op: OpRename, Field: field, To: to} }
+
+// Drop returns a rule removing field.
+func Drop(field string) Rule { return Rule{Op: OpDrop, Field: field} }
+
+// Wrap returns a rule nesting the value at field ("" for the root) under key.
+func Wrap(field, key string) Rule { return Rule{Op: OpWrap, Field: field, To: key} }
+
+// ForHeader matches requests whose header carries one of values, e.g. to
+// shim responses for an outdated client build.
+func ForHeader(name string, values ...string) func(ctx context.Context) bool {
+	return func(ctx context.Context) bool {
+		r := frameworkcontext.RequestFromContext(ctx)
+		if r == nil {
+			return false
+		}
+		got := r.Header.Get(name)
+		for _, value := range values {
+			if got == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Apply rewrites payload with the rules that match ctx. Payloads are
+// converted to their generic JSON form first; when no rule matches, payload
+// is returned unchanged.
+func (rs Rules) Apply(ctx context.Context, payload any) (any, error) {
+	var active []Rule
+	for _, rule := range rs {
+		if rule.When == nil || rule.When(ctx) {
+			active = append(active, rule)
+		}
+	}
+	if len(active) == 0 {
+		return payload, nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("transform: %w", err)
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("transform: %w", err)
+	}
+	for _, rule := range active {
+		var path []string
+		if rule.Field != "" {
+			path = strings.Split(rule.Field, ".")
+		}
+		generic = apply(generic, path, rule)
+	}
+	return generic, nil
+}
+
+// apply rewrites value at path and returns the possibly replaced value.
+func apply(value any, path []string, rule Rule) any {
+	if arr, ok := value.([]any); ok && len(path) > 0 {
+		for i := range arr {
+			arr[i] = apply(arr[i], path, rule)
+		}
+		return arr
+	}
+	if len(path) == 0 {
+		if rule.Op == OpWrap {
+			return map[string]any{rule.To: value}
+		}
+		return value
+	}
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+	child, ok := obj[path[0]]
+	if !ok {
+		return value
+	}
+	if len(path) > 1 {
+		obj[path[0]] = apply(child, path[1:], rule)
+		return obj
+	}
+	switch rule.Op {
+	case OpRename:
+		delete(obj, path[0])
+		obj[rule.To] = child
+	case OpDrop:
+		delete(obj, path[0])
+	case OpWrap:
+		obj[path[0]] = map[string]any{rule.To: child}
+	}
+	return obj
+}
@@ -0,0 +1,58 @@
+package transform_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	frameworkcontext "github.com/aatuh/pureapi-framework/context"
+	"github.com/aatuh/pureapi-framework/transform"
+)
+
+type line struct {
+	SKU      string `json:"sku"`
+	Internal string `json:"internal"`
+}
+
+type order struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Lines []line `json:"lines"`
+}
+
+func TestRules_Apply(t *testing.T) {
+	rules := transform.Rules{
+		transform.Rename("title", "name"),
+		transform.Drop("lines.internal"),
+		transform.Rename("lines.sku", "code"),
+		transform.Wrap("", "items"),
+	}
+	out, err := rules.Apply(context.Background(), []order{{ID: 1, Title: "a", Lines: []line{{SKU: "x", Internal: "secret"}}}})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	data, _ := json.Marshal(out)
+	want := `{"items":[{"id":1,"lines":[{"code":"x"}],"name":"a"}]}`
+	if string(data) != want {
+		t.Fatalf("unexpected payload:\n got %s\nwant %s", data, want)
+	}
+}
+
+func TestRules_WhenRestrictsRules(t *testing.T) {
+	rules := transform.Rules{{Op: transform.OpDrop, Field: "title", When: transform.ForHeader("X-Client", "legacy")}}
+	payload := order{ID: 1, Title: "a"}
+
+	out, err := rules.Apply(context.Background(), payload)
+	if _, ok := out.(order); err != nil || !ok {
+		t.Fatalf("expected payload untouched without a matching request, got %#v %v", out, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Client", "legacy")
+	out, _ = rules.Apply(frameworkcontext.WithRequest(context.Background(), req), payload)
+	if m, ok := out.(map[string]any); !ok || m["title"] != nil || m["id"] != float64(1) {
+		t.Fatalf("expected title dropped for legacy client, got %#v", out)
+	}
+}